@@ -19,6 +19,7 @@ package cmd
 import (
 	"io"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -71,7 +72,7 @@ func (b *builder) WithFlags(adder func(*pflag.FlagSet)) Builder {
 func (b *builder) ExactArgs(argCount int, action func(io.Writer, []string) error) *cobra.Command {
 	b.cmd.Args = cobra.ExactArgs(argCount)
 	b.cmd.RunE = func(cmd *cobra.Command, args []string) error {
-		return action(b.out, args)
+		return action(b.output(), args)
 	}
 	return &b.cmd
 }
@@ -79,7 +80,16 @@ func (b *builder) ExactArgs(argCount int, action func(io.Writer, []string) error
 func (b *builder) NoArgs(action func(io.Writer) error) *cobra.Command {
 	b.cmd.Args = cobra.NoArgs
 	b.cmd.RunE = func(cmd *cobra.Command, _ []string) error {
-		return action(b.out)
+		return action(b.output())
 	}
 	return &b.cmd
 }
+
+// output returns where the command prints. With the JSON log format, Skaffold's own
+// messages are printed as JSON records too, so that the output can be parsed line by line.
+func (b *builder) output() io.Writer {
+	if opts.Logs.Format == kubernetes.JSONLogFormat {
+		return kubernetes.NewMessageWriter(b.out)
+	}
+	return b.out
+}
@@ -162,6 +162,126 @@ var FlagRegistry = []Flag{
 		FlagAddMethod: "BoolVar",
 		DefinedOn:     []string{"dev", "debug"},
 	},
+	{
+		Name:          "tail-selector",
+		Usage:         "Also stream the logs of pods matching this label selector. Set multiple times for multiple selectors",
+		Value:         &opts.Logs.TailSelectors,
+		DefValue:      []string{},
+		FlagAddMethod: "StringArrayVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "tail-images",
+		Usage:         "Also stream the logs of pods running images matching these patterns, e.g. 'postgres:*'",
+		Value:         &opts.Logs.TailImages,
+		DefValue:      []string{},
+		FlagAddMethod: "StringSliceVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "tail-run",
+		Usage:         "Stream the logs of every pod deployed by this run, not only the ones running images built by Skaffold",
+		Value:         &opts.Logs.TailRun,
+		DefValue:      false,
+		FlagAddMethod: "BoolVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "tail-exclude-containers",
+		Usage:         "Never stream the logs of these containers, e.g. istio-proxy",
+		Value:         &opts.Logs.ExcludeContainers,
+		DefValue:      []string{},
+		FlagAddMethod: "StringSliceVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-images",
+		Usage:         "Only print the logs of containers whose image contains one of these names",
+		Value:         &opts.Logs.Images,
+		DefValue:      []string{},
+		FlagAddMethod: "StringSliceVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-containers",
+		Usage:         "Only print the logs of these containers",
+		Value:         &opts.Logs.Containers,
+		DefValue:      []string{},
+		FlagAddMethod: "StringSliceVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-selector",
+		Usage:         "Only print the logs of pods matching this label selector",
+		Value:         &opts.Logs.Selector,
+		DefValue:      "",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-include",
+		Usage:         "Only print the log lines matching this regular expression",
+		Value:         &opts.Logs.Include,
+		DefValue:      "",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-exclude",
+		Usage:         "Don't print the log lines matching this regular expression",
+		Value:         &opts.Logs.Exclude,
+		DefValue:      "",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-level",
+		Usage:         "Don't print JSON log lines below this level. One of trace, debug, info, warn, error or fatal",
+		Value:         &opts.Logs.Level,
+		DefValue:      "",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-fields",
+		Usage:         "Only print these fields of JSON log lines, as key=value pairs",
+		Value:         &opts.Logs.Fields,
+		DefValue:      []string{},
+		FlagAddMethod: "StringSliceVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-format",
+		Usage:         "Format of the logs: text, or json for one JSON object per line",
+		Value:         &opts.Logs.Format,
+		DefValue:      "text",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-dir",
+		Usage:         "Write the logs of the containers and the Skaffold events of each run to files under this directory",
+		Value:         &opts.Logs.Dir,
+		DefValue:      "",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-max-size",
+		Usage:         "Size in megabytes at which the files written to --log-dir are rotated",
+		Value:         &opts.Logs.MaxSize,
+		DefValue:      10,
+		FlagAddMethod: "IntVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
+	{
+		Name:          "log-max-files",
+		Usage:         "Number of rotated files kept for each file written to --log-dir",
+		Value:         &opts.Logs.MaxFiles,
+		DefValue:      3,
+		FlagAddMethod: "IntVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
 	// We need opts.Force and opts.ForceDev since cobra, overwrites the default value
 	// when registering the flag twice.
 	{
@@ -196,6 +316,14 @@ var FlagRegistry = []Flag{
 		FlagAddMethod: "BoolVar",
 		DefinedOn:     []string{"dev", "run", "debug"},
 	},
+	{
+		Name:          "delete-namespace",
+		Usage:         "Also delete the ephemeral namespace, with everything in it",
+		Value:         &opts.DeleteNamespace,
+		DefValue:      false,
+		FlagAddMethod: "BoolVar",
+		DefinedOn:     []string{"delete"},
+	},
 	{
 		Name:          "no-prune",
 		Usage:         "Skip removing images and containers built by Skaffold",
@@ -218,7 +346,7 @@ var FlagRegistry = []Flag{
 		Value:         &opts.PortForward.Enabled,
 		DefValue:      false,
 		FlagAddMethod: "BoolVar",
-		DefinedOn:     []string{"dev", "debug"},
+		DefinedOn:     []string{"dev", "run", "debug"},
 	},
 }
 
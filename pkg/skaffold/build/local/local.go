@@ -146,9 +146,14 @@ func (b *Builder) getImageIDForTag(ctx context.Context, tag string) (string, err
 }
 
 func (b *Builder) SyncMap(ctx context.Context, a *latest.Artifact) (map[string][]string, error) {
-	if a.DockerArtifact == nil {
+	switch {
+	case a.DockerArtifact != nil:
+		return docker.SyncMap(ctx, a.Workspace, a.DockerArtifact.DockerfilePath, a.DockerArtifact.BuildArgs, b.insecureRegistries)
+
+	case a.JibMavenArtifact != nil, a.JibGradleArtifact != nil:
+		return jib.GetSyncMap(ctx, a.Workspace, a)
+
+	default:
 		return nil, build.ErrSyncMapNotSupported{}
 	}
-
-	return docker.SyncMap(ctx, a.Workspace, a.DockerArtifact.DockerfilePath, a.DockerArtifact.BuildArgs, b.insecureRegistries)
 }
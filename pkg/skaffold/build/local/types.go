@@ -66,11 +66,14 @@ func NewBuilder(runCtx *runcontext.RunContext) (*Builder, error) {
 	}
 
 	var pushImages bool
-	if runCtx.Cfg.Build.LocalBuild.Push == nil {
+	switch {
+	case runCtx.Cfg.Build.LocalBuild.Push != nil:
+		pushImages = *runCtx.Cfg.Build.LocalBuild.Push
+	case runCtx.Cfg.Deploy.DockerDeploy != nil:
+		logrus.Debugln("push value not present, defaulting to false because images are run by the local Docker daemon")
+	default:
 		pushImages = !localCluster
 		logrus.Debugf("push value not present, defaulting to %t because localCluster is %t", pushImages, localCluster)
-	} else {
-		pushImages = *runCtx.Cfg.Build.LocalBuild.Push
 	}
 
 	return &Builder{
@@ -29,11 +29,34 @@ type PortForwardOptions struct {
 	ForwardPods bool
 }
 
+// LogOptions are options set by the command line for choosing, filtering,
+// formatting and archiving the logs of deployed containers
+type LogOptions struct {
+	Images     []string
+	Containers []string
+	Selector   string
+	Include    string
+	Exclude    string
+	Level      string
+	Fields     []string
+	Format     string
+
+	TailSelectors     []string
+	TailImages        []string
+	TailRun           bool
+	ExcludeContainers []string
+
+	Dir      string
+	MaxSize  int
+	MaxFiles int
+}
+
 // SkaffoldOptions are options that are set by command line arguments not included
 // in the config file itself
 type SkaffoldOptions struct {
 	ConfigurationFile  string
 	Cleanup            bool
+	DeleteNamespace    bool
 	Notification       bool
 	Tail               bool
 	TailDev            bool
@@ -45,6 +68,7 @@ type SkaffoldOptions struct {
 	NoPrune            bool
 	NoPruneChildren    bool
 	PortForward        PortForwardOptions
+	Logs               LogOptions
 	CustomTag          string
 	Namespace          string
 	CacheFile          string
@@ -41,6 +41,10 @@ const (
 
 	DefaultKustomizationPath = "."
 
+	DefaultEphemeralNamespaceTemplate = "{{.USER}}-{{.GIT_BRANCH}}"
+
+	DefaultDockerNetwork = "skaffold-network"
+
 	DefaultKanikoImage                  = "gcr.io/kaniko-project/executor:v0.10.0@sha256:78d44ec4e9cb5545d7f85c1924695c89503ded86a59f92c7ae658afa3cff5400"
 	DefaultKanikoSecretName             = "kaniko-secret"
 	DefaultKanikoTimeout                = "20m"
@@ -103,13 +107,19 @@ var DefaultKubectlManifests = []string{"k8s/*.yaml"}
 var LatestDownloadURL = fmt.Sprintf("https://storage.googleapis.com/skaffold/releases/latest/skaffold-%s-%s", runtime.GOOS, runtime.GOARCH)
 
 var Labels = struct {
-	TagPolicy        string
-	Deployer         string
-	Builder          string
-	DockerAPIVersion string
+	TagPolicy          string
+	Deployer           string
+	Builder            string
+	DockerAPIVersion   string
+	Owner              string
+	EphemeralNamespace string
+	RunID              string
 }{
-	TagPolicy:        "skaffold.dev/tag-policy",
-	Deployer:         "skaffold.dev/deployer",
-	Builder:          "skaffold.dev/builder",
-	DockerAPIVersion: "skaffold.dev/docker-api-version",
+	TagPolicy:          "skaffold.dev/tag-policy",
+	Deployer:           "skaffold.dev/deployer",
+	Builder:            "skaffold.dev/builder",
+	DockerAPIVersion:   "skaffold.dev/docker-api-version",
+	Owner:              "skaffold.dev/owner",
+	EphemeralNamespace: "skaffold.dev/ephemeral-namespace",
+	RunID:              "skaffold.dev/run-id",
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+)
+
+// DeployerMux forwards all method calls to the deployers it contains.
+// It is used to deploy the same artifacts to multiple kubectl contexts.
+type DeployerMux []Deployer
+
+// Labels merges the labels of all the deployers.
+func (m DeployerMux) Labels() map[string]string {
+	labels := make(map[string]string)
+	for _, deployer := range m {
+		copyMap(labels, deployer.Labels())
+	}
+	return labels
+}
+
+// Deploy deploys with each deployer in turn and stops at the first error.
+func (m DeployerMux) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
+	for _, deployer := range m {
+		if err := deployer.Deploy(ctx, out, builds, labellers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dependencies returns the deduplicated list of dependencies of all the deployers.
+func (m DeployerMux) Dependencies() ([]string, error) {
+	deps := map[string]bool{}
+	for _, deployer := range m {
+		result, err := deployer.Dependencies()
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range result {
+			deps[dep] = true
+		}
+	}
+
+	var dependencies []string
+	for dep := range deps {
+		dependencies = append(dependencies, dep)
+	}
+	sort.Strings(dependencies)
+	return dependencies, nil
+}
+
+// Cleanup cleans up with all the deployers, even if some of them fail,
+// and returns the first error.
+func (m DeployerMux) Cleanup(ctx context.Context, out io.Writer) error {
+	var firstErr error
+	for _, deployer := range m {
+		if err := deployer.Cleanup(ctx, out); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type mockDeployer struct {
+	kubeContext string
+	labels      map[string]string
+	deps        []string
+	deployErr   error
+	cleanupErr  error
+
+	calls *[]string
+}
+
+func (m *mockDeployer) Labels() map[string]string {
+	return m.labels
+}
+
+func (m *mockDeployer) Deploy(context.Context, io.Writer, []build.Artifact, []Labeller) error {
+	*m.calls = append(*m.calls, "deploy "+m.kubeContext)
+	return m.deployErr
+}
+
+func (m *mockDeployer) Dependencies() ([]string, error) {
+	return m.deps, nil
+}
+
+func (m *mockDeployer) Cleanup(context.Context, io.Writer) error {
+	*m.calls = append(*m.calls, "cleanup "+m.kubeContext)
+	return m.cleanupErr
+}
+
+func TestDeployerMuxDeploy(t *testing.T) {
+	tests := []struct {
+		description   string
+		deployErr     error
+		shouldErr     bool
+		expectedCalls []string
+	}{
+		{
+			description:   "deploy to all contexts",
+			expectedCalls: []string{"deploy cluster1", "deploy cluster2"},
+		},
+		{
+			description:   "stop at first error",
+			deployErr:     errors.New("BUG"),
+			shouldErr:     true,
+			expectedCalls: []string{"deploy cluster1"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var calls []string
+			mux := DeployerMux{
+				&mockDeployer{kubeContext: "cluster1", deployErr: test.deployErr, calls: &calls},
+				&mockDeployer{kubeContext: "cluster2", calls: &calls},
+			}
+
+			err := mux.Deploy(context.Background(), ioutil.Discard, nil, nil)
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestDeployerMuxCleanup(t *testing.T) {
+	var calls []string
+	mux := DeployerMux{
+		&mockDeployer{kubeContext: "cluster1", cleanupErr: errors.New("BUG"), calls: &calls},
+		&mockDeployer{kubeContext: "cluster2", calls: &calls},
+	}
+
+	err := mux.Cleanup(context.Background(), ioutil.Discard)
+
+	testutil.CheckErrorAndDeepEqual(t, true, err, []string{"cleanup cluster1", "cleanup cluster2"}, calls)
+}
+
+func TestDeployerMuxDependencies(t *testing.T) {
+	mux := DeployerMux{
+		&mockDeployer{deps: []string{"k8s/b.yaml", "k8s/a.yaml"}},
+		&mockDeployer{deps: []string{"k8s/a.yaml", "k8s/c.yaml"}},
+	}
+
+	deps, err := mux.Dependencies()
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, []string{"k8s/a.yaml", "k8s/b.yaml", "k8s/c.yaml"}, deps)
+}
+
+func TestDeployerMuxLabels(t *testing.T) {
+	mux := DeployerMux{
+		&mockDeployer{labels: map[string]string{"skaffold.dev/deployer": "kubectl"}},
+		&mockDeployer{labels: map[string]string{"skaffold.dev/deployer": "kubectl", "skaffold.dev/other": "value"}},
+	}
+
+	testutil.CheckDeepEqual(t, map[string]string{"skaffold.dev/deployer": "kubectl", "skaffold.dev/other": "value"}, mux.Labels())
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+)
+
+// DockerDeployer runs the artifacts as containers on the local Docker daemon.
+type DockerDeployer struct {
+	*latest.DockerDeploy
+
+	workingDir  string
+	localDocker docker.LocalDaemon
+}
+
+// NewDockerDeployer returns a new DockerDeployer for a DeployConfig filled
+// with the containers to run on the given Docker daemon.
+func NewDockerDeployer(runCtx *runcontext.RunContext, localDocker docker.LocalDaemon) *DockerDeployer {
+	return &DockerDeployer{
+		DockerDeploy: runCtx.Cfg.Deploy.DockerDeploy,
+		workingDir:   runCtx.WorkingDir,
+		localDocker:  localDocker,
+	}
+}
+
+// DockerContainerLabels are the labels that select the containers run by the DockerDeployer.
+func DockerContainerLabels() map[string]string {
+	return map[string]string{
+		constants.Labels.Deployer: "docker",
+	}
+}
+
+func (d *DockerDeployer) Labels() map[string]string {
+	return DockerContainerLabels()
+}
+
+// Deploy replaces the containers with new ones running the latest builds.
+func (d *DockerDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
+	event.DeployInProgress("")
+
+	if err := d.deploy(ctx, out, builds, merge(labellers...)); err != nil {
+		event.DeployFailed("", err)
+		return err
+	}
+
+	event.DeployComplete("")
+	return nil
+}
+
+func (d *DockerDeployer) deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labels map[string]string) error {
+	if err := d.localDocker.CreateNetwork(ctx, d.Network, labels); err != nil {
+		return err
+	}
+
+	for _, c := range d.Containers {
+		image := imageTag(c.Image, builds)
+
+		config, hostConfig, err := d.containerConfig(c, image, labels)
+		if err != nil {
+			return errors.Wrapf(err, "configuring container %s", c.Name)
+		}
+
+		if err := d.localDocker.RemoveContainer(ctx, c.Name); err != nil {
+			return err
+		}
+
+		if _, err := d.localDocker.RunContainer(ctx, c.Name, config, hostConfig, d.Network); err != nil {
+			return err
+		}
+
+		color.Default.Fprintf(out, "Running %s in container %s\n", image, c.Name)
+	}
+
+	return nil
+}
+
+// imageTag returns the tag of the build for a given image name,
+// or the image name itself if it wasn't built.
+func imageTag(image string, builds []build.Artifact) string {
+	for _, b := range builds {
+		if b.ImageName == image {
+			return b.Tag
+		}
+	}
+	return image
+}
+
+func (d *DockerDeployer) containerConfig(c latest.DockerContainer, image string, labels map[string]string) (*container.Config, *container.HostConfig, error) {
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(c.Ports)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing ports")
+	}
+
+	var env []string
+	for k, v := range c.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(env)
+
+	var binds []string
+	for _, volume := range c.Volumes {
+		binds = append(binds, d.bind(volume))
+	}
+
+	config := &container.Config{
+		Image:        image,
+		Cmd:          c.Args,
+		Env:          env,
+		ExposedPorts: exposedPorts,
+		Labels:       labels,
+	}
+	hostConfig := &container.HostConfig{
+		PortBindings: portBindings,
+		Binds:        binds,
+	}
+
+	return config, hostConfig, nil
+}
+
+// bind makes the host path of a volume absolute. Named volumes are left untouched.
+func (d *DockerDeployer) bind(volume string) string {
+	parts := strings.SplitN(volume, ":", 2)
+	if len(parts) < 2 {
+		return volume
+	}
+
+	host := parts[0]
+	if filepath.IsAbs(host) || !strings.HasPrefix(host, ".") && !strings.Contains(host, "/") {
+		return volume
+	}
+
+	return filepath.Join(d.workingDir, host) + ":" + parts[1]
+}
+
+// Dependencies lists no files, the containers are fully described in the Skaffold configuration.
+func (d *DockerDeployer) Dependencies() ([]string, error) {
+	return nil, nil
+}
+
+// Cleanup removes the containers and their network.
+func (d *DockerDeployer) Cleanup(ctx context.Context, out io.Writer) error {
+	for _, c := range d.Containers {
+		if err := d.localDocker.RemoveContainer(ctx, c.Name); err != nil {
+			return err
+		}
+	}
+
+	return d.localDocker.RemoveNetwork(ctx, d.Network)
+}
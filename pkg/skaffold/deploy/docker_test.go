@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+)
+
+type fakeLocalDaemon struct {
+	docker.LocalDaemon
+
+	networks   []string
+	removed    []string
+	configs    map[string]*container.Config
+	hostConfig map[string]*container.HostConfig
+}
+
+func (f *fakeLocalDaemon) CreateNetwork(_ context.Context, name string, _ map[string]string) error {
+	f.networks = append(f.networks, name)
+	return nil
+}
+
+func (f *fakeLocalDaemon) RemoveNetwork(_ context.Context, name string) error {
+	f.removed = append(f.removed, "network "+name)
+	return nil
+}
+
+func (f *fakeLocalDaemon) RemoveContainer(_ context.Context, name string) error {
+	f.removed = append(f.removed, name)
+	return nil
+}
+
+func (f *fakeLocalDaemon) RunContainer(_ context.Context, name string, config *container.Config, hostConfig *container.HostConfig, _ string) (string, error) {
+	f.configs[name] = config
+	f.hostConfig[name] = hostConfig
+	return name + "-id", nil
+}
+
+func newFakeDockerDeployer(t *testutil.T, cfg *latest.DockerDeploy) (*DockerDeployer, *fakeLocalDaemon) {
+	fake := &fakeLocalDaemon{
+		configs:    map[string]*container.Config{},
+		hostConfig: map[string]*container.HostConfig{},
+	}
+	runCtx := &runcontext.RunContext{
+		WorkingDir: "/project",
+		Cfg: &latest.Pipeline{
+			Deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					DockerDeploy: cfg,
+				},
+			},
+		},
+		Opts: &config.SkaffoldOptions{},
+	}
+	event.InitializeState(runCtx)
+
+	return NewDockerDeployer(runCtx, fake), fake
+}
+
+func TestDockerDeploy(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		deployer, fake := newFakeDockerDeployer(t, &latest.DockerDeploy{
+			Network: "network",
+			Containers: []latest.DockerContainer{
+				{
+					Name:    "web",
+					Image:   "leeroy-web",
+					Args:    []string{"--verbose"},
+					Ports:   []string{"8080:80"},
+					Env:     map[string]string{"B": "2", "A": "1"},
+					Volumes: []string{"./data:/data", "/tmp:/tmp:ro", "cache:/cache"},
+				},
+				{
+					Name:  "redis",
+					Image: "redis:5",
+				},
+			},
+		})
+
+		err := deployer.Deploy(context.Background(), ioutil.Discard, []build.Artifact{{ImageName: "leeroy-web", Tag: "leeroy-web:v1"}}, []Labeller{deployer})
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"network"}, fake.networks)
+		t.CheckDeepEqual([]string{"web", "redis"}, fake.removed)
+		t.CheckDeepEqual(&container.Config{
+			Image:        "leeroy-web:v1",
+			Cmd:          []string{"--verbose"},
+			Env:          []string{"A=1", "B=2"},
+			ExposedPorts: nat.PortSet{"80/tcp": {}},
+			Labels:       map[string]string{"skaffold.dev/deployer": "docker"},
+		}, fake.configs["web"])
+		t.CheckDeepEqual(&container.HostConfig{
+			PortBindings: nat.PortMap{"80/tcp": []nat.PortBinding{{HostPort: "8080"}}},
+			Binds:        []string{"/project/data:/data", "/tmp:/tmp:ro", "cache:/cache"},
+		}, fake.hostConfig["web"])
+		t.CheckDeepEqual("redis:5", fake.configs["redis"].Image)
+	})
+}
+
+func TestDockerDeployInvalidPort(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		deployer, _ := newFakeDockerDeployer(t, &latest.DockerDeploy{
+			Containers: []latest.DockerContainer{{Name: "web", Image: "web", Ports: []string{"invalid:port"}}},
+		})
+
+		err := deployer.Deploy(context.Background(), ioutil.Discard, nil, nil)
+
+		t.CheckError(true, err)
+	})
+}
+
+func TestDockerCleanup(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		deployer, fake := newFakeDockerDeployer(t, &latest.DockerDeploy{
+			Network: "network",
+			Containers: []latest.DockerContainer{
+				{Name: "web", Image: "leeroy-web"},
+				{Name: "redis", Image: "redis:5"},
+			},
+		})
+
+		err := deployer.Cleanup(context.Background(), ioutil.Discard)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"web", "redis", "network network"}, fake.removed)
+	})
+}
@@ -22,9 +22,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,35 +34,60 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/blang/semver"
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	yaml "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	// helm32Version is the first Helm version supporting `--create-namespace`.
+	helm32Version = semver.MustParse("3.2.0")
+
+	// versionRegex extracts the semantic version from `helm version --client` output,
+	// both `Client: &version.Version{SemVer:"v2.14.1", ...}` for Helm 2
+	// and `version.BuildInfo{Version:"v3.0.0", ...}` for Helm 3.
+	versionRegex = regexp.MustCompile(`v(\d+\.\d+\.\d+[\w.\-+]*)`)
 )
 
 type HelmDeployer struct {
 	*latest.HelmDeploy
 
-	kubeContext string
-	namespace   string
-	defaultRepo string
-	forceDeploy bool
+	kubeContext        string
+	namespace          string
+	defaultRepo        string
+	forceDeploy        bool
+	insecureRegistries map[string]bool
+	validator          *kubectl.Validator
+
+	// bV is the cached version of the helm binary.
+	bV *semver.Version
+
+	// kubectl holds, per release name, the CLI used to deploy rendered releases.
+	kubectl map[string]*kubectl.CLI
 }
 
 // NewHelmDeployer returns a new HelmDeployer for a DeployConfig filled
 // with the needed configuration for `helm`
 func NewHelmDeployer(runCtx *runcontext.RunContext) *HelmDeployer {
 	return &HelmDeployer{
-		HelmDeploy:  runCtx.Cfg.Deploy.HelmDeploy,
-		kubeContext: runCtx.KubeContext,
-		namespace:   runCtx.Opts.Namespace,
-		defaultRepo: runCtx.DefaultRepo,
-		forceDeploy: runCtx.Opts.ForceDeploy(),
+		HelmDeploy:         runCtx.Cfg.Deploy.HelmDeploy,
+		kubeContext:        runCtx.KubeContext,
+		namespace:          runCtx.Opts.Namespace,
+		defaultRepo:        runCtx.DefaultRepo,
+		forceDeploy:        runCtx.Opts.ForceDeploy(),
+		insecureRegistries: runCtx.InsecureRegistries,
+		validator:          kubectl.NewValidator(runCtx.Cfg.Deploy.Validation, runCtx.WorkingDir),
+		kubectl:            map[string]*kubectl.CLI{},
 	}
 }
 
@@ -70,27 +97,62 @@ func (h *HelmDeployer) Labels() map[string]string {
 	}
 }
 
+// HelmReleaseSelectors select the pods of the given releases.
+// Helm doesn't label the pods with the run id, so they are selected by the
+// labels that charts conventionally set to the release name.
+func HelmReleaseSelectors(releases []latest.HelmRelease) []labels.Selector {
+	var selectors []labels.Selector
+	for _, r := range releases {
+		releaseName, err := evaluateReleaseName(r.Name)
+		if err != nil {
+			continue
+		}
+
+		selectors = append(selectors,
+			labels.SelectorFromSet(map[string]string{"release": releaseName}),
+			labels.SelectorFromSet(map[string]string{"app.kubernetes.io/instance": releaseName}),
+		)
+	}
+	return selectors
+}
+
 func (h *HelmDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
 	var dRes []Artifact
 
-	event.DeployInProgress()
+	event.DeployInProgress(h.kubeContext)
 
-	for _, r := range h.Releases {
-		results, err := h.deployRelease(ctx, out, r, builds)
+	helmVersion, err := h.binVer(ctx)
+	if err != nil {
+		event.DeployFailed(h.kubeContext, err)
+		return errors.Wrap(err, "getting helm version")
+	}
+
+	// Every release is prepared, and validated, before any of them is deployed.
+	rendered := make([]kubectl.ManifestList, len(h.Releases))
+	for i, r := range h.Releases {
+		if rendered[i], err = h.prepareRelease(ctx, out, helmVersion, r, builds); err != nil {
+			releaseName, _ := evaluateReleaseName(r.Name)
+
+			event.DeployFailed(h.kubeContext, err)
+			return errors.Wrapf(err, "deploying %s", releaseName)
+		}
+	}
+
+	for i, r := range h.Releases {
+		results, err := h.deployRelease(ctx, out, helmVersion, r, rendered[i], builds, labellers)
 		if err != nil {
 			releaseName, _ := evaluateReleaseName(r.Name)
 
-			event.DeployFailed(err)
+			event.DeployFailed(h.kubeContext, err)
 			return errors.Wrapf(err, "deploying %s", releaseName)
 		}
 
 		dRes = append(dRes, results...)
 	}
 
-	event.DeployComplete()
+	event.DeployComplete(h.kubeContext)
 
-	labels := merge(labellers...)
-	labelDeployResults(labels, dRes)
+	labelDeployResults(h.kubeContext, merge(labellers...), dRes)
 
 	return nil
 }
@@ -133,8 +195,13 @@ func (h *HelmDeployer) Dependencies() ([]string, error) {
 
 // Cleanup deletes what was deployed by calling Deploy.
 func (h *HelmDeployer) Cleanup(ctx context.Context, out io.Writer) error {
+	helmVersion, err := h.binVer(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting helm version")
+	}
+
 	for _, r := range h.Releases {
-		if err := h.deleteRelease(ctx, out, r); err != nil {
+		if err := h.deleteRelease(ctx, out, helmVersion, r); err != nil {
 			releaseName, _ := evaluateReleaseName(r.Name)
 			return errors.Wrapf(err, "deploying %s", releaseName)
 		}
@@ -143,6 +210,11 @@ func (h *HelmDeployer) Cleanup(ctx context.Context, out io.Writer) error {
 }
 
 func (h *HelmDeployer) helm(ctx context.Context, out io.Writer, useSecrets bool, arg ...string) error {
+	return h.helmOut(ctx, out, out, useSecrets, arg...)
+}
+
+// helmOut runs helm with separate writers for its standard output and standard error.
+func (h *HelmDeployer) helmOut(ctx context.Context, stdout, stderr io.Writer, useSecrets bool, arg ...string) error {
 	args := append([]string{"--kube-context", h.kubeContext}, arg...)
 	args = append(args, h.Flags.Global...)
 
@@ -151,59 +223,164 @@ func (h *HelmDeployer) helm(ctx context.Context, out io.Writer, useSecrets bool,
 	}
 
 	cmd := exec.CommandContext(ctx, "helm", args...)
-	cmd.Stdout = out
-	cmd.Stderr = out
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	return util.RunCmd(cmd)
 }
 
-func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact) ([]Artifact, error) {
-	isInstalled := true
+// binVer returns the version of the helm binary found in PATH.
+// The version is queried once and then cached.
+func (h *HelmDeployer) binVer(ctx context.Context) (semver.Version, error) {
+	if h.bV != nil {
+		return *h.bV, nil
+	}
+
+	var b bytes.Buffer
+	if err := h.helm(ctx, &b, false, "version", "--client"); err != nil {
+		return semver.Version{}, errors.Wrapf(err, "helm version command failed %q", b.String())
+	}
+
+	raw := b.String()
+	matches := versionRegex.FindStringSubmatch(raw)
+	if len(matches) == 0 {
+		return semver.Version{}, fmt.Errorf("unable to parse helm version: %q", raw)
+	}
+
+	v, err := semver.ParseTolerant(matches[1])
+	if err != nil {
+		return semver.Version{}, errors.Wrapf(err, "parsing helm version %q", matches[1])
+	}
+
+	logrus.Debugf("Using helm version %s", v)
+	h.bV = &v
+	return v, nil
+}
+
+// isHelm3 returns true if the given helm version uses Helm 3 semantics:
+// no tiller, namespace-scoped releases and `helm uninstall`.
+func isHelm3(v semver.Version) bool {
+	return v.Major >= 3
+}
+
+// releaseNamespace returns the namespace a release should be deployed to.
+// The `--namespace` command line flag takes precedence over the release configuration.
+func (h *HelmDeployer) releaseNamespace(r latest.HelmRelease) string {
+	if h.namespace != "" {
+		return h.namespace
+	}
+	return r.Namespace
+}
+
+// namespaceArgs returns the `--namespace` flag for namespace-scoped helm commands.
+func namespaceArgs(namespace string) []string {
+	if namespace == "" {
+		return nil
+	}
+	return []string{"--namespace", namespace}
+}
 
+// prepareRelease builds the dependencies of a release. The release is also rendered when manifest
+// transforms are registered, or when its manifests are validated. Nil manifests are returned otherwise.
+func (h *HelmDeployer) prepareRelease(ctx context.Context, out io.Writer, helmVersion semver.Version, r latest.HelmRelease, builds []build.Artifact) (kubectl.ManifestList, error) {
 	releaseName, err := evaluateReleaseName(r.Name)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse the release name template")
 	}
-	if err := h.helm(ctx, out, false, "get", releaseName); err != nil {
-		color.Red.Fprintf(out, "Helm release %s not installed. Installing...\n", releaseName)
-		isInstalled = false
+
+	if err := h.buildDependencies(ctx, out, r); err != nil {
+		return nil, err
 	}
-	params, err := h.joinTagsToBuildResult(builds, r.Values)
+
+	transformed := len(manifestTransforms) > 0
+	if !transformed {
+		if h.validator == nil {
+			return nil, nil
+		}
+		if r.Remote && !isHelm3(helmVersion) {
+			warnings.Printf("helm %s can't render remote chart %s, its manifests are not validated", helmVersion, r.ChartPath)
+			return nil, nil
+		}
+	}
+
+	manifests, err := h.renderRelease(ctx, out, helmVersion, r, releaseName, builds)
 	if err != nil {
-		return nil, errors.Wrap(err, "matching build results to chart values")
+		return nil, errors.Wrap(err, "rendering release")
 	}
 
-	var setOpts []string
-	for k, v := range params {
-		setOpts = append(setOpts, "--set")
-		if r.ImageStrategy.HelmImageConfig.HelmConventionConfig != nil {
-			dockerRef, err := docker.ParseReference(v.Tag)
-			if err != nil {
-				return nil, errors.Wrapf(err, "cannot parse the docker image reference %s", v.Tag)
-			}
-			imageRepositoryTag := fmt.Sprintf("%s.repository=%s,%s.tag=%s", k, dockerRef.BaseName, k, dockerRef.Tag)
-			setOpts = append(setOpts, imageRepositoryTag)
-		} else {
-			setOpts = append(setOpts, fmt.Sprintf("%s=%s", k, v.Tag))
-		}
+	if err := h.validator.Validate(releaseName, manifests); err != nil {
+		return nil, errors.Wrap(err, "validating manifests")
 	}
 
+	return manifests, nil
+}
+
+// buildDependencies runs `helm dep build` on the chart of a release.
+func (h *HelmDeployer) buildDependencies(ctx context.Context, out io.Writer, r latest.HelmRelease) error {
 	// Dependency builds should be skipped when trying to install a chart
 	// with local dependencies in the chart folder, e.g. the istio helm chart.
 	// This decision is left to the user.
 	// Dep builds should also be skipped whenever a remote chart path is specified.
-	if !r.SkipBuildDependencies && !r.Remote {
-		// First build dependencies.
-		logrus.Infof("Building helm dependencies...")
-		if err := h.helm(ctx, out, false, "dep", "build", r.ChartPath); err != nil {
-			return nil, errors.Wrap(err, "building helm dependencies")
-		}
+	if r.SkipBuildDependencies || r.Remote {
+		return nil
+	}
+
+	logrus.Infof("Building helm dependencies...")
+	if err := h.helm(ctx, out, false, "dep", "build", r.ChartPath); err != nil {
+		return errors.Wrap(err, "building helm dependencies")
+	}
+	return nil
+}
+
+// isInstalled tells if helm knows a release. Releases deployed with manifest transforms are not.
+func (h *HelmDeployer) isInstalled(ctx context.Context, out io.Writer, helmVersion semver.Version, namespace string, releaseName string) bool {
+	args := []string{"get", releaseName}
+	if isHelm3(helmVersion) {
+		args = append([]string{"get", "all", releaseName}, namespaceArgs(namespace)...)
+	}
+	return h.helm(ctx, out, false, args...) == nil
+}
+
+// deployRelease installs or upgrades a release. When manifest transforms are registered,
+// the manifests rendered by prepareRelease are deployed instead.
+func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, helmVersion semver.Version, r latest.HelmRelease, rendered kubectl.ManifestList, builds []build.Artifact, labellers []Labeller) ([]Artifact, error) {
+	releaseName, err := evaluateReleaseName(r.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse the release name template")
+	}
+
+	if len(manifestTransforms) > 0 {
+		// Manifest transforms can only be applied to rendered manifests.
+		return nil, h.deployRendered(ctx, out, r, releaseName, rendered, builds, labellers)
+	}
+
+	helm3 := isHelm3(helmVersion)
+	ns := h.releaseNamespace(r)
+
+	isInstalled := h.isInstalled(ctx, out, helmVersion, ns, releaseName)
+	if !isInstalled {
+		color.Red.Fprintf(out, "Helm release %s not installed. Installing...\n", releaseName)
 	}
 
 	var args []string
 	if !isInstalled {
-		args = append(args, "install", "--name", releaseName)
+		if helm3 {
+			args = append(args, "install", releaseName)
+		} else {
+			args = append(args, "install", "--name", releaseName)
+		}
 		args = append(args, h.Flags.Install...)
+
+		if r.CreateNamespace {
+			switch {
+			case !helm3:
+				logrus.Debugf("helm %s creates namespaces on install, ignoring createNamespace", helmVersion)
+			case helmVersion.LT(helm32Version):
+				return nil, fmt.Errorf("createNamespace requires helm %s or later, found %s", helm32Version, helmVersion)
+			default:
+				args = append(args, "--create-namespace")
+			}
+		}
 	} else {
 		args = append(args, "upgrade", releaseName)
 		args = append(args, h.Flags.Upgrade...)
@@ -211,10 +388,133 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 			args = append(args, "--force")
 		}
 		if r.RecreatePods {
-			args = append(args, "--recreate-pods")
+			if helm3 {
+				logrus.Warnf("recreatePods is not supported by helm %s, ignoring", helmVersion)
+			} else {
+				args = append(args, "--recreate-pods")
+			}
+		}
+	}
+
+	chartArgs, cleanup, err := h.chartArgs(ctx, out, r, builds, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	args = append(args, chartArgs...)
+
+	if r.Wait {
+		args = append(args, "--wait")
+	}
+
+	helmErr := h.helm(ctx, out, r.UseHelmSecrets, args...)
+	return h.getDeployResults(ctx, helmVersion, ns, releaseName), helmErr
+}
+
+// deployRendered applies the manifest transforms and the labels to the manifests
+// rendered with `helm template`, and deploys the result with `kubectl apply`.
+// This is how releases are deployed when manifest transforms are registered, eg. by `skaffold debug`.
+// As with `helm --wait`, `wait` waits for the workloads of the release to be rolled out.
+func (h *HelmDeployer) deployRendered(ctx context.Context, out io.Writer, r latest.HelmRelease, releaseName string, manifests kubectl.ManifestList, builds []build.Artifact, labellers []Labeller) error {
+	if len(manifests) == 0 {
+		return nil
+	}
+
+	manifests, err := setLabels(manifests, labellers)
+	if err != nil {
+		return errors.Wrap(err, "setting labels in manifests")
+	}
+
+	manifests, err = applyManifestTransforms(manifests, builds, h.insecureRegistries)
+	if err != nil {
+		return err
+	}
+
+	cli := h.kubectlFor(r, releaseName)
+	if err := cli.Apply(ctx, out, manifests); err != nil {
+		return err
+	}
+
+	if r.Wait {
+		return cli.WaitForRollout(ctx, out, manifests)
+	}
+	return nil
+}
+
+// renderRelease runs `helm template` on a release and returns the rendered manifests.
+func (h *HelmDeployer) renderRelease(ctx context.Context, out io.Writer, helmVersion semver.Version, r latest.HelmRelease, releaseName string, builds []build.Artifact) (kubectl.ManifestList, error) {
+	helm3 := isHelm3(helmVersion)
+	if r.Remote && !helm3 {
+		return nil, fmt.Errorf("helm %s can't render remote chart %s, helm 3 is required", helmVersion, r.ChartPath)
+	}
+
+	args := []string{"template", "--name", releaseName}
+	if helm3 {
+		args = []string{"template", releaseName}
+	}
+
+	// `helm template` only supports `--version` with Helm 3.
+	chartArgs, cleanup, err := h.chartArgs(ctx, out, r, builds, helm3)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	args = append(args, chartArgs...)
+
+	var rendered bytes.Buffer
+	if err := h.helmOut(ctx, &rendered, out, r.UseHelmSecrets, args...); err != nil {
+		return nil, err
+	}
+
+	var manifests kubectl.ManifestList
+	manifests.Append(rendered.Bytes())
+	return manifests, nil
+}
+
+// kubectlFor returns the kubectl CLI used to apply and delete the rendered manifests of a release.
+// Each release has its own CLI so that only modified manifests are re-applied.
+func (h *HelmDeployer) kubectlFor(r latest.HelmRelease, releaseName string) *kubectl.CLI {
+	if cli, found := h.kubectl[releaseName]; found {
+		return cli
+	}
+
+	cli := &kubectl.CLI{
+		Namespace:   h.releaseNamespace(r),
+		KubeContext: h.kubeContext,
+		ForceDeploy: h.forceDeploy,
+	}
+	h.kubectl[releaseName] = cli
+	return cli
+}
+
+// chartArgs returns the arguments common to `helm install`, `helm upgrade` and `helm template`:
+// the chart, its namespace, values and value overrides.
+// The returned cleanup function must be called once the helm command has run.
+func (h *HelmDeployer) chartArgs(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact, withVersion bool) ([]string, func(), error) {
+	noop := func() {}
+
+	params, err := h.joinTagsToBuildResult(builds, r.Values)
+	if err != nil {
+		return nil, noop, errors.Wrap(err, "matching build results to chart values")
+	}
+
+	var setOpts []string
+	for k, v := range params {
+		setOpts = append(setOpts, "--set")
+		if r.ImageStrategy.HelmImageConfig.HelmConventionConfig != nil {
+			dockerRef, err := docker.ParseReference(v.Tag)
+			if err != nil {
+				return nil, noop, errors.Wrapf(err, "cannot parse the docker image reference %s", v.Tag)
+			}
+			imageRepositoryTag := fmt.Sprintf("%s.repository=%s,%s.tag=%s", k, dockerRef.BaseName, k, dockerRef.Tag)
+			setOpts = append(setOpts, imageRepositoryTag)
+		} else {
+			setOpts = append(setOpts, fmt.Sprintf("%s=%s", k, v.Tag))
 		}
 	}
 
+	var args []string
+
 	// There are 2 strategies:
 	// 1) Deploy chart directly from filesystem path or from repository
 	//    (like stable/kubernetes-dashboard). Version only applies to a
@@ -223,42 +523,37 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 	//    that packaged chart. This way user can apply any version and appVersion
 	//    for the chart.
 	if r.Packaged == nil {
-		if r.Version != "" {
+		if r.Version != "" && withVersion {
 			args = append(args, "--version", r.Version)
 		}
 		args = append(args, r.ChartPath)
 	} else {
 		chartPath, err := h.packageChart(ctx, r)
 		if err != nil {
-			return nil, errors.WithMessage(err, "cannot package chart")
+			return nil, noop, errors.WithMessage(err, "cannot package chart")
 		}
 		args = append(args, chartPath)
 	}
 
-	var ns string
-	if h.namespace != "" {
-		ns = h.namespace
-	} else if r.Namespace != "" {
-		ns = r.Namespace
-	}
-	if ns != "" {
-		args = append(args, "--namespace", ns)
-	}
+	args = append(args, namespaceArgs(h.releaseNamespace(r))...)
+
+	cleanup := noop
 	if len(r.Overrides.Values) != 0 {
 		overrides, err := yaml.Marshal(r.Overrides)
 		if err != nil {
-			return nil, errors.Wrap(err, "cannot marshal overrides to create overrides values.yaml")
+			return nil, noop, errors.Wrap(err, "cannot marshal overrides to create overrides values.yaml")
 		}
 		overridesFile, err := os.Create(constants.HelmOverridesFilename)
 		if err != nil {
-			return nil, errors.Wrapf(err, "cannot create file %s", constants.HelmOverridesFilename)
+			return nil, noop, errors.Wrapf(err, "cannot create file %s", constants.HelmOverridesFilename)
 		}
-		defer func() {
+		cleanup = func() {
 			overridesFile.Close()
 			os.Remove(constants.HelmOverridesFilename)
-		}()
+		}
 		if _, err := overridesFile.WriteString(string(overrides)); err != nil {
-			return nil, errors.Wrapf(err, "failed to write file %s", constants.HelmOverridesFilename)
+			cleanup()
+			return nil, noop, errors.Wrapf(err, "failed to write file %s", constants.HelmOverridesFilename)
 		}
 		args = append(args, "-f", constants.HelmOverridesFilename)
 	}
@@ -286,11 +581,13 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		for k, v := range r.SetValueTemplates {
 			t, err := util.ParseEnvTemplate(v)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to parse setValueTemplates")
+				cleanup()
+				return nil, noop, errors.Wrapf(err, "failed to parse setValueTemplates")
 			}
 			result, err := util.ExecuteEnvTemplate(t, envMap)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to generate setValueTemplates")
+				cleanup()
+				return nil, noop, errors.Wrapf(err, "failed to generate setValueTemplates")
 			}
 			setValues[k] = result
 		}
@@ -299,13 +596,9 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		setOpts = append(setOpts, "--set")
 		setOpts = append(setOpts, fmt.Sprintf("%s=%s", k, v))
 	}
-	if r.Wait {
-		args = append(args, "--wait")
-	}
 	args = append(args, setOpts...)
 
-	helmErr := h.helm(ctx, out, r.UseHelmSecrets, args...)
-	return h.getDeployResults(ctx, ns, releaseName), helmErr
+	return args, cleanup, nil
 }
 
 func createEnvVarMap(imageName string, digest string) map[string]string {
@@ -373,9 +666,15 @@ func (h *HelmDeployer) packageChart(ctx context.Context, r latest.HelmRelease) (
 	return filepath.Join(tmp, fpath), nil
 }
 
-func (h *HelmDeployer) getReleaseInfo(ctx context.Context, release string) (*bufio.Reader, error) {
+func (h *HelmDeployer) getReleaseInfo(ctx context.Context, helmVersion semver.Version, namespace string, release string) (*bufio.Reader, error) {
+	args := []string{"get", release}
+	if isHelm3(helmVersion) {
+		// Helm 3 releases are scoped to a namespace and `helm get` requires a subcommand.
+		args = append([]string{"get", "manifest", release}, namespaceArgs(namespace)...)
+	}
+
 	var releaseInfo bytes.Buffer
-	if err := h.helm(ctx, &releaseInfo, false, "get", release); err != nil {
+	if err := h.helm(ctx, &releaseInfo, false, args...); err != nil {
 		return nil, fmt.Errorf("error retrieving helm deployment info: %s", releaseInfo.String())
 	}
 	return bufio.NewReader(&releaseInfo), nil
@@ -384,28 +683,63 @@ func (h *HelmDeployer) getReleaseInfo(ctx context.Context, release string) (*buf
 // Retrieve info about all releases using helm get
 // Skaffold labels will be applied to each deployed k8s object
 // Since helm isn't always consistent with retrieving results, don't return errors here
-func (h *HelmDeployer) getDeployResults(ctx context.Context, namespace string, release string) []Artifact {
-	b, err := h.getReleaseInfo(ctx, release)
+func (h *HelmDeployer) getDeployResults(ctx context.Context, helmVersion semver.Version, namespace string, release string) []Artifact {
+	b, err := h.getReleaseInfo(ctx, helmVersion, namespace, release)
 	if err != nil {
-		logrus.Warnf(err.Error())
+		logrus.Warn(err.Error())
 		return nil
 	}
 	return parseReleaseInfo(namespace, b)
 }
 
-func (h *HelmDeployer) deleteRelease(ctx context.Context, out io.Writer, r latest.HelmRelease) error {
+func (h *HelmDeployer) deleteRelease(ctx context.Context, out io.Writer, helmVersion semver.Version, r latest.HelmRelease) error {
 	releaseName, err := evaluateReleaseName(r.Name)
 	if err != nil {
 		return errors.Wrap(err, "cannot parse the release name template")
 	}
 
-	if err := h.helm(ctx, out, false, "delete", releaseName, "--purge"); err != nil {
+	// A release deployed with manifest transforms, eg. by `skaffold debug`, is unknown to helm.
+	// Its rendered manifests were applied with kubectl, whatever the command that cleans it up.
+	ns := h.releaseNamespace(r)
+	if !h.isInstalled(ctx, ioutil.Discard, helmVersion, ns, releaseName) {
+		return h.deleteRendered(ctx, out, helmVersion, r, releaseName)
+	}
+
+	args := []string{"delete", releaseName, "--purge"}
+	if isHelm3(helmVersion) {
+		args = append([]string{"uninstall", releaseName}, namespaceArgs(ns)...)
+	}
+
+	if err := h.helm(ctx, out, false, args...); err != nil {
 		logrus.Debugf("deleting release %s: %v\n", releaseName, err)
 	}
 
 	return nil
 }
 
+// deleteRendered deletes the resources of a release that was deployed with `deployRendered`.
+func (h *HelmDeployer) deleteRendered(ctx context.Context, out io.Writer, helmVersion semver.Version, r latest.HelmRelease, releaseName string) error {
+	if r.Remote && !isHelm3(helmVersion) {
+		logrus.Debugf("release %s is not installed and helm %s can't render remote chart %s, nothing to delete", releaseName, helmVersion, r.ChartPath)
+		return nil
+	}
+
+	if err := h.buildDependencies(ctx, out, r); err != nil {
+		return err
+	}
+
+	manifests, err := h.renderRelease(ctx, out, helmVersion, r, releaseName, nil)
+	if err != nil {
+		return errors.Wrap(err, "rendering release")
+	}
+
+	if len(manifests) == 0 {
+		return nil
+	}
+
+	return h.kubectlFor(r, releaseName).Delete(ctx, out, manifests)
+}
+
 func (h *HelmDeployer) joinTagsToBuildResult(builds []build.Artifact, params map[string]string) (map[string]build.Artifact, error) {
 	imageToBuildResult := map[string]build.Artifact{}
 	for _, b := range builds {
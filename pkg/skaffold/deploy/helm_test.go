@@ -30,15 +30,18 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	schemautil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var testBuilds = []build.Artifact{
@@ -126,6 +129,19 @@ var testDeployHelmStyleConfig = &latest.HelmDeploy{
 	},
 }
 
+var testDeployCreateNamespaceConfig = &latest.HelmDeploy{
+	Releases: []latest.HelmRelease{
+		{
+			Name:      "skaffold-helm",
+			ChartPath: "examples/test",
+			Values: map[string]string{
+				"image": "skaffold-helm",
+			},
+			CreateNamespace: true,
+		},
+	},
+}
+
 var testDeployConfigParameterUnmatched = &latest.HelmDeploy{
 	Releases: []latest.HelmRelease{
 		{
@@ -192,6 +208,14 @@ var testDeployRemoteChart = &latest.HelmDeploy{
 
 var testNamespace = "testNamespace"
 
+var version20rc = `Client: &version.Version{SemVer:"v2.0.0-rc.1", GitCommit:"92be174acf51e60a33287fb7011f4571eaa5cb98", GitTreeState:"clean"}`
+
+var version21 = `Client: &version.Version{SemVer:"v2.14.1", GitCommit:"5270352a09c7e8b6e8c9593002a73535276507c0", GitTreeState:"clean"}`
+
+var version30 = `version.BuildInfo{Version:"v3.0.0", GitCommit:"e29ce2a54e96cd02ccfce88bee4f58bb6e2a28b6", GitTreeState:"clean", GoVersion:"go1.13.4"}`
+
+var version32 = `version.BuildInfo{Version:"v3.2.0", GitCommit:"e11b7ce3b12db2941e90399e874513fbd24bcb71", GitTreeState:"clean", GoVersion:"go1.13.10"}`
+
 var validDeployYaml = `
 # Source: skaffold-helm/templates/deployment.yaml
 apiVersion: extensions/v1beta1
@@ -454,6 +478,124 @@ func TestHelmDeploy(t *testing.T) {
 			runContext:  makeRunContext(testDeployWithTemplatedName, false),
 			builds:      testBuilds,
 		},
+		{
+			description: "helm version failure",
+			cmd: &MockHelm{
+				t:             t,
+				versionResult: fmt.Errorf("not found"),
+			},
+			shouldErr:  true,
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "unparsable helm version",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: "unknown",
+			},
+			shouldErr:  true,
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 2 install with --name",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: version20rc,
+				getResult:  fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return cmd.Args[4] == "--name" && cmd.Args[5] == "skaffold-helm"
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 3 get release in namespace",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: version30,
+				getMatcher: func(cmd *exec.Cmd) bool {
+					// `get all` checks the release exists, `get manifest` retrieves the deployed resources
+					return (cmd.Args[4] == "all" || cmd.Args[4] == "manifest") && hasArgs(cmd, "skaffold-helm", "--namespace", testNamespace)
+				},
+				upgradeMatcher: func(cmd *exec.Cmd) bool {
+					return cmd.Args[4] == "skaffold-helm" && hasArgs(cmd, "--namespace", testNamespace)
+				},
+				installResult: fmt.Errorf("should not have called install"),
+			},
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 3 install without --name",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: version30,
+				getResult:  fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return cmd.Args[4] == "skaffold-helm" && !hasArgs(cmd, "--name") && hasArgs(cmd, "--namespace", testNamespace)
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 3 upgrade ignores recreatePods",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: version30,
+				upgradeMatcher: func(cmd *exec.Cmd) bool {
+					return !hasArgs(cmd, "--recreate-pods")
+				},
+				installResult: fmt.Errorf("should not have called install"),
+			},
+			runContext: makeRunContext(testDeployRecreatePodsConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 3.2 install with --create-namespace",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: version32,
+				getResult:  fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return hasArgs(cmd, "--create-namespace")
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployCreateNamespaceConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 3.0 doesn't support --create-namespace",
+			cmd: &MockHelm{
+				t:             t,
+				versionOut:    version30,
+				getResult:     fmt.Errorf("not found"),
+				installResult: fmt.Errorf("should not have called install"),
+			},
+			shouldErr:  true,
+			runContext: makeRunContext(testDeployCreateNamespaceConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm 2 ignores createNamespace",
+			cmd: &MockHelm{
+				t:          t,
+				versionOut: version21,
+				getResult:  fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return !hasArgs(cmd, "--create-namespace")
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployCreateNamespaceConfig, false),
+			builds:     testBuilds,
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -467,11 +609,290 @@ func TestHelmDeploy(t *testing.T) {
 	}
 }
 
+var renderedYaml = `---
+# Source: skaffold-helm/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: skaffold-helm
+---
+# Source: skaffold-helm/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: skaffold-helm
+`
+
+func TestHelmDeployWithTransforms(t *testing.T) {
+	var tests = []struct {
+		description      string
+		versionOut       string
+		deploy           *latest.HelmDeploy
+		expected         []string
+		expectedRollouts []string
+		shouldErr        bool
+	}{
+		{
+			description: "helm 2 renders with --name",
+			versionOut:  version21,
+			deploy:      testDeployConfig,
+			expected:    []string{"template", "--name", "skaffold-helm", "examples/test"},
+		},
+		{
+			description: "helm 3 renders with release name",
+			versionOut:  version30,
+			deploy:      testDeployConfig,
+			expected:    []string{"template", "skaffold-helm", "examples/test", "--namespace", testNamespace},
+		},
+		{
+			description: "helm 3 renders chart from repository",
+			versionOut:  version30,
+			deploy:      testDeploySkipBuildDependencies,
+			expected:    []string{"template", "skaffold-helm", "stable/chartmuseum"},
+		},
+		{
+			description: "helm 2 can't render remote chart",
+			versionOut:  version21,
+			deploy: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{{Name: "skaffold-helm", ChartPath: "stable/chartmuseum", Remote: true}},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "wait for the rollout",
+			versionOut:  version30,
+			deploy: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{{Name: "skaffold-helm", ChartPath: "examples/test", Wait: true}},
+			},
+			expected:         []string{"template", "skaffold-helm", "examples/test", "--namespace", testNamespace},
+			expectedRollouts: []string{"deployment/skaffold-helm"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&manifestTransforms, []ManifestTransform{
+				func(l kubectl.ManifestList, builds []build.Artifact, insecureRegistries map[string]bool) (kubectl.ManifestList, error) {
+					return l.SetLabels(map[string]string{"transformed": "true"})
+				},
+			})
+			cmd := &MockHelm{
+				t:             t.T,
+				versionOut:    test.versionOut,
+				templateOut:   renderedYaml,
+				getResult:     fmt.Errorf("should not have called get"),
+				installResult: fmt.Errorf("should not have called install"),
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			}
+			t.Override(&util.DefaultExecCommand, cmd)
+
+			event.InitializeState(makeRunContext(test.deploy, false))
+			deployer := NewHelmDeployer(makeRunContext(test.deploy, false))
+			err := deployer.Deploy(context.Background(), ioutil.Discard, testBuilds, []Labeller{&testLabeller{}})
+
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				t.CheckDeepEqual(test.expected, cmd.templateArgs[:len(test.expected)])
+				t.CheckContains("transformed: \"true\"", cmd.applied)
+				t.CheckContains("test: label", cmd.applied)
+				t.CheckContains("kind: Deployment", cmd.applied)
+				t.CheckDeepEqual(test.expectedRollouts, cmd.rollouts)
+
+				err = deployer.Cleanup(context.Background(), ioutil.Discard)
+
+				t.CheckNoError(err)
+				t.CheckContains("kind: Deployment", cmd.deleted)
+				t.CheckDeepEqual([]string(nil), cmd.deleteArgs)
+			}
+		})
+	}
+}
+
+func TestHelmDeployWithValidation(t *testing.T) {
+	var tests = []struct {
+		description      string
+		versionOut       string
+		deploy           *latest.HelmDeploy
+		templateOut      string
+		installResult    error
+		expectedTemplate bool
+		expectedWarnings []string
+		shouldErr        bool
+	}{
+		{
+			description:      "valid release",
+			versionOut:       version21,
+			deploy:           testDeployConfig,
+			templateOut:      renderedYaml,
+			expectedTemplate: true,
+		},
+		{
+			description:      "invalid release is not installed",
+			versionOut:       version21,
+			deploy:           testDeployConfig,
+			templateOut:      "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: skaffold-helm\nspec:\n  replica: 1",
+			installResult:    fmt.Errorf("should not have called install"),
+			expectedTemplate: true,
+			shouldErr:        true,
+		},
+		{
+			description: "helm 2 remote chart is not validated",
+			versionOut:  version21,
+			deploy: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{{Name: "skaffold-helm", ChartPath: "stable/chartmuseum", Remote: true}},
+			},
+			expectedWarnings: []string{"helm 2.14.1 can't render remote chart stable/chartmuseum, its manifests are not validated"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+			cmd := &MockHelm{
+				t:             t.T,
+				versionOut:    test.versionOut,
+				templateOut:   test.templateOut,
+				getResult:     fmt.Errorf("not found"),
+				installResult: test.installResult,
+			}
+			t.Override(&util.DefaultExecCommand, cmd)
+
+			runCtx := makeRunContext(test.deploy, false)
+			runCtx.Cfg.Deploy.Validation = &latest.ManifestValidation{}
+			event.InitializeState(runCtx)
+			err := NewHelmDeployer(runCtx).Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expectedTemplate, cmd.templateArgs != nil)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+			t.CheckDeepEqual(!test.deploy.Releases[0].Remote, cmd.depBuilds == 1)
+		})
+	}
+}
+
+type testLabeller struct{}
+
+func (l *testLabeller) Labels() map[string]string {
+	return map[string]string{"test": "label"}
+}
+
+func TestHelmCleanup(t *testing.T) {
+	var tests = []struct {
+		description string
+		versionOut  string
+		expected    []string
+	}{
+		{
+			description: "helm 2 deletes and purges the release",
+			versionOut:  version21,
+			expected:    []string{"delete", "skaffold-helm", "--purge"},
+		},
+		{
+			description: "helm 3 uninstalls the release from its namespace",
+			versionOut:  version30,
+			expected:    []string{"uninstall", "skaffold-helm", "--namespace", testNamespace},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			cmd := &MockHelm{t: t.T, versionOut: test.versionOut}
+			t.Override(&util.DefaultExecCommand, cmd)
+
+			err := NewHelmDeployer(makeRunContext(testDeployConfig, false)).Cleanup(context.Background(), ioutil.Discard)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expected, cmd.deleteArgs)
+		})
+	}
+}
+
+func TestHelmCleanupRendered(t *testing.T) {
+	testutil.Run(t, "release unknown to helm was deployed with kubectl", func(t *testutil.T) {
+		cmd := &MockHelm{
+			t:           t.T,
+			versionOut:  version30,
+			templateOut: renderedYaml,
+			getResult:   fmt.Errorf("release: not found"),
+		}
+		t.Override(&util.DefaultExecCommand, cmd)
+
+		err := NewHelmDeployer(makeRunContext(testDeployConfig, false)).Cleanup(context.Background(), ioutil.Discard)
+
+		t.CheckNoError(err)
+		t.CheckContains("kind: Deployment", cmd.deleted)
+		t.CheckDeepEqual([]string(nil), cmd.deleteArgs)
+		t.CheckDeepEqual(1, cmd.depBuilds)
+	})
+}
+
+func TestBinVer(t *testing.T) {
+	var tests = []struct {
+		description string
+		versionOut  string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "helm 2 release candidate",
+			versionOut:  version20rc,
+			expected:    "2.0.0-rc.1",
+		},
+		{
+			description: "helm 2",
+			versionOut:  version21,
+			expected:    "2.14.1",
+		},
+		{
+			description: "helm 3",
+			versionOut:  version30,
+			expected:    "3.0.0",
+		},
+		{
+			description: "helm 3 short output",
+			versionOut:  "v3.2.0+ge11b7ce",
+			expected:    "3.2.0+ge11b7ce",
+		},
+		{
+			description: "no version",
+			versionOut:  "Error: unknown command",
+			expected:    "0.0.0",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&util.DefaultExecCommand, &MockHelm{t: t.T, versionOut: test.versionOut})
+
+			v, err := NewHelmDeployer(makeRunContext(testDeployConfig, false)).binVer(context.Background())
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, v.String())
+		})
+	}
+}
+
+// hasArgs checks that the command has the expected arguments, in sequence.
+func hasArgs(cmd *exec.Cmd, expected ...string) bool {
+	for i := 0; i+len(expected) <= len(cmd.Args); i++ {
+		found := true
+		for j, arg := range expected {
+			if cmd.Args[i+j] != arg {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
 type CommandMatcher func(*exec.Cmd) bool
 
 type MockHelm struct {
 	t *testing.T
 
+	versionOut     string
+	versionResult  error
 	getResult      error
 	getMatcher     CommandMatcher
 	installResult  error
@@ -479,9 +900,19 @@ type MockHelm struct {
 	upgradeResult  error
 	upgradeMatcher CommandMatcher
 	depResult      error
+	depBuilds      int
 
 	packageOut    io.Reader
 	packageResult error
+
+	deleteArgs []string
+
+	templateOut  string
+	templateArgs []string
+
+	applied  string
+	deleted  string
+	rollouts []string
 }
 
 func (m *MockHelm) RunCmdOut(c *exec.Cmd) ([]byte, error) {
@@ -494,6 +925,10 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 		m.t.Errorf("Not enough args in command %v", c)
 	}
 
+	if c.Args[0] == "kubectl" {
+		return m.runKubectl(c)
+	}
+
 	if c.Args[1] != "--kube-context" || c.Args[2] != testKubeContext {
 		m.t.Errorf("Invalid kubernetes context %v", c)
 	}
@@ -505,6 +940,27 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 	}
 
 	switch c.Args[3] {
+	case "version":
+		if m.versionResult != nil {
+			return m.versionResult
+		}
+		versionOut := m.versionOut
+		if versionOut == "" {
+			versionOut = version21
+		}
+		if _, err := c.Stdout.Write([]byte(versionOut)); err != nil {
+			m.t.Errorf("Failed to write stdout")
+		}
+		return nil
+	case "delete", "uninstall":
+		m.deleteArgs = c.Args[3:]
+		return nil
+	case "template":
+		m.templateArgs = c.Args[3:]
+		if _, err := c.Stdout.Write([]byte(m.templateOut)); err != nil {
+			m.t.Errorf("Failed to write stdout")
+		}
+		return nil
 	case "get":
 		if m.getMatcher != nil && !m.getMatcher(c) {
 			m.t.Errorf("get matcher failed to match cmd")
@@ -521,6 +977,7 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 		}
 		return m.upgradeResult
 	case "dep":
+		m.depBuilds++
 		return m.depResult
 	case "package":
 		if m.packageOut != nil {
@@ -535,6 +992,38 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 	}
 }
 
+func (m *MockHelm) runKubectl(c *exec.Cmd) error {
+	if c.Args[1] != "--context" || c.Args[2] != testKubeContext {
+		m.t.Errorf("Invalid kubernetes context %v", c)
+	}
+
+	for i, arg := range c.Args {
+		if arg == "rollout" {
+			m.rollouts = append(m.rollouts, c.Args[i+2])
+			return nil
+		}
+	}
+
+	stdin, err := ioutil.ReadAll(c.Stdin)
+	if err != nil {
+		m.t.Errorf("Failed to read stdin")
+	}
+
+	for _, arg := range c.Args {
+		switch arg {
+		case "apply":
+			m.applied = string(stdin)
+			return nil
+		case "delete":
+			m.deleted = string(stdin)
+			return nil
+		}
+	}
+
+	m.t.Errorf("Unknown kubectl command: %+v", c)
+	return nil
+}
+
 func TestParseHelmRelease(t *testing.T) {
 	var tests = []struct {
 		description string
@@ -573,6 +1062,27 @@ func TestExtractChartFilename(t *testing.T) {
 	testutil.CheckErrorAndDeepEqual(t, false, err, "foo-1.2.3-dirty.tgz", out)
 }
 
+func TestHelmReleaseSelectors(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.SetEnvs(map[string]string{"USER": "jane"})
+
+		selectors := HelmReleaseSelectors([]latest.HelmRelease{{Name: "web-{{.USER}}"}})
+
+		selects := func(podLabels map[string]string) bool {
+			for _, selector := range selectors {
+				if selector.Matches(labels.Set(podLabels)) {
+					return true
+				}
+			}
+			return false
+		}
+		t.CheckDeepEqual(true, selects(map[string]string{"release": "web-jane"}))
+		t.CheckDeepEqual(true, selects(map[string]string{"app.kubernetes.io/instance": "web-jane"}))
+		t.CheckDeepEqual(false, selects(map[string]string{"release": "db"}))
+		t.CheckDeepEqual(false, selects(nil))
+	})
+}
+
 func TestHelmDependencies(t *testing.T) {
 	var tests = []struct {
 		description           string
@@ -19,6 +19,7 @@ package deploy
 import (
 	"context"
 	"io"
+	"io/ioutil"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
@@ -40,6 +41,8 @@ type KubectlDeployer struct {
 	kubectl            kubectl.CLI
 	defaultRepo        string
 	insecureRegistries map[string]bool
+	imageFields        []latest.ImageField
+	validator          *kubectl.Validator
 }
 
 // NewKubectlDeployer returns a new KubectlDeployer for a DeployConfig filled
@@ -53,9 +56,12 @@ func NewKubectlDeployer(runCtx *runcontext.RunContext) *KubectlDeployer {
 			KubeContext: runCtx.KubeContext,
 			Flags:       runCtx.Cfg.Deploy.KubectlDeploy.Flags,
 			ForceDeploy: runCtx.Opts.ForceDeploy(),
+			Decrypter:   kubectl.NewDecrypter(runCtx.Cfg.Deploy.KubectlDeploy.Sops),
 		},
 		defaultRepo:        runCtx.DefaultRepo,
 		insecureRegistries: runCtx.InsecureRegistries,
+		imageFields:        runCtx.Cfg.Deploy.ImageFields,
+		validator:          kubectl.NewValidator(runCtx.Cfg.Deploy.Validation, runCtx.WorkingDir),
 	}
 }
 
@@ -75,6 +81,19 @@ func AddManifestTransform(newTransform ManifestTransform) {
 	manifestTransforms = append(manifestTransforms, newTransform)
 }
 
+// applyManifestTransforms applies all the registered transforms to a list of manifests.
+func applyManifestTransforms(manifests kubectl.ManifestList, builds []build.Artifact, insecureRegistries map[string]bool) (kubectl.ManifestList, error) {
+	var err error
+	for _, transform := range manifestTransforms {
+		manifests, err = transform(manifests, builds, insecureRegistries)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to transform manifests")
+		}
+	}
+
+	return manifests, nil
+}
+
 // Deploy templates the provided manifests with a simple `find and replace` and
 // runs `kubectl apply` on those manifests
 func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
@@ -83,11 +102,16 @@ func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []bu
 		color.Default.Fprintln(out, err)
 	}
 
-	event.DeployInProgress()
+	event.DeployInProgress(k.kubectl.KubeContext)
+
+	if err := k.validateManifests(ctx); err != nil {
+		event.DeployFailed(k.kubectl.KubeContext, err)
+		return errors.Wrap(err, "validating manifests")
+	}
 
 	manifests, err := k.readManifests(ctx)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "reading manifests")
 	}
 
@@ -95,32 +119,30 @@ func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []bu
 		return nil
 	}
 
-	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo)
+	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo, k.imageFields)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "replacing images in manifests")
 	}
 
-	manifests, err = manifests.SetLabels(merge(labellers...))
+	manifests, err = setLabels(manifests, labellers)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "setting labels in manifests")
 	}
 
-	for _, transform := range manifestTransforms {
-		manifests, err = transform(manifests, builds, k.insecureRegistries)
-		if err != nil {
-			return errors.Wrap(err, "unable to transform manifests")
-		}
+	manifests, err = applyManifestTransforms(manifests, builds, k.insecureRegistries)
+	if err != nil {
+		return err
 	}
 
 	err = k.kubectl.Apply(ctx, out, manifests)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "kubectl error")
 	}
 
-	event.DeployComplete()
+	event.DeployComplete(k.kubectl.KubeContext)
 	return err
 }
 
@@ -163,6 +185,44 @@ func (k *KubectlDeployer) manifestFiles(manifests []string) ([]string, error) {
 	return filteredManifests, nil
 }
 
+// validateManifests validates the local manifest files, one file at a time
+// so that problems are reported with the file they were found in.
+func (k *KubectlDeployer) validateManifests(ctx context.Context) error {
+	if k.validator == nil {
+		return nil
+	}
+
+	files, err := k.Dependencies()
+	if err != nil {
+		return errors.Wrap(err, "listing manifests")
+	}
+
+	for _, file := range files {
+		if util.IsURL(file) {
+			continue
+		}
+
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrap(err, "reading manifest")
+		}
+
+		var manifests kubectl.ManifestList
+		manifests.Append(buf)
+
+		manifests, err = k.kubectl.Decrypter.DecryptSourceManifests(ctx, manifests)
+		if err != nil {
+			return err
+		}
+
+		if err := k.validator.Validate(file, manifests); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // readManifests reads the manifests to deploy/delete.
 func (k *KubectlDeployer) readManifests(ctx context.Context) (kubectl.ManifestList, error) {
 	manifests, err := k.Dependencies()
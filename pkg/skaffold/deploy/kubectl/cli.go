@@ -17,15 +17,19 @@ limitations under the License.
 package kubectl
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"io/ioutil"
 	"os/exec"
+	"strings"
 	"sync"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
 )
 
 // CLI holds parameters to run kubectl.
@@ -37,6 +41,7 @@ type CLI struct {
 	version       ClientVersion
 	versionOnce   sync.Once
 	ForceDeploy   bool
+	Decrypter     Decrypter
 	previousApply ManifestList
 }
 
@@ -72,24 +77,79 @@ func (c *CLI) Apply(ctx context.Context, out io.Writer, manifests ManifestList)
 	return nil
 }
 
+// rolledOutKinds are the kinds of the workloads whose rollout can be waited for.
+var rolledOutKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// WaitForRollout runs `kubectl rollout status` on the workloads of a list of manifests.
+func (c *CLI) WaitForRollout(ctx context.Context, out io.Writer, manifests ManifestList) error {
+	for _, manifest := range manifests {
+		var workload struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal(manifest, &workload); err != nil {
+			return errors.Wrap(err, "reading manifest")
+		}
+		if !rolledOutKinds[workload.Kind] || workload.Metadata.Name == "" {
+			continue
+		}
+
+		resource := strings.ToLower(workload.Kind) + "/" + workload.Metadata.Name
+		args := []string{resource}
+		if workload.Metadata.Namespace != "" {
+			args = append(args, "--namespace", workload.Metadata.Namespace)
+		}
+
+		if err := c.Run(ctx, nil, out, "rollout", []string{"status"}, args...); err != nil {
+			return errors.Wrapf(err, "waiting for the rollout of %s", resource)
+		}
+	}
+
+	return nil
+}
+
 // ReadManifests reads a list of manifests in yaml format.
+// Manifests encrypted with SOPS are decrypted.
 func (c *CLI) ReadManifests(ctx context.Context, manifests []string) (ManifestList, error) {
 	var list []string
+	var decrypted ManifestList
 	for _, manifest := range manifests {
+		// Manifests that can't be read, like urls, are left to kubectl.
+		if content, err := ioutil.ReadFile(manifest); err == nil && IsEncrypted(content) {
+			plaintext, err := c.Decrypter.decryptFile(ctx, manifest, content)
+			if err != nil {
+				return nil, err
+			}
+			decrypted.Append(bytes.TrimSpace(plaintext))
+			continue
+		}
+
 		list = append(list, "-f", manifest)
 	}
 
-	args := c.args("create", []string{"--dry-run", "-oyaml"}, list...)
+	var manifestList ManifestList
+	if len(list) > 0 {
+		args := c.args("create", []string{"--dry-run", "-oyaml"}, list...)
+
+		cmd := exec.CommandContext(ctx, "kubectl", args...)
+		buf, err := util.RunCmdOut(cmd)
+		if err != nil {
+			return nil, errors.Wrap(err, "kubectl create")
+		}
 
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
-	buf, err := util.RunCmdOut(cmd)
-	if err != nil {
-		return nil, errors.Wrap(err, "kubectl create")
+		manifestList.Append(buf)
+		logrus.Debugln("manifests", manifestList.String())
 	}
 
-	var manifestList ManifestList
-	manifestList.Append(buf)
-	logrus.Debugln("manifests", manifestList.String())
+	// Decrypted manifests don't go through `kubectl create` to keep them out of its output.
+	manifestList = append(manifestList, decrypted...)
 
 	return manifestList, nil
 }
@@ -17,18 +17,24 @@ limitations under the License.
 package kubectl
 
 import (
+	"regexp"
+	"sort"
+	"strings"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 )
 
 // ReplaceImages replaces image names in a list of manifests.
-func (l *ManifestList) ReplaceImages(builds []build.Artifact, defaultRepo string) (ManifestList, error) {
-	replacer := newImageReplacer(builds, defaultRepo)
+// Fields named `image` are always replaced, along with the given image fields.
+func (l *ManifestList) ReplaceImages(builds []build.Artifact, defaultRepo string, imageFields []latest.ImageField) (ManifestList, error) {
+	replacer := newImageReplacer(builds, defaultRepo, imageFields)
 
 	updated, err := l.Visit(replacer)
 	if err != nil {
@@ -45,18 +51,41 @@ type imageReplacer struct {
 	defaultRepo     string
 	tagsByImageName map[string]string
 	found           map[string]bool
+	fields          []imageField
+}
+
+// imageField is a latest.ImageField with its paths split into segments.
+type imageField struct {
+	kind  string
+	group string
+	keys  []string
+	paths [][]string
 }
 
-func newImageReplacer(builds []build.Artifact, defaultRepo string) *imageReplacer {
+func newImageReplacer(builds []build.Artifact, defaultRepo string, imageFields []latest.ImageField) *imageReplacer {
 	tagsByImageName := make(map[string]string)
 	for _, build := range builds {
 		tagsByImageName[build.ImageName] = build.Tag
 	}
 
+	var fields []imageField
+	for _, f := range imageFields {
+		field := imageField{
+			kind:  f.Kind,
+			group: f.Group,
+			keys:  f.Keys,
+		}
+		for _, path := range f.Paths {
+			field.paths = append(field.paths, splitPath(path))
+		}
+		fields = append(fields, field)
+	}
+
 	return &imageReplacer{
 		defaultRepo:     defaultRepo,
 		tagsByImageName: tagsByImageName,
 		found:           make(map[string]bool),
+		fields:          fields,
 	}
 }
 
@@ -64,6 +93,77 @@ func (r *imageReplacer) Matches(key string) bool {
 	return key == "image"
 }
 
+func (r *imageReplacer) MatchesPath(apiVersion, kind string, path []string) bool {
+	key := path[len(path)-1]
+	if r.Matches(key) {
+		return true
+	}
+
+	for _, field := range r.fields {
+		if !field.matchesResource(apiVersion, kind) {
+			continue
+		}
+
+		for _, k := range field.keys {
+			if k == key {
+				return true
+			}
+		}
+
+		for _, p := range field.paths {
+			if matchesPath(p, path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (f *imageField) matchesResource(apiVersion, kind string) bool {
+	if f.kind != "*" && f.kind != kind {
+		return false
+	}
+	if f.group == "" {
+		return true
+	}
+
+	// The core group has no prefix: `apiVersion: v1`.
+	var group string
+	if idx := strings.LastIndex(apiVersion, "/"); idx != -1 {
+		group = apiVersion[:idx]
+	}
+	return f.group == group
+}
+
+// indexRegex matches list indices in JSON paths, eg. `[*]` or `[0]`.
+var indexRegex = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// splitPath splits a path like `spec.steps.*.image` into its segments.
+// JSON paths like `$.spec.steps[*].image` are also accepted.
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = indexRegex.ReplaceAllString(path, ".$1")
+	path = strings.Trim(path, ".")
+
+	return strings.Split(path, ".")
+}
+
+// matchesPath matches a path against a pattern where `*` matches any field name or list index.
+func matchesPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+
+	for i, segment := range pattern {
+		if segment != "*" && segment != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (r *imageReplacer) NewValue(old interface{}) (bool, interface{}) {
 	image, ok := old.(string)
 	if !ok {
@@ -102,12 +202,21 @@ func (r *imageReplacer) parseAndReplace(image string) (bool, interface{}) {
 	return false, nil
 }
 
+// Check warns about the built images that are not referenced by any of the manifests.
 func (r *imageReplacer) Check() {
+	var unused []string
 	for imageName := range r.tagsByImageName {
 		if !r.found[imageName] {
-			warnings.Printf("image [%s] is not used by the deployment", imageName)
+			unused = append(unused, imageName)
 		}
 	}
+
+	if len(unused) == 0 {
+		return
+	}
+
+	sort.Strings(unused)
+	warnings.Printf("images %v are not used by the deployment. Configure `deploy.imageFields` if they are referenced by fields not named `image`", unused)
 }
 
 func (r *imageReplacer) substituteRepoIntoImage(originalImage string) string {
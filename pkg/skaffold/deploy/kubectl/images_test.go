@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
@@ -90,13 +91,12 @@ spec:
 	reset := testutil.Override(t, &warnings.Printf, fakeWarner.Warnf)
 	defer reset()
 
-	resultManifest, err := manifests.ReplaceImages(builds, "")
+	resultManifest, err := manifests.ReplaceImages(builds, "", nil)
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
 	testutil.CheckErrorAndDeepEqual(t, false, err, []string{
 		"Couldn't parse image: in valid",
-		"image [skaffold/unused] is not used by the deployment",
-		"image [skaffold/usedwrongfqn] is not used by the deployment",
+		"images [skaffold/unused skaffold/usedwrongfqn] are not used by the deployment. Configure `deploy.imageFields` if they are referenced by fields not named `image`",
 	}, fakeWarner.Warnings)
 }
 
@@ -104,7 +104,7 @@ func TestReplaceEmptyManifest(t *testing.T) {
 	manifests := ManifestList{[]byte(""), []byte("  ")}
 	expected := ManifestList{}
 
-	resultManifest, err := manifests.ReplaceImages(nil, "")
+	resultManifest, err := manifests.ReplaceImages(nil, "", nil)
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
 }
@@ -112,7 +112,7 @@ func TestReplaceEmptyManifest(t *testing.T) {
 func TestReplaceInvalidManifest(t *testing.T) {
 	manifests := ManifestList{[]byte("INVALID")}
 
-	_, err := manifests.ReplaceImages(nil, "")
+	_, err := manifests.ReplaceImages(nil, "", nil)
 
 	testutil.CheckError(t, true, err)
 }
@@ -125,7 +125,173 @@ image:
 - value2
 `)}
 
-	output, err := manifests.ReplaceImages(nil, "")
+	output, err := manifests.ReplaceImages(nil, "", nil)
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, manifests.String(), output.String())
 }
+
+func TestReplaceImageFields(t *testing.T) {
+	manifests := ManifestList{[]byte(`
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: knative
+spec:
+  template:
+    spec:
+      containers:
+      - image: skaffold/example
+`), []byte(`
+apiVersion: tekton.dev/v1alpha1
+kind: Task
+metadata:
+  name: task
+spec:
+  steps:
+  - containerImage: skaffold/example
+  - name: skaffold/example
+`), []byte(`
+apiVersion: example.com/v1
+kind: Custom
+metadata:
+  name: custom
+spec:
+  images:
+    main: skaffold/example
+    other: skaffold/example
+  containerImage: skaffold/example
+`), []byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containerImage: skaffold/example
+`)}
+
+	builds := []build.Artifact{{
+		ImageName: "skaffold/example",
+		Tag:       "skaffold/example:TAG",
+	}}
+
+	imageFields := []latest.ImageField{
+		{Kind: "Task", Group: "tekton.dev", Keys: []string{"containerImage"}},
+		{Kind: "Custom", Group: "example.com", Paths: []string{"$.spec.images.main"}},
+		{Kind: "Custom", Group: "other.com", Keys: []string{"containerImage"}},
+	}
+
+	expected := ManifestList{[]byte(`
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: knative
+spec:
+  template:
+    spec:
+      containers:
+      - image: skaffold/example:TAG
+`), []byte(`
+apiVersion: tekton.dev/v1alpha1
+kind: Task
+metadata:
+  name: task
+spec:
+  steps:
+  - containerImage: skaffold/example:TAG
+  - name: skaffold/example
+`), []byte(`
+apiVersion: example.com/v1
+kind: Custom
+metadata:
+  name: custom
+spec:
+  containerImage: skaffold/example
+  images:
+    main: skaffold/example:TAG
+    other: skaffold/example
+`), []byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containerImage: skaffold/example
+`)}
+
+	resultManifest, err := manifests.ReplaceImages(builds, "", imageFields)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
+}
+
+func TestMatchesImagePath(t *testing.T) {
+	var tests = []struct {
+		description string
+		field       latest.ImageField
+		apiVersion  string
+		kind        string
+		path        []string
+		expected    bool
+	}{
+		{
+			description: "image key always matches",
+			apiVersion:  "v1",
+			kind:        "Pod",
+			path:        []string{"spec", "containers", "0", "image"},
+			expected:    true,
+		},
+		{
+			description: "key for any kind",
+			field:       latest.ImageField{Kind: "*", Keys: []string{"containerImage"}},
+			apiVersion:  "v1",
+			kind:        "Pod",
+			path:        []string{"spec", "containerImage"},
+			expected:    true,
+		},
+		{
+			description: "key for other kind",
+			field:       latest.ImageField{Kind: "Task", Keys: []string{"containerImage"}},
+			apiVersion:  "v1",
+			kind:        "Pod",
+			path:        []string{"spec", "containerImage"},
+		},
+		{
+			description: "core group",
+			field:       latest.ImageField{Kind: "Pod", Group: "apps", Keys: []string{"containerImage"}},
+			apiVersion:  "v1",
+			kind:        "Pod",
+			path:        []string{"spec", "containerImage"},
+		},
+		{
+			description: "path with wildcard",
+			field:       latest.ImageField{Kind: "Rollout", Paths: []string{"spec.steps.*.run"}},
+			apiVersion:  "argoproj.io/v1alpha1",
+			kind:        "Rollout",
+			path:        []string{"spec", "steps", "3", "run"},
+			expected:    true,
+		},
+		{
+			description: "json path with index",
+			field:       latest.ImageField{Kind: "Rollout", Paths: []string{"$.spec.steps[1].run"}},
+			apiVersion:  "argoproj.io/v1alpha1",
+			kind:        "Rollout",
+			path:        []string{"spec", "steps", "1", "run"},
+			expected:    true,
+		},
+		{
+			description: "path too short",
+			field:       latest.ImageField{Kind: "Rollout", Paths: []string{"spec.steps"}},
+			apiVersion:  "argoproj.io/v1alpha1",
+			kind:        "Rollout",
+			path:        []string{"spec", "steps", "1", "run"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			replacer := newImageReplacer(nil, "", []latest.ImageField{test.field})
+
+			matches := replacer.MatchesPath(test.apiVersion, test.kind, test.path)
+
+			t.CheckDeepEqual(test.expected, matches)
+		})
+	}
+}
@@ -35,6 +35,19 @@ func (l *ManifestList) SetLabels(labels map[string]string) (ManifestList, error)
 	return updated, nil
 }
 
+// SetTopLevelLabels adds labels to the objects of a list of Kubernetes manifests,
+// but not to the templates they hold, so that changing these labels doesn't roll out new pods.
+func (l *ManifestList) SetTopLevelLabels(labels map[string]string) (ManifestList, error) {
+	updated, err := l.Visit(&topLevelLabelsSetter{newLabelsSetter(labels)})
+	if err != nil {
+		return nil, errors.Wrap(err, "setting labels")
+	}
+
+	logrus.Debugln("manifests with top-level labels", updated.String())
+
+	return updated, nil
+}
+
 type labelsSetter struct {
 	labels map[string]string
 }
@@ -76,3 +89,20 @@ func (r *labelsSetter) NewValue(old interface{}) (bool, interface{}) {
 
 	return true, metadata
 }
+
+// topLevelLabelsSetter only sets labels in the metadata of the objects,
+// including the items of a `List`.
+type topLevelLabelsSetter struct {
+	*labelsSetter
+}
+
+func (r *topLevelLabelsSetter) MatchesPath(_, kind string, path []string) bool {
+	switch {
+	case len(path) == 1:
+		return path[0] == "metadata"
+	case len(path) == 3 && kind == "List":
+		return path[0] == "items" && path[2] == "metadata"
+	default:
+		return false
+	}
+}
@@ -121,3 +121,63 @@ spec:
 
 	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
 }
+
+func TestSetTopLevelLabels(t *testing.T) {
+	manifests := ManifestList{[]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: gcr.io/k8s-skaffold/example
+        name: example
+`), []byte(`
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: getting-started
+`)}
+
+	expected := ManifestList{[]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    key1: value1
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: gcr.io/k8s-skaffold/example
+        name: example
+`), []byte(`
+apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    labels:
+      key1: value1
+    name: getting-started
+kind: List
+`)}
+
+	resultManifest, err := manifests.SetTopLevelLabels(map[string]string{
+		"key1": "value1",
+	})
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
+}
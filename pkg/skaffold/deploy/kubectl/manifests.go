@@ -51,6 +51,35 @@ func (l *ManifestList) Append(buf []byte) {
 	}
 }
 
+// AppendStream appends the documents of a YAML stream, as output by
+// manifest generators. Documents are separated by `---` lines and
+// documents without any content, besides comments, are skipped.
+func (l *ManifestList) AppendStream(buf []byte) {
+	var doc []string
+	var hasContent bool
+
+	flush := func() {
+		if hasContent {
+			*l = append(*l, []byte(strings.TrimSpace(strings.Join(doc, "\n"))))
+		}
+		doc = nil
+		hasContent = false
+	}
+
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line == "---" || strings.HasPrefix(line, "--- ") || line == "..." {
+			flush()
+			continue
+		}
+
+		doc = append(doc, line)
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			hasContent = true
+		}
+	}
+	flush()
+}
+
 // Diff computes the list of manifests that have changed.
 func (l *ManifestList) Diff(latest ManifestList) ManifestList {
 	if l == nil {
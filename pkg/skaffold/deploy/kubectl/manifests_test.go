@@ -59,3 +59,13 @@ func TestAppendWithoutSeperator(t *testing.T) {
 	testutil.CheckDeepEqual(t, pod1, string(manifests[0]))
 	testutil.CheckDeepEqual(t, pod2, string(manifests[1]))
 }
+
+func TestAppendStream(t *testing.T) {
+	var manifests ManifestList
+
+	manifests.AppendStream([]byte("---\n# generated\n" + pod1 + "\n--- # second pod\n" + pod2 + "\n---\n# empty\n...\n"))
+
+	testutil.CheckDeepEqual(t, 2, len(manifests))
+	testutil.CheckDeepEqual(t, "# generated\n"+pod1, string(manifests[0]))
+	testutil.CheckDeepEqual(t, pod2, string(manifests[1]))
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// BundledKubernetesVersion is the version of the Kubernetes API
+// whose schemas are bundled with Skaffold.
+const BundledKubernetesVersion = "1.12"
+
+// openAPISchema is the subset of an OpenAPI schema used to validate manifests.
+type openAPISchema struct {
+	Type                  string                    `json:"type,omitempty"`
+	Format                string                    `json:"format,omitempty"`
+	Properties            map[string]*openAPISchema `json:"properties,omitempty"`
+	Required              []string                  `json:"required,omitempty"`
+	Items                 *openAPISchema            `json:"items,omitempty"`
+	AdditionalProperties  *additionalProperties     `json:"additionalProperties,omitempty"`
+	IntOrString           bool                      `json:"x-kubernetes-int-or-string,omitempty"`
+	PreserveUnknownFields bool                      `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+}
+
+// additionalProperties is either a boolean or a schema.
+type additionalProperties struct {
+	Allowed bool
+	Schema  *openAPISchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// anySchema accepts any value.
+var anySchema = &openAPISchema{}
+
+// schemaKey identifies the schema of a resource.
+func schemaKey(apiVersion, kind string) string {
+	return apiVersion + " " + kind
+}
+
+var (
+	bundledSchemas     map[string]*openAPISchema
+	bundledSchemasOnce sync.Once
+)
+
+// bundledSchemasFor returns the schemas of the Kubernetes API resources
+// for a given version, indexed by apiVersion and kind.
+// The schemas are derived from the API types Skaffold is built with, so
+// other versions are rejected.
+func bundledSchemasFor(kubernetesVersion string) (map[string]*openAPISchema, error) {
+	if kubernetesVersion != "" && majorMinor(kubernetesVersion) != BundledKubernetesVersion {
+		return nil, fmt.Errorf("no schemas bundled for Kubernetes %s, only Kubernetes %s is supported", kubernetesVersion, BundledKubernetesVersion)
+	}
+
+	bundledSchemasOnce.Do(func() {
+		bundledSchemas = map[string]*openAPISchema{}
+
+		r := schemaReflector{schemas: map[reflect.Type]*openAPISchema{}}
+		for gvk, t := range scheme.Scheme.AllKnownTypes() {
+			bundledSchemas[schemaKey(gvk.GroupVersion().String(), gvk.Kind)] = r.schemaFor(t)
+		}
+	})
+
+	return bundledSchemas, nil
+}
+
+// majorMinor turns versions like `v1.12.9` into `1.12`.
+func majorMinor(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	intOrStringType = reflect.TypeOf(intstr.IntOrString{})
+	quantityType    = reflect.TypeOf(resource.Quantity{})
+)
+
+// schemaReflector derives schemas from Go types, following their json tags.
+type schemaReflector struct {
+	schemas map[reflect.Type]*openAPISchema
+}
+
+func (r *schemaReflector) schemaFor(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if s, found := r.schemas[t]; found {
+		return s
+	}
+
+	switch {
+	case t == intOrStringType:
+		return &openAPISchema{IntOrString: true}
+	case t == quantityType:
+		return &openAPISchema{Format: "quantity"}
+	case reflect.PtrTo(t).Implements(marshalerType):
+		// Types with a custom json encoding, like timestamps or raw extensions.
+		return anySchema
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Base64 encoded bytes.
+			return &openAPISchema{Type: "string"}
+		}
+		return &openAPISchema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: &additionalProperties{Allowed: true, Schema: r.schemaFor(t.Elem())}}
+	case reflect.Struct:
+		s := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+		// Register the schema before visiting the fields of recursive types.
+		r.schemas[t] = s
+		r.addFields(s, t)
+		return s
+	default:
+		return anySchema
+	}
+}
+
+func (r *schemaReflector) addFields(s *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		if field.Anonymous && name == "" || strings.Contains(tag, ",inline") {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(s, embedded)
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = r.schemaFor(field.Type)
+
+		// As with the API, fields that are not omitted when empty are required.
+		if !strings.Contains(tag, ",omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// customResourceDefinition is the subset of a CustomResourceDefinition,
+// in either `apiextensions.k8s.io/v1beta1` or `apiextensions.k8s.io/v1`,
+// that describes the schemas of custom resources.
+type customResourceDefinition struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind string `json:"kind"`
+		} `json:"names"`
+		Version    string             `json:"version"`
+		Validation *customResourceVal `json:"validation"`
+		Versions   []struct {
+			Name   string             `json:"name"`
+			Schema *customResourceVal `json:"schema"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+type customResourceVal struct {
+	OpenAPIV3Schema *openAPISchema `json:"openAPIV3Schema"`
+}
+
+// readCRDSchemas reads the schemas of custom resources from files holding
+// CustomResourceDefinitions, indexed by apiVersion and kind.
+// Custom resources defined without a schema accept any content.
+func readCRDSchemas(files []string) (map[string]*openAPISchema, error) {
+	schemas := map[string]*openAPISchema{}
+
+	for _, file := range files {
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CustomResourceDefinitions")
+		}
+
+		var manifests ManifestList
+		manifests.Append(buf)
+
+		for _, manifest := range manifests {
+			var crd customResourceDefinition
+			if err := yaml.Unmarshal(manifest, &crd); err != nil {
+				return nil, errors.Wrapf(err, "parsing CustomResourceDefinition in %s", file)
+			}
+			if crd.Kind != "CustomResourceDefinition" {
+				continue
+			}
+
+			spec := crd.Spec
+			common := anySchema
+			if spec.Validation != nil && spec.Validation.OpenAPIV3Schema != nil {
+				common = customResourceSchema(spec.Validation.OpenAPIV3Schema)
+			}
+
+			if spec.Version != "" {
+				schemas[schemaKey(spec.Group+"/"+spec.Version, spec.Names.Kind)] = common
+			}
+			for _, version := range spec.Versions {
+				s := common
+				if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+					s = customResourceSchema(version.Schema.OpenAPIV3Schema)
+				}
+				schemas[schemaKey(spec.Group+"/"+version.Name, spec.Names.Kind)] = s
+			}
+		}
+	}
+
+	return schemas, nil
+}
+
+// customResourceSchema adds the fields common to all the resources
+// to the schema of a custom resource, if they are not described.
+func customResourceSchema(s *openAPISchema) *openAPISchema {
+	if len(s.Properties) == 0 {
+		return s
+	}
+
+	root := *s
+	root.Properties = map[string]*openAPISchema{
+		"apiVersion": {Type: "string"},
+		"kind":       {Type: "string"},
+		"metadata":   {Type: "object"},
+	}
+	for name, property := range s.Properties {
+		root.Properties[name] = property
+	}
+	return &root
+}
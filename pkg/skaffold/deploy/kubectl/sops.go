@@ -0,0 +1,231 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	sopsMetadata = regexp.MustCompile(`(?m)^sops:\s*$`)
+
+	redactor = &redactHook{values: map[string]bool{}}
+)
+
+// Decrypter decrypts manifests encrypted with SOPS, using age or PGP keys from local files.
+// Plaintext is never written to disk and decrypted values are redacted from the logs.
+type Decrypter struct {
+	AgeKeyFile string
+	GnuPGHome  string
+}
+
+// NewDecrypter returns a Decrypter configured with the given keys.
+func NewDecrypter(cfg *latest.SopsConfig) Decrypter {
+	if cfg == nil {
+		return Decrypter{}
+	}
+
+	return Decrypter{
+		AgeKeyFile: cfg.AgeKeyFile,
+		GnuPGHome:  cfg.GnuPGHome,
+	}
+}
+
+// IsEncrypted returns true if a yaml document holds SOPS metadata.
+func IsEncrypted(manifest []byte) bool {
+	return sopsMetadata.Match(manifest)
+}
+
+// DecryptManifests decrypts the rendered manifests that are encrypted with SOPS.
+// Rendering, by kustomize for example, reformats the manifests, so their message
+// authentication code is not verified.
+func (d *Decrypter) DecryptManifests(ctx context.Context, manifests ManifestList) (ManifestList, error) {
+	return d.decryptManifests(ctx, manifests, "--ignore-mac")
+}
+
+// DecryptSourceManifests decrypts the manifests, read as is from their files, that are
+// encrypted with SOPS. Their message authentication code is verified.
+func (d *Decrypter) DecryptSourceManifests(ctx context.Context, manifests ManifestList) (ManifestList, error) {
+	return d.decryptManifests(ctx, manifests)
+}
+
+func (d *Decrypter) decryptManifests(ctx context.Context, manifests ManifestList, flags ...string) (ManifestList, error) {
+	var decrypted ManifestList
+
+	for _, manifest := range manifests {
+		if !IsEncrypted(manifest) {
+			decrypted = append(decrypted, manifest)
+			continue
+		}
+
+		plaintext, err := d.decrypt(ctx, bytes.NewReader(manifest), append(flags, "/dev/stdin")...)
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypting manifest")
+		}
+
+		redactor.addValues(manifest, plaintext)
+		decrypted = append(decrypted, bytes.TrimSpace(plaintext))
+	}
+
+	return decrypted, nil
+}
+
+// decryptFile decrypts a manifest file that is encrypted with SOPS.
+func (d *Decrypter) decryptFile(ctx context.Context, path string, encrypted []byte) ([]byte, error) {
+	plaintext, err := d.decrypt(ctx, nil, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decrypting %s", path)
+	}
+
+	redactor.addValues(encrypted, plaintext)
+	return plaintext, nil
+}
+
+func (d *Decrypter) decrypt(ctx context.Context, in *bytes.Reader, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "sops", append([]string{"--decrypt", "--input-type", "yaml", "--output-type", "yaml"}, args...)...)
+	cmd.Env = util.OSEnviron()
+	if d.AgeKeyFile != "" {
+		cmd.Env = append(cmd.Env, "SOPS_AGE_KEY_FILE="+d.AgeKeyFile)
+	}
+	if d.GnuPGHome != "" {
+		cmd.Env = append(cmd.Env, "GNUPGHOME="+d.GnuPGHome)
+	}
+	if in != nil {
+		cmd.Stdin = in
+	}
+
+	// Unlike RunCmdOut, RunCmd doesn't log the output of the command.
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := util.RunCmd(cmd); err != nil {
+		return nil, errors.Wrapf(err, "running sops: %s", stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// redactHook is a logrus hook that redacts decrypted values from log messages.
+type redactHook struct {
+	sync.RWMutex
+	once   sync.Once
+	values map[string]bool
+	// sorted lists the values, longest first, so that a value is
+	// fully redacted even when it contains a shorter one.
+	sorted []string
+}
+
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	h.RLock()
+	defer h.RUnlock()
+
+	for _, value := range h.sorted {
+		entry.Message = strings.Replace(entry.Message, value, "<redacted>", -1)
+	}
+	return nil
+}
+
+// addValues registers the values that were decrypted, found by comparing
+// the encrypted and plaintext versions of yaml documents.
+func (h *redactHook) addValues(encrypted, plaintext []byte) {
+	var encryptedDocs, plaintextDocs ManifestList
+	encryptedDocs.Append(encrypted)
+	plaintextDocs.Append(plaintext)
+
+	var values []string
+	for i := range encryptedDocs {
+		if i >= len(plaintextDocs) {
+			break
+		}
+
+		var e, p interface{}
+		if err := yaml.Unmarshal(encryptedDocs[i], &e); err != nil {
+			continue
+		}
+		if err := yaml.Unmarshal(plaintextDocs[i], &p); err != nil {
+			continue
+		}
+		values = append(values, decryptedValues(e, p)...)
+	}
+
+	h.once.Do(func() { logrus.AddHook(h) })
+
+	h.Lock()
+	for _, value := range values {
+		// Multiline values, like certificates, are logged with a different indentation.
+		for _, line := range append(strings.Split(value, "\n"), value) {
+			if line = strings.TrimSpace(line); line != "" && !h.values[line] {
+				h.values[line] = true
+				h.sorted = append(h.sorted, line)
+			}
+		}
+	}
+	sort.SliceStable(h.sorted, func(i, j int) bool { return len(h.sorted[i]) > len(h.sorted[j]) })
+	h.Unlock()
+}
+
+// decryptedValues lists the plaintext values of the fields that are encrypted.
+func decryptedValues(encrypted, plaintext interface{}) []string {
+	var values []string
+
+	switch e := encrypted.(type) {
+	case map[interface{}]interface{}:
+		p, ok := plaintext.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		for k, v := range e {
+			if k == "sops" {
+				continue
+			}
+			values = append(values, decryptedValues(v, p[k])...)
+		}
+	case []interface{}:
+		p, ok := plaintext.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, v := range e {
+			if i < len(p) {
+				values = append(values, decryptedValues(v, p[i])...)
+			}
+		}
+	case string:
+		if strings.HasPrefix(e, "ENC[") && plaintext != nil {
+			values = append(values, fmt.Sprint(plaintext))
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+const encryptedSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+data:
+  password: ENC[AES256_GCM,data:Tr7o=,iv:1=,tag:2=,type:str]
+sops:
+  mac: ENC[AES256_GCM,data:abc,iv:1=,tag:2=,type:str]
+  version: 3.5.0`
+
+const decryptedSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+data:
+  password: c3VwZXJzZWNyZXQ=
+`
+
+// fakeSops fakes `sops` and `kubectl create` commands.
+type fakeSops struct {
+	t             *testutil.T
+	kubectlOutput string
+	commands      []string
+	env           []string
+	stdin         []string
+}
+
+func (f *fakeSops) RunCmd(cmd *exec.Cmd) error {
+	f.commands = append(f.commands, strings.Join(cmd.Args, " "))
+	f.env = cmd.Env
+	if cmd.Stdin != nil {
+		stdin, err := ioutil.ReadAll(cmd.Stdin)
+		f.t.CheckNoError(err)
+		f.stdin = append(f.stdin, string(stdin))
+	}
+
+	_, err := fmt.Fprint(cmd.Stdout, decryptedSecret)
+	return err
+}
+
+func (f *fakeSops) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
+	f.commands = append(f.commands, strings.Join(cmd.Args, " "))
+	return []byte(f.kubectlOutput), nil
+}
+
+func TestIsEncrypted(t *testing.T) {
+	testutil.CheckDeepEqual(t, true, IsEncrypted([]byte(encryptedSecret)))
+	testutil.CheckDeepEqual(t, false, IsEncrypted([]byte(decryptedSecret)))
+	testutil.CheckDeepEqual(t, false, IsEncrypted([]byte("metadata:\n  sops: value")))
+}
+
+func TestDecryptManifests(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		fake := &fakeSops{t: t}
+		t.Override(&util.DefaultExecCommand, fake)
+		t.Override(&util.OSEnviron, func() []string { return []string{"PATH=/bin"} })
+
+		decrypter := NewDecrypter(nil)
+		decrypter.AgeKeyFile = "keys.txt"
+		manifests, err := decrypter.DecryptManifests(context.Background(), ManifestList{[]byte(pod1), []byte(encryptedSecret)})
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(ManifestList{[]byte(pod1), []byte(strings.TrimSpace(decryptedSecret))}, manifests)
+		t.CheckDeepEqual([]string{"sops --decrypt --input-type yaml --output-type yaml --ignore-mac /dev/stdin"}, fake.commands)
+		t.CheckDeepEqual([]string{"PATH=/bin", "SOPS_AGE_KEY_FILE=keys.txt"}, fake.env)
+		t.CheckDeepEqual([]string{encryptedSecret}, fake.stdin)
+	})
+}
+
+func TestDecryptSourceManifests(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		fake := &fakeSops{t: t}
+		t.Override(&util.DefaultExecCommand, fake)
+
+		decrypter := NewDecrypter(nil)
+		manifests, err := decrypter.DecryptSourceManifests(context.Background(), ManifestList{[]byte(encryptedSecret)})
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(ManifestList{[]byte(strings.TrimSpace(decryptedSecret))}, manifests)
+		t.CheckDeepEqual([]string{"sops --decrypt --input-type yaml --output-type yaml /dev/stdin"}, fake.commands)
+	})
+}
+
+func TestReadManifestsDecryptsFiles(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("secret.yaml", encryptedSecret).
+			Write("pod.yaml", pod1)
+		fake := &fakeSops{t: t, kubectlOutput: pod1}
+		t.Override(&util.DefaultExecCommand, fake)
+
+		cli := &CLI{KubeContext: "kubecontext"}
+		manifests, err := cli.ReadManifests(context.Background(), tmpDir.Paths("pod.yaml", "secret.yaml"))
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(ManifestList{[]byte(pod1), []byte(strings.TrimSpace(decryptedSecret))}, manifests)
+		t.CheckDeepEqual([]string{
+			"sops --decrypt --input-type yaml --output-type yaml " + tmpDir.Path("secret.yaml"),
+			"kubectl --context kubecontext create --dry-run -oyaml -f " + tmpDir.Path("pod.yaml"),
+		}, fake.commands)
+	})
+}
+
+func TestRedactDecryptedValues(t *testing.T) {
+	hook := &redactHook{values: map[string]bool{}}
+	hook.addValues([]byte(encryptedSecret), []byte(decryptedSecret))
+
+	entry := &logrus.Entry{Message: "manifests data:\n  password: c3VwZXJzZWNyZXQ=\nkind: Secret"}
+	err := hook.Fire(entry)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "manifests data:\n  password: <redacted>\nkind: Secret", entry.Message)
+}
+
+func TestRedactShortValues(t *testing.T) {
+	hook := &redactHook{values: map[string]bool{}}
+	hook.addValues([]byte(`data:
+  user: ENC[AES256_GCM,data:a,iv:1=,tag:2=,type:str]
+  password: ENC[AES256_GCM,data:b,iv:1=,tag:2=,type:str]
+sops:
+  version: 3.5.0`), []byte(`data:
+  user: ab
+  password: abcd
+`))
+
+	entry := &logrus.Entry{Message: "user: ab, password: abcd"}
+	err := hook.Fire(entry)
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "user: <redacted>, password: <redacted>", entry.Message)
+}
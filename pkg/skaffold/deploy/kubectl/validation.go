@@ -0,0 +1,315 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// helmSource matches the comment `helm template` adds to the rendered manifests.
+var helmSource = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+
+// Validator validates manifests against the schemas of the Kubernetes API
+// and of custom resources, without contacting the cluster.
+type Validator struct {
+	kubernetesVersion string
+	crds              []string
+	workingDir        string
+}
+
+// NewValidator returns a Validator for the given configuration,
+// or nil if the manifests shouldn't be validated.
+func NewValidator(cfg *latest.ManifestValidation, workingDir string) *Validator {
+	if cfg == nil {
+		return nil
+	}
+
+	return &Validator{
+		kubernetesVersion: cfg.KubernetesVersion,
+		crds:              cfg.CRDs,
+		workingDir:        workingDir,
+	}
+}
+
+// Validate validates a list of manifests read from the given source.
+// All the problems found are reported in the returned error, with
+// the file, the resource and the field they were found in.
+func (v *Validator) Validate(source string, manifests ManifestList) error {
+	if v == nil {
+		return nil
+	}
+
+	schemas, err := v.schemas()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, manifest := range manifests {
+		file := source
+		if match := helmSource.FindSubmatch(manifest); match != nil {
+			file = string(match[1])
+		}
+
+		for _, problem := range validateManifest(manifest, schemas) {
+			problems = append(problems, fmt.Sprintf("%s: %s", file, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid manifests:\n - %s", strings.Join(problems, "\n - "))
+	}
+
+	return nil
+}
+
+// schemas returns the bundled schemas of the configured Kubernetes version,
+// completed with the schemas read from the CustomResourceDefinitions.
+func (v *Validator) schemas() (map[string]*openAPISchema, error) {
+	bundled, err := bundledSchemasFor(v.kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v.crds) == 0 {
+		return bundled, nil
+	}
+
+	files, err := util.ExpandPathsGlob(v.workingDir, v.crds)
+	if err != nil {
+		return nil, errors.Wrap(err, "expanding CustomResourceDefinition paths")
+	}
+
+	custom, err := readCRDSchemas(files)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]*openAPISchema, len(bundled)+len(custom))
+	for key, s := range bundled {
+		schemas[key] = s
+	}
+	for key, s := range custom {
+		schemas[key] = s
+	}
+	return schemas, nil
+}
+
+// validateManifest validates a single manifest and returns the problems found.
+func validateManifest(manifest []byte, schemas map[string]*openAPISchema) []string {
+	var m map[interface{}]interface{}
+	if err := yaml.Unmarshal(manifest, &m); err != nil {
+		return []string{fmt.Sprintf("invalid yaml: %s", err)}
+	}
+
+	if len(m) == 0 {
+		return nil
+	}
+
+	apiVersion, _ := m["apiVersion"].(string)
+	kind, _ := m["kind"].(string)
+	resource := resourceName(kind, m)
+	if apiVersion == "" || kind == "" {
+		return []string{fmt.Sprintf("%s: apiVersion and kind are required", resource)}
+	}
+
+	s, found := schemas[schemaKey(apiVersion, kind)]
+	if !found {
+		warnings.Printf("no schema to validate %s %s, add its CustomResourceDefinition to `deploy.validation.crds`", apiVersion, resource)
+		return nil
+	}
+
+	var problems []string
+	validateValue(m, s, nil, func(path []string, problem string) {
+		problems = append(problems, fmt.Sprintf("%s: %s: %s", resource, formatPath(path), problem))
+	})
+	return problems
+}
+
+// resourceName returns a name like `Deployment/web` for error messages.
+func resourceName(kind string, m map[interface{}]interface{}) string {
+	if kind == "" {
+		kind = "<unknown kind>"
+	}
+
+	metadata, _ := m["metadata"].(map[interface{}]interface{})
+	if name, ok := metadata["name"].(string); ok {
+		return kind + "/" + name
+	}
+	if generateName, ok := metadata["generateName"].(string); ok {
+		return kind + "/" + generateName
+	}
+	return kind
+}
+
+// formatPath formats a path to a field, eg. `spec.containers[0].image`.
+func formatPath(path []string) string {
+	var formatted string
+	for _, p := range path {
+		if _, err := strconv.Atoi(p); err == nil {
+			formatted += "[" + p + "]"
+			continue
+		}
+		if formatted != "" {
+			formatted += "."
+		}
+		formatted += p
+	}
+	return formatted
+}
+
+func validateValue(value interface{}, s *openAPISchema, path []string, report func(path []string, problem string)) {
+	// `null` means the field is not set.
+	if value == nil || s == nil {
+		return
+	}
+
+	if s.Format == "quantity" {
+		switch value.(type) {
+		case int, int64, uint64, float64, string:
+		default:
+			report(path, fmt.Sprintf("expected a number or a string, got %s", describe(value)))
+		}
+		return
+	}
+
+	if s.IntOrString || s.Format == "int-or-string" {
+		switch v := value.(type) {
+		case int, int64, uint64, string:
+		case float64:
+			// yaml reads `1.0` as a float.
+			if v != math.Trunc(v) {
+				report(path, fmt.Sprintf("expected an integer or a string, got %s", describe(value)))
+			}
+		default:
+			report(path, fmt.Sprintf("expected an integer or a string, got %s", describe(value)))
+		}
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			report(path, fmt.Sprintf("expected a string, got %s", describe(value)))
+		}
+	case "integer":
+		switch value.(type) {
+		case int, int64, uint64:
+		default:
+			report(path, fmt.Sprintf("expected an integer, got %s", describe(value)))
+		}
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			report(path, fmt.Sprintf("expected a number, got %s", describe(value)))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report(path, fmt.Sprintf("expected a boolean, got %s", describe(value)))
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			report(path, fmt.Sprintf("expected a list, got %s", describe(value)))
+			return
+		}
+		for i, item := range items {
+			validateValue(item, s.Items, append(path[:len(path):len(path)], strconv.Itoa(i)), report)
+		}
+	case "object":
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			report(path, fmt.Sprintf("expected an object, got %s", describe(value)))
+			return
+		}
+		validateObject(m, s, path, report)
+	default:
+		// Schemas without a type can still describe the fields of an object.
+		if m, ok := value.(map[interface{}]interface{}); ok {
+			validateObject(m, s, path, report)
+		}
+	}
+}
+
+func validateObject(m map[interface{}]interface{}, s *openAPISchema, path []string, report func(path []string, problem string)) {
+	// Report the problems in a stable order.
+	var keys []string
+	for k := range m {
+		keys = append(keys, fmt.Sprintf("%v", k))
+	}
+	sort.Strings(keys)
+
+	// Objects that don't describe their fields accept any field.
+	closed := len(s.Properties) > 0 && s.AdditionalProperties == nil && !s.PreserveUnknownFields
+
+	for _, key := range keys {
+		fieldPath := append(path[:len(path):len(path)], key)
+		value := m[key]
+
+		if property, found := s.Properties[key]; found {
+			validateValue(value, property, fieldPath, report)
+			continue
+		}
+
+		switch {
+		case s.AdditionalProperties != nil && !s.AdditionalProperties.Allowed:
+			report(fieldPath, "unknown field")
+		case s.AdditionalProperties != nil:
+			validateValue(value, s.AdditionalProperties.Schema, fieldPath, report)
+		case closed:
+			report(fieldPath, "unknown field")
+		}
+	}
+
+	for _, required := range s.Required {
+		if _, found := m[required]; !found {
+			report(append(path[:len(path):len(path)], required), "missing required field")
+		}
+	}
+}
+
+// describe returns the yaml type of a value for error messages.
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("a string %q", v)
+	case bool:
+		return fmt.Sprintf("a boolean %t", v)
+	case int, int64, uint64:
+		return fmt.Sprintf("an integer %v", v)
+	case float64:
+		return fmt.Sprintf("a number %v", v)
+	case []interface{}:
+		return "a list"
+	case map[interface{}]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
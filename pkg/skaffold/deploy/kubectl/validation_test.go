@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const validDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: gcr.io/k8s-skaffold/web
+        ports:
+        - containerPort: 8080
+        resources:
+          limits:
+            cpu: 1
+            memory: 128Mi
+        readinessProbe:
+          httpGet:
+            port: http`
+
+const invalidDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replica: 2
+  template:
+    spec:
+      containers:
+      - name: web
+        image: gcr.io/k8s-skaffold/web
+        ports:
+        - containerPort: "8080"
+        env: name=value`
+
+const crd = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  version: v1
+  names:
+    kind: CronTab
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required: [image]
+          properties:
+            cronSpec:
+              type: string
+            image:
+              type: string`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		description       string
+		kubernetesVersion string
+		crds              string
+		manifests         ManifestList
+		expected          string
+		expectedWarnings  []string
+	}{
+		{
+			description: "valid",
+			manifests:   ManifestList{[]byte(validDeployment), []byte(pod1)},
+		},
+		{
+			description: "invalid",
+			manifests:   ManifestList{[]byte(invalidDeployment)},
+			expected: `invalid manifests:
+ - deployment.yaml: Deployment/web: spec.replica: unknown field
+ - deployment.yaml: Deployment/web: spec.template.spec.containers[0].env: expected a list, got a string "name=value"
+ - deployment.yaml: Deployment/web: spec.template.spec.containers[0].ports[0].containerPort: expected an integer, got a string "8080"`,
+		},
+		{
+			description:      "unknown kind is not validated",
+			manifests:        ManifestList{[]byte("apiVersion: stable.example.com/v1\nkind: CronTab\nmetadata:\n  name: cron\nspec:\n  foo: bar")},
+			expectedWarnings: []string{"no schema to validate stable.example.com/v1 CronTab/cron, add its CustomResourceDefinition to `deploy.validation.crds`"},
+		},
+		{
+			description: "custom resource",
+			crds:        crd,
+			manifests:   ManifestList{[]byte("apiVersion: stable.example.com/v1\nkind: CronTab\nmetadata:\n  name: cron\nspec:\n  cronSpec: 5")},
+			expected: `invalid manifests:
+ - deployment.yaml: CronTab/cron: spec.cronSpec: expected a string, got an integer 5
+ - deployment.yaml: CronTab/cron: spec.image: missing required field`,
+		},
+		{
+			description: "helm source",
+			manifests:   ManifestList{[]byte("# Source: chart/templates/pod.yaml\napiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\nspec:\n  restartPolicy: true")},
+			expected: `invalid manifests:
+ - chart/templates/pod.yaml: Pod/pod: spec.restartPolicy: expected a string, got a boolean true`,
+		},
+		{
+			description: "missing kind",
+			manifests:   ManifestList{[]byte("apiVersion: v1\nmetadata:\n  name: pod")},
+			expected: `invalid manifests:
+ - deployment.yaml: <unknown kind>/pod: apiVersion and kind are required`,
+		},
+		{
+			description:       "bundled version",
+			kubernetesVersion: "v1.12.9",
+			manifests:         ManifestList{[]byte(validDeployment)},
+		},
+		{
+			description:       "other version is rejected",
+			kubernetesVersion: "1.18",
+			manifests:         ManifestList{[]byte(validDeployment)},
+			expected:          "no schemas bundled for Kubernetes 1.18, only Kubernetes 1.12 is supported",
+		},
+		{
+			description: "missing required field",
+			manifests:   ManifestList{[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\nspec:\n  containers:\n  - image: nginx\n    ports:\n    - name: http")},
+			expected: `invalid manifests:
+ - deployment.yaml: Pod/pod: spec.containers[0].ports[0].containerPort: missing required field
+ - deployment.yaml: Pod/pod: spec.containers[0].name: missing required field`,
+		},
+		{
+			description: "quantities",
+			manifests:   ManifestList{[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\nspec:\n  containers:\n  - name: web\n    resources:\n      limits:\n        cpu: 0.5\n        memory: 128Mi\n      requests:\n        cpu: 1\n        memory: true")},
+			expected: `invalid manifests:
+ - deployment.yaml: Pod/pod: spec.containers[0].resources.requests.memory: expected a number or a string, got a boolean true`,
+		},
+		{
+			description: "int or string",
+			manifests:   ManifestList{[]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\nspec:\n  containers:\n  - name: web\n    readinessProbe:\n      httpGet:\n        port: 8080.0\n    livenessProbe:\n      httpGet:\n        port: 80.5")},
+			expected: `invalid manifests:
+ - deployment.yaml: Pod/pod: spec.containers[0].livenessProbe.httpGet.port: expected an integer or a string, got a number 80.5`,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+			tmpDir := t.NewTempDir().Write("crds/crontab.yaml", test.crds)
+
+			validator := NewValidator(&latest.ManifestValidation{
+				KubernetesVersion: test.kubernetesVersion,
+				CRDs:              []string{"crds/*.yaml"},
+			}, tmpDir.Root())
+			err := validator.Validate("deployment.yaml", test.manifests)
+
+			if test.expected == "" {
+				t.CheckNoError(err)
+			} else {
+				t.CheckErrorContains(test.expected, err)
+			}
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
+func TestValidateNotConfigured(t *testing.T) {
+	validator := NewValidator(nil, ".")
+
+	err := validator.Validate("deployment.yaml", ManifestList{[]byte(invalidDeployment)})
+
+	testutil.CheckError(t, false, err)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package kubectl
 
 import (
+	"strconv"
+
 	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -28,6 +30,16 @@ type Replacer interface {
 	NewValue(old interface{}) (bool, interface{})
 }
 
+// PathReplacer is a Replacer that matches fields by their path
+// in the resource being visited rather than by their key only.
+type PathReplacer interface {
+	Replacer
+
+	// MatchesPath is given the apiVersion and kind of the resource being visited
+	// and the path to the field, where list items are identified by their index.
+	MatchesPath(apiVersion, kind string, path []string) bool
+}
+
 // Visit recursively visits a list of manifests and applies transformations of them.
 func (l *ManifestList) Visit(replacer Replacer) (ManifestList, error) {
 	var updated ManifestList
@@ -42,7 +54,7 @@ func (l *ManifestList) Visit(replacer Replacer) (ManifestList, error) {
 			continue
 		}
 
-		recursiveVisit(m, replacer)
+		recursiveVisit(m, nil, matcherFor(replacer, m), replacer)
 
 		updatedManifest, err := yaml.Marshal(m)
 		if err != nil {
@@ -55,18 +67,35 @@ func (l *ManifestList) Visit(replacer Replacer) (ManifestList, error) {
 	return updated, nil
 }
 
-func recursiveVisit(i interface{}, replacer Replacer) {
+// matcherFor returns a function that matches the fields of a resource that the replacer should replace.
+func matcherFor(replacer Replacer, resource map[interface{}]interface{}) func(path []string) bool {
+	if pathReplacer, ok := replacer.(PathReplacer); ok {
+		apiVersion, _ := resource["apiVersion"].(string)
+		kind, _ := resource["kind"].(string)
+
+		return func(path []string) bool {
+			return pathReplacer.MatchesPath(apiVersion, kind, path)
+		}
+	}
+
+	return func(path []string) bool {
+		return replacer.Matches(path[len(path)-1])
+	}
+}
+
+func recursiveVisit(i interface{}, path []string, matches func(path []string) bool, replacer Replacer) {
 	switch t := i.(type) {
 	case []interface{}:
-		for _, v := range t {
-			recursiveVisit(v, replacer)
+		for idx, v := range t {
+			recursiveVisit(v, append(path[:len(path):len(path)], strconv.Itoa(idx)), matches, replacer)
 		}
 	case map[interface{}]interface{}:
 		for k, v := range t {
 			key := k.(string)
+			fieldPath := append(path[:len(path):len(path)], key)
 
-			if !replacer.Matches(key) {
-				recursiveVisit(v, replacer)
+			if !matches(fieldPath) {
+				recursiveVisit(v, fieldPath, matches, replacer)
 				continue
 			}
 
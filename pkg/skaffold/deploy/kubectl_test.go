@@ -177,6 +177,34 @@ func TestKubectlDeploy(t *testing.T) {
 	}
 }
 
+func TestKubectlDeployValidation(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&util.DefaultExecCommand, testutil.FakeRunOut(t.T, "kubectl version --client -ojson", kubectlVersion))
+		t.NewTempDir().
+			Write("deployment.yaml", "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replica: 2").
+			Chdir()
+
+		k := NewKubectlDeployer(&runcontext.RunContext{
+			WorkingDir: ".",
+			Cfg: &latest.Pipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						KubectlDeploy: &latest.KubectlDeploy{
+							Manifests: []string{"deployment.yaml"},
+						},
+					},
+					Validation: &latest.ManifestValidation{},
+				},
+			},
+			KubeContext: testKubeContext,
+			Opts:        &config.SkaffoldOptions{},
+		})
+		err := k.Deploy(context.Background(), ioutil.Discard, nil, nil)
+
+		t.CheckErrorContains("deployment.yaml: Deployment/web: spec.replica: unknown field", err)
+	})
+}
+
 func TestKubectlCleanup(t *testing.T) {
 	var tests = []struct {
 		description string
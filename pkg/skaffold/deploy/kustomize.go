@@ -20,8 +20,10 @@ import (
 	"context"
 	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -33,12 +35,14 @@ import (
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/pkg/errors"
 )
 
 // kustomization is the content of a kustomization.yaml file.
 type kustomization struct {
 	Bases                 []string             `yaml:"bases"`
+	Components            []string             `yaml:"components"`
 	Resources             []string             `yaml:"resources"`
 	Patches               []string             `yaml:"patches"`
 	PatchesStrategicMerge []string             `yaml:"patchesStrategicMerge"`
@@ -48,6 +52,9 @@ type kustomization struct {
 	SecretGenerator       []secretGenerator    `yaml:"secretGenerator"`
 }
 
+// kustomizationFiles are the names kustomize looks for in a directory, in order.
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
 type patchJSON6902 struct {
 	Path string `yaml:"path"`
 }
@@ -67,6 +74,8 @@ type KustomizeDeployer struct {
 	kubectl            kubectl.CLI
 	defaultRepo        string
 	insecureRegistries map[string]bool
+	imageFields        []latest.ImageField
+	validator          *kubectl.Validator
 }
 
 func NewKustomizeDeployer(runCtx *runcontext.RunContext) *KustomizeDeployer {
@@ -77,9 +86,12 @@ func NewKustomizeDeployer(runCtx *runcontext.RunContext) *KustomizeDeployer {
 			KubeContext: runCtx.KubeContext,
 			Flags:       runCtx.Cfg.Deploy.KustomizeDeploy.Flags,
 			ForceDeploy: runCtx.Opts.ForceDeploy(),
+			Decrypter:   kubectl.NewDecrypter(runCtx.Cfg.Deploy.KustomizeDeploy.Sops),
 		},
 		defaultRepo:        runCtx.DefaultRepo,
 		insecureRegistries: runCtx.InsecureRegistries,
+		imageFields:        runCtx.Cfg.Deploy.ImageFields,
+		validator:          kubectl.NewValidator(runCtx.Cfg.Deploy.Validation, runCtx.WorkingDir),
 	}
 }
 
@@ -99,7 +111,7 @@ func (k *KustomizeDeployer) Deploy(ctx context.Context, out io.Writer, builds []
 
 	manifests, err := k.readManifests(ctx)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "reading manifests")
 	}
 
@@ -107,34 +119,37 @@ func (k *KustomizeDeployer) Deploy(ctx context.Context, out io.Writer, builds []
 		return nil
 	}
 
-	event.DeployInProgress()
+	event.DeployInProgress(k.kubectl.KubeContext)
+
+	if err := k.validator.Validate(strings.Join(k.paths(), ", "), manifests); err != nil {
+		event.DeployFailed(k.kubectl.KubeContext, err)
+		return errors.Wrap(err, "validating manifests")
+	}
 
-	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo)
+	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo, k.imageFields)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "replacing images in manifests")
 	}
 
-	manifests, err = manifests.SetLabels(merge(labellers...))
+	manifests, err = setLabels(manifests, labellers)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "setting labels in manifests")
 	}
 
-	for _, transform := range manifestTransforms {
-		manifests, err = transform(manifests, builds, k.insecureRegistries)
-		if err != nil {
-			return errors.Wrap(err, "unable to transform manifests")
-		}
+	manifests, err = applyManifestTransforms(manifests, builds, k.insecureRegistries)
+	if err != nil {
+		return err
 	}
 
 	err = k.kubectl.Apply(ctx, out, manifests)
 	if err != nil {
-		event.DeployFailed(err)
+		event.DeployFailed(k.kubectl.KubeContext, err)
 		return errors.Wrap(err, "kubectl error")
 	}
 
-	event.DeployComplete()
+	event.DeployComplete(k.kubectl.KubeContext)
 	return nil
 }
 
@@ -155,7 +170,11 @@ func (k *KustomizeDeployer) Cleanup(ctx context.Context, out io.Writer) error {
 func dependenciesForKustomization(dir string) ([]string, error) {
 	var deps []string
 
-	path := filepath.Join(dir, "kustomization.yaml")
+	path, err := findKustomization(dir)
+	if err != nil {
+		return nil, err
+	}
+
 	buf, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -166,7 +185,12 @@ func dependenciesForKustomization(dir string) ([]string, error) {
 		return nil, err
 	}
 
-	for _, base := range content.Bases {
+	for _, base := range append(content.Bases, content.Components...) {
+		if isRemoteKustomization(base) {
+			warnings.Printf("remote base %s of %s is not watched for changes", base, path)
+			continue
+		}
+
 		baseDeps, err := dependenciesForKustomization(filepath.Join(dir, base))
 		if err != nil {
 			return nil, err
@@ -176,7 +200,26 @@ func dependenciesForKustomization(dir string) ([]string, error) {
 	}
 
 	deps = append(deps, path)
-	deps = append(deps, joinPaths(dir, content.Resources)...)
+	for _, resource := range content.Resources {
+		if isRemoteKustomization(resource) {
+			warnings.Printf("remote resource %s of %s is not watched for changes", resource, path)
+			continue
+		}
+
+		// Since kustomize 2.1, resources can also be directories holding a kustomization.
+		resourcePath := filepath.Join(dir, resource)
+		if info, err := os.Stat(resourcePath); err == nil && info.IsDir() {
+			resourceDeps, err := dependenciesForKustomization(resourcePath)
+			if err != nil {
+				return nil, err
+			}
+
+			deps = append(deps, resourceDeps...)
+			continue
+		}
+
+		deps = append(deps, resourcePath)
+	}
 	deps = append(deps, joinPaths(dir, content.Patches)...)
 	deps = append(deps, joinPaths(dir, content.PatchesStrategicMerge)...)
 	deps = append(deps, joinPaths(dir, content.CRDs)...)
@@ -184,15 +227,50 @@ func dependenciesForKustomization(dir string) ([]string, error) {
 		deps = append(deps, filepath.Join(dir, patch.Path))
 	}
 	for _, generator := range content.ConfigMapGenerator {
-		deps = append(deps, joinPaths(dir, generator.Files)...)
+		deps = append(deps, generatorFiles(dir, generator.Files)...)
 	}
 	for _, generator := range content.SecretGenerator {
-		deps = append(deps, joinPaths(dir, generator.Files)...)
+		deps = append(deps, generatorFiles(dir, generator.Files)...)
 	}
 
 	return deps, nil
 }
 
+// findKustomization returns the path to the kustomization file found in a directory.
+func findKustomization(dir string) (string, error) {
+	for _, name := range kustomizationFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", errors.Errorf("no kustomization found in %s", dir)
+}
+
+// isRemoteKustomization tells if a base or a resource is fetched by kustomize from a remote repository.
+func isRemoteKustomization(path string) bool {
+	return strings.Contains(path, "://") ||
+		strings.HasPrefix(path, "git@") ||
+		strings.HasPrefix(path, "github.com/") ||
+		strings.Contains(path, "?ref=")
+}
+
+// generatorFiles returns the paths to the files of a generator.
+// Those can be given as `key=path`.
+func generatorFiles(dir string, files []string) []string {
+	var paths []string
+
+	for _, file := range files {
+		if i := strings.Index(file, "="); i >= 0 {
+			file = file[i+1:]
+		}
+		paths = append(paths, filepath.Join(dir, file))
+	}
+
+	return paths
+}
+
 func joinPaths(root string, paths []string) []string {
 	var list []string
 
@@ -205,21 +283,61 @@ func joinPaths(root string, paths []string) []string {
 
 // Dependencies lists all the files that can change what needs to be deployed.
 func (k *KustomizeDeployer) Dependencies() ([]string, error) {
-	return dependenciesForKustomization(k.KustomizePath)
+	var deps []string
+	seen := map[string]bool{}
+
+	for _, path := range k.paths() {
+		pathDeps, err := dependenciesForKustomization(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Overlays often share the same bases.
+		for _, dep := range pathDeps {
+			if !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+// paths returns the kustomizations to render, in order.
+func (k *KustomizeDeployer) paths() []string {
+	var paths []string
+
+	if k.KustomizePath != "" {
+		paths = append(paths, k.KustomizePath)
+	}
+
+	return append(paths, k.KustomizePaths...)
 }
 
 func (k *KustomizeDeployer) readManifests(ctx context.Context) (kubectl.ManifestList, error) {
-	cmd := exec.CommandContext(ctx, "kustomize", "build", k.KustomizePath)
-	out, err := util.RunCmdOut(cmd)
-	if err != nil {
-		return nil, errors.Wrap(err, "kustomize build")
+	binary := k.Binary
+	if binary == "" {
+		binary = "kustomize"
+	}
+
+	var manifests kubectl.ManifestList
+	for _, path := range k.paths() {
+		args := append([]string{"build"}, k.BuildArgs...)
+		cmd := exec.CommandContext(ctx, binary, append(args, path)...)
+		out, err := util.RunCmdOut(cmd)
+		if err != nil {
+			return nil, errors.Wrapf(err, "kustomize build %s", path)
+		}
+
+		if len(out) > 0 {
+			manifests.Append(out)
+		}
 	}
 
-	if len(out) == 0 {
+	if len(manifests) == 0 {
 		return nil, nil
 	}
 
-	var manifests kubectl.ManifestList
-	manifests.Append(out)
-	return manifests, nil
+	return k.kubectl.Decrypter.DecryptManifests(ctx, manifests)
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -63,6 +64,37 @@ func TestKustomizeDeploy(t *testing.T) {
 			}},
 			forceDeploy: true,
 		},
+		{
+			description: "multiple paths with build args",
+			cfg: &latest.KustomizeDeploy{
+				KustomizePaths: []string{"overlays/api", "overlays/web"},
+				Binary:         "kustomize-v3",
+				BuildArgs:      []string{"--load_restrictor", "none"},
+			},
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("kustomize-v3 build --load_restrictor none overlays/api", deploymentAppYAML).
+				WithRunOut("kustomize-v3 build --load_restrictor none overlays/web", deploymentWebYAML).
+				WithRun("kubectl --context kubecontext --namespace testNamespace apply -f -"),
+			builds: []build.Artifact{{
+				ImageName: "leeroy-web",
+				Tag:       "leeroy-web:123",
+			}, {
+				ImageName: "leeroy-app",
+				Tag:       "leeroy-app:123",
+			}},
+		},
+		{
+			description: "build error",
+			cfg: &latest.KustomizeDeploy{
+				KustomizePaths: []string{"overlays/api", "overlays/web"},
+			},
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("kustomize build overlays/api", deploymentAppYAML).
+				WithRunOutErr("kustomize build overlays/web", "", errors.New("BUG")),
+			shouldErr: true,
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -70,7 +102,7 @@ func TestKustomizeDeploy(t *testing.T) {
 			t.NewTempDir().
 				Chdir()
 
-			k := NewKustomizeDeployer(&runcontext.RunContext{
+			runCtx := &runcontext.RunContext{
 				WorkingDir: ".",
 				Cfg: &latest.Pipeline{
 					Deploy: latest.DeployConfig{
@@ -84,7 +116,10 @@ func TestKustomizeDeploy(t *testing.T) {
 					Namespace: testNamespace,
 					Force:     test.forceDeploy,
 				},
-			})
+			}
+
+			event.InitializeState(runCtx)
+			k := NewKustomizeDeployer(runCtx)
 			err := k.Deploy(context.Background(), ioutil.Discard, test.builds, nil)
 
 			t.CheckError(test.shouldErr, err)
@@ -203,11 +238,28 @@ func TestDependenciesForKustomization(t *testing.T) {
 - files: [secret2.file, secret3.file]`,
 			expected: []string{"kustomization.yaml", "secret1.file", "secret2.file", "secret3.file"},
 		},
+		{
+			description: "generator files with keys",
+			yaml: `configMapGenerator:
+- files: [config=app.properties]`,
+			expected: []string{"kustomization.yaml", "app.properties"},
+		},
+		{
+			description: "remote bases and resources are skipped",
+			yaml: `bases: ["github.com/org/repo/base?ref=v1"]
+resources: ["https://example.com/pod.yaml", pod1.yaml]`,
+			expected: []string{"kustomization.yaml", "pod1.yaml"},
+		},
 		{
 			description: "unknown base",
 			yaml:        `bases: [other]`,
 			shouldErr:   true,
 		},
+		{
+			description: "unknown component",
+			yaml:        `components: [other]`,
+			shouldErr:   true,
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -233,3 +285,45 @@ func TestDependenciesForKustomization(t *testing.T) {
 		})
 	}
 }
+
+func TestDependenciesForKustomizationOverlays(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("base/kustomization.yml", `resources: [deployment.yaml]`).
+			Write("components/debug/kustomization.yaml", `patchesStrategicMerge: [debug.yaml]`).
+			Write("services/redis/Kustomization", `resources: [redis.yaml]`).
+			Write("overlays/api/kustomization.yaml", `bases: [../../base]
+components: [../../components/debug]
+resources: [../../services/redis]`).
+			Write("overlays/web/kustomization.yaml", `bases: [../../base]
+patchesJson6902:
+- path: patch.json`)
+
+		k := NewKustomizeDeployer(&runcontext.RunContext{
+			Cfg: &latest.Pipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						KustomizeDeploy: &latest.KustomizeDeploy{
+							KustomizePaths: tmpDir.Paths("overlays/api", "overlays/web"),
+						},
+					},
+				},
+			},
+			KubeContext: testKubeContext,
+			Opts:        &config.SkaffoldOptions{},
+		})
+		deps, err := k.Dependencies()
+
+		t.CheckErrorAndDeepEqual(false, err, tmpDir.Paths(
+			"base/kustomization.yml",
+			"base/deployment.yaml",
+			"components/debug/kustomization.yaml",
+			"components/debug/debug.yaml",
+			"overlays/api/kustomization.yaml",
+			"services/redis/Kustomization",
+			"services/redis/redis.yaml",
+			"overlays/web/kustomization.yaml",
+			"overlays/web/patch.json",
+		), deps)
+	})
+}
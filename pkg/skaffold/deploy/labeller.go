@@ -17,8 +17,11 @@ limitations under the License.
 package deploy
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 )
 
@@ -58,3 +61,31 @@ func (d *DefaultLabeller) K8sManagedByLabelKeyValueString() string {
 func (d *DefaultLabeller) skaffoldVersion() string {
 	return fmt.Sprintf("skaffold-%s", d.version)
 }
+
+// RunIDLabeller adds a label unique to the current Skaffold run,
+// so that the pods of this run can be told apart from the others.
+type RunIDLabeller struct {
+	runID string
+}
+
+func NewRunIDLabeller() (*RunIDLabeller, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &RunIDLabeller{
+		runID: hex.EncodeToString(id),
+	}, nil
+}
+
+func (r *RunIDLabeller) Labels() map[string]string {
+	return map[string]string{
+		constants.Labels.RunID: r.runID,
+	}
+}
+
+// RunID returns the id of the current run.
+func (r *RunIDLabeller) RunID() string {
+	return r.runID
+}
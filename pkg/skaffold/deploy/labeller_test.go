@@ -19,6 +19,7 @@ package deploy
 import (
 	"testing"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -59,3 +60,49 @@ func TestK8sManagedByLabelKeyValueString(t *testing.T) {
 		t.Fatalf("actual label not equal to expected label. Actual: \n %s \n Expected: \n %s", actual, expected)
 	}
 }
+
+func TestRunIDLabeller(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		l, err := NewRunIDLabeller()
+		t.CheckNoError(err)
+		other, err := NewRunIDLabeller()
+		t.CheckNoError(err)
+
+		t.CheckDeepEqual(map[string]string{"skaffold.dev/run-id": l.RunID()}, l.Labels())
+		t.CheckDeepEqual(32, len(l.RunID()))
+		if l.RunID() == other.RunID() {
+			t.Errorf("expected different run ids, got %s twice", l.RunID())
+		}
+	})
+}
+
+func TestSetLabelsRunIDOnTopLevel(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		manifests := kubectl.ManifestList{[]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      name: web
+`)}
+
+		runID := &RunIDLabeller{runID: "1234"}
+		labelled, err := setLabels(manifests, []Labeller{NewLabeller("version"), runID})
+
+		t.CheckErrorAndDeepEqual(false, err, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: skaffold-version
+    skaffold.dev/run-id: "1234"
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/managed-by: skaffold-version
+      name: web`, labelled.String())
+	})
+}
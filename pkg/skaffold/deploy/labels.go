@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/pkg/errors"
@@ -59,21 +60,42 @@ func merge(sources ...Labeller) map[string]string {
 	return merged
 }
 
+// setLabels adds the labels of the labellers to the manifests. The run id only labels
+// the deployed objects, and not their pod templates: it changes on every run and would
+// otherwise roll out new pods each time.
+func setLabels(manifests kubectl.ManifestList, labellers []Labeller) (kubectl.ManifestList, error) {
+	var all, topLevel []Labeller
+	for _, l := range labellers {
+		if _, ok := l.(*RunIDLabeller); ok {
+			topLevel = append(topLevel, l)
+		} else {
+			all = append(all, l)
+		}
+	}
+
+	manifests, err := manifests.SetLabels(merge(all...))
+	if err != nil {
+		return nil, err
+	}
+
+	return manifests.SetTopLevelLabels(merge(topLevel...))
+}
+
 // retry 3 times to give the object time to propagate to the API server
 const (
 	tries     = 3
 	sleeptime = 300 * time.Millisecond
 )
 
-func labelDeployResults(labels map[string]string, results []Artifact) {
+func labelDeployResults(kubeContext string, labels map[string]string, results []Artifact) {
 	// use the kubectl client to update all k8s objects with a skaffold watermark
-	dynClient, err := kubernetes.DynamicClient()
+	dynClient, err := kubernetes.DynamicClient(kubeContext)
 	if err != nil {
 		logrus.Warnf("error retrieving kubernetes dynamic client: %s", err.Error())
 		return
 	}
 
-	client, err := kubernetes.GetClientset()
+	client, err := kubernetes.Client(kubeContext)
 	if err != nil {
 		logrus.Warnf("error retrieving kubernetes client: %s", err.Error())
 		return
@@ -82,7 +104,7 @@ func labelDeployResults(labels map[string]string, results []Artifact) {
 	for _, res := range results {
 		err = nil
 		for i := 0; i < tries; i++ {
-			if err = updateRuntimeObject(dynClient, client.Discovery(), labels, res); err == nil {
+			if err = updateRuntimeObject(dynClient, client.Discovery(), kubeContext, labels, res); err == nil {
 				break
 			}
 			time.Sleep(sleeptime)
@@ -102,7 +124,7 @@ func addLabels(labels map[string]string, accessor metav1.Object) {
 	accessor.SetLabels(kv)
 }
 
-func updateRuntimeObject(client dynamic.Interface, disco discovery.DiscoveryInterface, labels map[string]string, res Artifact) error {
+func updateRuntimeObject(client dynamic.Interface, disco discovery.DiscoveryInterface, kubeContext string, labels map[string]string, res Artifact) error {
 	originalJSON, _ := json.Marshal(res.Obj)
 	modifiedObj := res.Obj.DeepCopyObject()
 	accessor, err := meta.Accessor(modifiedObj)
@@ -133,7 +155,7 @@ func updateRuntimeObject(client dynamic.Interface, disco discovery.DiscoveryInte
 		namespace = res.Namespace
 	}
 
-	ns, err := resolveNamespace(namespace)
+	ns, err := resolveNamespace(kubeContext, namespace)
 	if err != nil {
 		return errors.Wrap(err, "resolving namespace")
 	}
@@ -146,7 +168,7 @@ func updateRuntimeObject(client dynamic.Interface, disco discovery.DiscoveryInte
 	return nil
 }
 
-func resolveNamespace(ns string) (string, error) {
+func resolveNamespace(kubeContext, ns string) (string, error) {
 	if ns != "" {
 		return ns, nil
 	}
@@ -154,8 +176,11 @@ func resolveNamespace(ns string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "getting kubeconfig")
 	}
+	if kubeContext == "" {
+		kubeContext = cfg.CurrentContext
+	}
 
-	current, present := cfg.Contexts[cfg.CurrentContext]
+	current, present := cfg.Contexts[kubeContext]
 	if present && current.Namespace != "" {
 		return current.Namespace, nil
 	}
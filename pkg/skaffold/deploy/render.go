@@ -0,0 +1,169 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// RenderDeployer deploys the manifests rendered by a user provided command.
+type RenderDeployer struct {
+	*latest.RenderDeploy
+
+	workingDir         string
+	kubectl            kubectl.CLI
+	defaultRepo        string
+	insecureRegistries map[string]bool
+	imageFields        []latest.ImageField
+	validator          *kubectl.Validator
+}
+
+// NewRenderDeployer returns a new RenderDeployer for a DeployConfig filled
+// with the command that renders the manifests.
+func NewRenderDeployer(runCtx *runcontext.RunContext) *RenderDeployer {
+	return &RenderDeployer{
+		RenderDeploy: runCtx.Cfg.Deploy.RenderDeploy,
+		workingDir:   runCtx.WorkingDir,
+		kubectl: kubectl.CLI{
+			Namespace:   runCtx.Opts.Namespace,
+			KubeContext: runCtx.KubeContext,
+			Flags:       runCtx.Cfg.Deploy.RenderDeploy.Flags,
+			ForceDeploy: runCtx.Opts.ForceDeploy(),
+			Decrypter:   kubectl.NewDecrypter(runCtx.Cfg.Deploy.RenderDeploy.Sops),
+		},
+		defaultRepo:        runCtx.DefaultRepo,
+		insecureRegistries: runCtx.InsecureRegistries,
+		imageFields:        runCtx.Cfg.Deploy.ImageFields,
+		validator:          kubectl.NewValidator(runCtx.Cfg.Deploy.Validation, runCtx.WorkingDir),
+	}
+}
+
+// Labels returns the labels specific to the render deployer.
+func (r *RenderDeployer) Labels() map[string]string {
+	return map[string]string{
+		constants.Labels.Deployer: "render",
+	}
+}
+
+// Deploy runs `kubectl apply` on the manifests rendered by the command.
+func (r *RenderDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
+	color.Default.Fprintln(out, "kubectl client version:", r.kubectl.Version(ctx))
+	if err := r.kubectl.CheckVersion(ctx); err != nil {
+		color.Default.Fprintln(out, err)
+	}
+
+	event.DeployInProgress(r.kubectl.KubeContext)
+
+	manifests, err := r.readManifests(ctx)
+	if err != nil {
+		event.DeployFailed(r.kubectl.KubeContext, err)
+		return errors.Wrap(err, "reading manifests")
+	}
+
+	if len(manifests) == 0 {
+		event.DeployComplete(r.kubectl.KubeContext)
+		return nil
+	}
+
+	if err := r.validator.Validate(r.Command, manifests); err != nil {
+		event.DeployFailed(r.kubectl.KubeContext, err)
+		return errors.Wrap(err, "validating manifests")
+	}
+
+	manifests, err = manifests.ReplaceImages(builds, r.defaultRepo, r.imageFields)
+	if err != nil {
+		event.DeployFailed(r.kubectl.KubeContext, err)
+		return errors.Wrap(err, "replacing images in manifests")
+	}
+
+	manifests, err = setLabels(manifests, labellers)
+	if err != nil {
+		event.DeployFailed(r.kubectl.KubeContext, err)
+		return errors.Wrap(err, "setting labels in manifests")
+	}
+
+	manifests, err = applyManifestTransforms(manifests, builds, r.insecureRegistries)
+	if err != nil {
+		event.DeployFailed(r.kubectl.KubeContext, err)
+		return err
+	}
+
+	if err := r.kubectl.Apply(ctx, out, manifests); err != nil {
+		event.DeployFailed(r.kubectl.KubeContext, err)
+		return errors.Wrap(err, "kubectl error")
+	}
+
+	event.DeployComplete(r.kubectl.KubeContext)
+	return nil
+}
+
+// Cleanup deletes what was deployed by calling Deploy.
+func (r *RenderDeployer) Cleanup(ctx context.Context, out io.Writer) error {
+	manifests, err := r.readManifests(ctx)
+	if err != nil {
+		return errors.Wrap(err, "reading manifests")
+	}
+
+	if err := r.kubectl.Delete(ctx, out, manifests); err != nil {
+		return errors.Wrap(err, "delete")
+	}
+
+	return nil
+}
+
+// Dependencies lists the files used to render the manifests.
+func (r *RenderDeployer) Dependencies() ([]string, error) {
+	deps, err := util.ExpandPathsGlob(r.workingDir, r.RenderDeploy.Dependencies)
+	if err != nil {
+		return nil, errors.Wrap(err, "expanding render dependencies")
+	}
+
+	return deps, nil
+}
+
+// readManifests runs the command with `sh -c` and parses the YAML stream it outputs.
+func (r *RenderDeployer) readManifests(ctx context.Context) (kubectl.ManifestList, error) {
+	if strings.TrimSpace(r.Command) == "" {
+		return nil, errors.New("empty render command")
+	}
+
+	// The command is run by a shell, so that it can use quotes, pipes and redirections.
+	cmd := exec.CommandContext(ctx, "sh", "-c", r.Command)
+	cmd.Dir = r.workingDir
+	out, err := util.RunCmdOut(cmd)
+	if err != nil {
+		return nil, errors.Wrapf(err, "rendering manifests with %q", r.Command)
+	}
+
+	var manifests kubectl.ManifestList
+	manifests.AppendStream(out)
+
+	return r.kubectl.Decrypter.DecryptManifests(ctx, manifests)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func newRenderDeployer(cfg *latest.RenderDeploy) *RenderDeployer {
+	runCtx := &runcontext.RunContext{
+		WorkingDir: ".",
+		Cfg: &latest.Pipeline{
+			Deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					RenderDeploy: cfg,
+				},
+			},
+		},
+		KubeContext: testKubeContext,
+		Opts: &config.SkaffoldOptions{
+			Namespace: testNamespace,
+		},
+	}
+	event.InitializeState(runCtx)
+
+	return NewRenderDeployer(runCtx)
+}
+
+func TestRenderDeploy(t *testing.T) {
+	var tests = []struct {
+		description string
+		render      string
+		command     util.Command
+		shouldErr   bool
+	}{
+		{
+			description: "no manifest",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("sh -c jsonnet -y app.jsonnet", ""),
+		},
+		{
+			description: "deploy success",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("sh -c jsonnet -y app.jsonnet", "---\n"+deploymentWebYAML+"\n---\n"+deploymentAppYAML).
+				WithRun("kubectl --context kubecontext --namespace testNamespace apply -f -"),
+		},
+		{
+			description: "shell command",
+			render:      "jsonnet -y 'app.jsonnet' | sed 's/v1/v2/'",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("sh -c jsonnet -y 'app.jsonnet' | sed 's/v1/v2/'", deploymentWebYAML).
+				WithRun("kubectl --context kubecontext --namespace testNamespace apply -f -"),
+		},
+		{
+			description: "render error",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOutErr("sh -c jsonnet -y app.jsonnet", "", fmt.Errorf("syntax error")),
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&util.DefaultExecCommand, test.command)
+			render := test.render
+			if render == "" {
+				render = "jsonnet -y app.jsonnet"
+			}
+
+			r := newRenderDeployer(&latest.RenderDeploy{Command: render})
+			err := r.Deploy(context.Background(), ioutil.Discard, []build.Artifact{{
+				ImageName: "leeroy-web",
+				Tag:       "leeroy-web:123",
+			}}, nil)
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestRenderCleanup(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&util.DefaultExecCommand, testutil.NewFakeCmd(t.T).
+			WithRunOut("sh -c jsonnet -y app.jsonnet", deploymentWebYAML).
+			WithRun("kubectl --context kubecontext --namespace testNamespace delete --ignore-not-found=true -f -"))
+
+		r := newRenderDeployer(&latest.RenderDeploy{Command: "jsonnet -y app.jsonnet"})
+		err := r.Cleanup(context.Background(), ioutil.Discard)
+
+		t.CheckNoError(err)
+	})
+}
+
+func TestRenderDependencies(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.NewTempDir().
+			Touch("app.jsonnet", "lib/utils.libsonnet", "lib/ignored.txt").
+			Chdir()
+
+		r := newRenderDeployer(&latest.RenderDeploy{
+			Command:      "jsonnet -y app.jsonnet",
+			Dependencies: []string{"*.jsonnet", "lib/*.libsonnet"},
+		})
+		deps, err := r.Dependencies()
+
+		t.CheckErrorAndDeepEqual(false, err, []string{"app.jsonnet", "lib/utils.libsonnet"}, deps)
+	})
+}
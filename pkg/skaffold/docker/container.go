@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// RunContainer creates and starts a container attached to a network.
+func (l *localDaemon) RunContainer(ctx context.Context, name string, config *container.Config, hostConfig *container.HostConfig, networkName string) (string, error) {
+	var networkingConfig *network.NetworkingConfig
+	if networkName != "" {
+		hostConfig.NetworkMode = container.NetworkMode(networkName)
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				networkName: {Aliases: []string{name}},
+			},
+		}
+	}
+
+	created, err := l.apiClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "creating container %s", name)
+	}
+
+	if err := l.apiClient.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		return "", errors.Wrapf(err, "starting container %s", name)
+	}
+
+	return created.ID, nil
+}
+
+// RemoveContainer stops and removes a container. It's not an error if the container doesn't exist.
+func (l *localDaemon) RemoveContainer(ctx context.Context, name string) error {
+	err := l.apiClient.ContainerRemove(ctx, name, types.ContainerRemoveOptions{Force: true})
+	if err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "removing container %s", name)
+	}
+
+	return nil
+}
+
+// Containers lists the containers that have all the given labels.
+// Stopped containers are only listed if all is true.
+func (l *localDaemon) Containers(ctx context.Context, labels map[string]string, all bool) ([]types.Container, error) {
+	args := filters.NewArgs()
+	for k, v := range labels {
+		args.Add("label", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	return l.apiClient.ContainerList(ctx, types.ContainerListOptions{All: all, Filters: args})
+}
+
+// ContainerLogs follows the logs of a container, since a given time, until the container exits.
+func (l *localDaemon) ContainerLogs(ctx context.Context, w io.Writer, id string, since time.Time) error {
+	logs, err := l.apiClient.ContainerLogs(ctx, id, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Since:      fmt.Sprintf("%d", since.Unix()),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "getting logs of container %s", id)
+	}
+	defer logs.Close()
+
+	return demuxLogs(w, logs)
+}
+
+// demuxLogs copies the logs of a container run without a TTY. Such logs are
+// a sequence of frames, each with an 8 bytes header that ends with the size of the frame.
+func demuxLogs(w io.Writer, r io.Reader) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "reading log frame header")
+		}
+
+		size := binary.BigEndian.Uint32(header[4:])
+		if _, err := io.CopyN(w, r, int64(size)); err != nil {
+			return errors.Wrap(err, "reading log frame")
+		}
+	}
+}
+
+// CreateNetwork creates a network, unless it already exists.
+func (l *localDaemon) CreateNetwork(ctx context.Context, name string, labels map[string]string) error {
+	networks, err := l.apiClient.NetworkList(ctx, types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", name)),
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing networks")
+	}
+
+	for _, n := range networks {
+		// The name filter matches names partially.
+		if n.Name == name {
+			return nil
+		}
+	}
+
+	if _, err := l.apiClient.NetworkCreate(ctx, name, types.NetworkCreate{CheckDuplicate: true, Labels: labels}); err != nil {
+		return errors.Wrapf(err, "creating network %s", name)
+	}
+
+	return nil
+}
+
+// RemoveNetwork removes a network. It's not an error if the network doesn't exist.
+func (l *localDaemon) RemoveNetwork(ctx context.Context, name string) error {
+	err := l.apiClient.NetworkRemove(ctx, name)
+	if err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "removing network %s", name)
+	}
+
+	return nil
+}
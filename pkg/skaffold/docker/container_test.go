@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDemuxLogs(t *testing.T) {
+	frames := []byte{}
+	frames = append(frames, 1, 0, 0, 0, 0, 0, 0, 6)
+	frames = append(frames, []byte("hello\n")...)
+	frames = append(frames, 2, 0, 0, 0, 0, 0, 0, 7)
+	frames = append(frames, []byte("error!\n")...)
+
+	var out bytes.Buffer
+	err := demuxLogs(&out, bytes.NewReader(frames))
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, "hello\nerror!\n", out.String())
+}
+
+func TestDemuxTruncatedLogs(t *testing.T) {
+	frames := []byte{1, 0, 0, 0, 0, 0, 0, 6, 'h', 'e'}
+
+	var out bytes.Buffer
+	err := demuxLogs(&out, bytes.NewReader(frames))
+
+	testutil.CheckError(t, true, err)
+}
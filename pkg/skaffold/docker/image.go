@@ -24,10 +24,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/progress"
@@ -55,6 +57,12 @@ type LocalDaemon interface {
 	RepoDigest(ctx context.Context, ref string) (string, error)
 	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
 	ImageExists(ctx context.Context, ref string) bool
+	RunContainer(ctx context.Context, name string, config *container.Config, hostConfig *container.HostConfig, networkName string) (string, error)
+	RemoveContainer(ctx context.Context, name string) error
+	Containers(ctx context.Context, labels map[string]string, all bool) ([]types.Container, error)
+	ContainerLogs(ctx context.Context, w io.Writer, id string, since time.Time) error
+	CreateNetwork(ctx context.Context, name string, labels map[string]string) error
+	RemoveNetwork(ctx context.Context, name string) error
 }
 
 type localDaemon struct {
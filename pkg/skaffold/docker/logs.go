@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runlog"
+	"github.com/docker/docker/api/types"
+	"github.com/sirupsen/logrus"
+)
+
+// logsPollInterval is how often the running containers are listed.
+var logsPollInterval = time.Second
+
+// LogAggregator aggregates the logs of the containers run on the local Docker daemon.
+type LogAggregator struct {
+	output      io.Writer
+	localDocker LocalDaemon
+	labels      map[string]string
+	colorPicker kubernetes.ColorPicker
+	printer     *kubernetes.LogPrinter
+	archive     *runlog.Archive
+
+	muted     int32
+	startTime time.Time
+	cancel    context.CancelFunc
+
+	trackedLock sync.Mutex
+	tracked     map[string]bool
+}
+
+// NewLogAggregator creates a new LogAggregator for the containers that have all the given labels.
+// printer filters and formats the lines of logs. archive, if not nil, receives every line of logs,
+// even the muted or filtered out ones.
+func NewLogAggregator(out io.Writer, localDocker LocalDaemon, baseImageNames []string, labels map[string]string, printer *kubernetes.LogPrinter, archive *runlog.Archive) *LogAggregator {
+	return &LogAggregator{
+		output:      out,
+		localDocker: localDocker,
+		labels:      labels,
+		colorPicker: kubernetes.NewColorPicker(baseImageNames),
+		printer:     printer,
+		archive:     archive,
+		tracked:     map[string]bool{},
+	}
+}
+
+// Start starts a logger that lists the running containers and tails their logs.
+func (a *LogAggregator) Start(ctx context.Context) error {
+	cancelCtx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+	a.startTime = time.Now()
+
+	go func() {
+		ticker := time.NewTicker(logsPollInterval)
+		defer ticker.Stop()
+
+		for {
+			a.streamNewContainers(cancelCtx)
+
+			select {
+			case <-cancelCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (a *LogAggregator) streamNewContainers(ctx context.Context) {
+	// Containers that already exited are listed too, so that the logs of a crash aren't missed.
+	containers, err := a.localDocker.Containers(ctx, a.labels, true)
+	if err != nil {
+		logrus.Debugln("listing containers:", err)
+		return
+	}
+
+	for _, c := range containers {
+		if a.track(c.ID) {
+			go a.streamContainerLogs(ctx, c)
+		}
+	}
+}
+
+// track returns true if the container wasn't tracked yet.
+func (a *LogAggregator) track(id string) bool {
+	a.trackedLock.Lock()
+	defer a.trackedLock.Unlock()
+
+	if a.tracked[id] {
+		return false
+	}
+	a.tracked[id] = true
+	return true
+}
+
+func (a *LogAggregator) streamContainerLogs(ctx context.Context, c types.Container) {
+	name := ContainerName(c)
+	logrus.Infof("Stream logs from container: %s", name)
+
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(a.localDocker.ContainerLogs(ctx, w, c.ID, a.startTime))
+	}()
+
+	headerColor := a.colorPicker.PickImage(c.Image)
+	header := fmt.Sprintf("[%s]", name)
+	source := kubernetes.LogSource{
+		Container: name,
+		Image:     c.Image,
+		Labels:    c.Labels,
+	}
+	archive, err := a.archive.Writer("containers", name+".log")
+	if err != nil {
+		logrus.Warnf("archiving logs of %s: %s", header, err)
+		archive = ioutil.Discard
+	}
+	if err := kubernetes.StreamLogs(ctx, a.output, a.printer, headerColor, header, source, r, archive, a.IsMuted); err != nil {
+		logrus.Errorf("streaming logs %s", err)
+	}
+}
+
+// ContainerName returns the name of a container, without the leading `/`.
+func ContainerName(c types.Container) string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// Stop stops the logger.
+func (a *LogAggregator) Stop() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// Mute mutes the logs.
+func (a *LogAggregator) Mute() {
+	atomic.StoreInt32(&a.muted, 1)
+}
+
+// Unmute unmutes the logs.
+func (a *LogAggregator) Unmute() {
+	atomic.StoreInt32(&a.muted, 0)
+}
+
+// IsMuted says if the logs are to be muted.
+func (a *LogAggregator) IsMuted() bool {
+	return atomic.LoadInt32(&a.muted) == 1
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runlog"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/docker/docker/api/types"
+)
+
+type fakeContainers struct {
+	LocalDaemon
+}
+
+// Containers lists a container that already exited.
+func (f *fakeContainers) Containers(_ context.Context, labels map[string]string, all bool) ([]types.Container, error) {
+	if labels["skaffold.dev/deployer"] != "docker" || !all {
+		return nil, nil
+	}
+	return []types.Container{{ID: "id", Names: []string{"/web"}, Image: "web:v1", State: "exited"}}, nil
+}
+
+func (f *fakeContainers) ContainerLogs(_ context.Context, w io.Writer, id string, _ time.Time) error {
+	_, err := fmt.Fprintf(w, "hello from %s\n", id)
+	return err
+}
+
+// lockedBuffer is a buffer safe for concurrent use.
+type lockedBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func TestLogAggregator(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&logsPollInterval, 10*time.Millisecond)
+
+		tmpDir := t.NewTempDir()
+		archive, err := runlog.New(tmpDir.Root(), "1234", 1024, 1)
+		t.CheckNoError(err)
+
+		var out lockedBuffer
+		logger := NewLogAggregator(&out, &fakeContainers{}, []string{"other"}, map[string]string{"skaffold.dev/deployer": "docker"}, nil, archive)
+
+		err = logger.Start(context.Background())
+		t.CheckNoError(err)
+		defer logger.Stop()
+
+		for i := 0; i < 100 && !strings.HasSuffix(out.String(), "\n"); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		t.CheckDeepEqual("[web] hello from id\n", out.String())
+
+		logger.Stop()
+		t.CheckNoError(archive.Close())
+		content, err := ioutil.ReadFile(tmpDir.Path("1234/containers/web.log"))
+		t.CheckNoError(err)
+		t.CheckDeepEqual("hello from id\n", string(content))
+	})
+}
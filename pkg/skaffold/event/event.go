@@ -20,12 +20,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 )
 
 const (
@@ -33,11 +35,18 @@ const (
 	InProgress = "In Progress"
 	Complete   = "Complete"
 	Failed     = "Failed"
+
+	Forwarded     = "Forwarded"
+	Lost          = "Lost"
+	Reestablished = "Re-established"
 )
 
 var (
 	handler *eventHandler
 	once    sync.Once
+
+	// for testing
+	now = time.Now
 )
 
 type eventHandler struct {
@@ -47,7 +56,14 @@ type eventHandler struct {
 	state     proto.State
 	stateLock sync.Mutex
 
-	listeners []listener
+	listeners []*listener
+
+	timingLock sync.Mutex
+	started    map[string]time.Time
+
+	queueLock sync.Mutex
+	queue     []*proto.LogEntry
+	handling  bool
 }
 
 type listener struct {
@@ -67,7 +83,7 @@ func ForEachEvent(callback func(*proto.LogEntry) error) error {
 
 func Handle(event *proto.Event) error {
 	if event != nil {
-		handler.handle(event)
+		handler.enqueue(event)
 	}
 	return nil
 }
@@ -103,7 +119,7 @@ func (ev *eventHandler) logEvent(entry proto.LogEntry) {
 }
 
 func (ev *eventHandler) forEachEvent(callback func(*proto.LogEntry) error) error {
-	listener := listener{
+	listener := &listener{
 		callback: callback,
 		errors:   make(chan error),
 	}
@@ -139,54 +155,318 @@ func emptyState(build *latest.BuildConfig) proto.State {
 			Artifacts: builds,
 		},
 		DeployState: &proto.DeployState{
-			Status: NotStarted,
+			Status:       NotStarted,
+			KubeContexts: map[string]string{},
 		},
 		ForwardedPorts: make(map[string]*proto.PortEvent),
+		TestState: &proto.TestState{
+			Status: proto.PhaseStatus_NOT_STARTED,
+		},
+		SyncState: &proto.SyncState{
+			Artifacts: map[string]*proto.SyncEvent{},
+		},
+		StatusCheckState: &proto.StatusCheckState{
+			Status: proto.PhaseStatus_NOT_STARTED,
+		},
+		DevLoopState: &proto.DevLoopState{
+			Status: proto.PhaseStatus_NOT_STARTED,
+		},
+		Resources: map[string]*proto.ResourceStatusEvent{},
 	}
 }
 
 // InitializeState instantiates the global state of the skaffold runner, as well as the event log.
 func InitializeState(runCtx *runcontext.RunContext) {
 	once.Do(func() {
+		state := emptyState(&runCtx.Cfg.Build)
+		for _, kubeContext := range runCtx.Cfg.Deploy.KubeContexts {
+			state.DeployState.KubeContexts[kubeContext.Name] = NotStarted
+		}
 		handler = &eventHandler{
-			state: emptyState(&runCtx.Cfg.Build),
+			state: state,
 		}
 	})
 }
 
-// DeployInProgress notifies that a deployment has been started.
-func DeployInProgress() {
-	handler.handleDeployEvent(&proto.DeployEvent{Status: InProgress})
+// DeployInProgress notifies that a deployment to a kubectl context has been started.
+func DeployInProgress(kubeContext string) {
+	handler.handleDeployEvent(&proto.DeployEvent{
+		Status:      InProgress,
+		PhaseStatus: proto.PhaseStatus_IN_PROGRESS,
+		KubeContext: kubeContext,
+		Timing:      handler.timing("deploy/"+kubeContext, proto.PhaseStatus_IN_PROGRESS),
+	})
 }
 
-// DeployFailed notifies that a deployment has failed.
-func DeployFailed(err error) {
-	handler.handleDeployEvent(&proto.DeployEvent{Status: Failed, Err: err.Error()})
+// DeployFailed notifies that a deployment to a kubectl context has failed.
+func DeployFailed(kubeContext string, err error) {
+	handler.handleDeployEvent(&proto.DeployEvent{
+		Status:      Failed,
+		PhaseStatus: proto.PhaseStatus_FAILED,
+		Err:         err.Error(),
+		ErrCode:     proto.ErrorCode_DEPLOY_FAILED,
+		KubeContext: kubeContext,
+		Timing:      handler.timing("deploy/"+kubeContext, proto.PhaseStatus_FAILED),
+	})
 }
 
-// DeployComplete notifies that a deployment has completed.
-func DeployComplete() {
-	handler.handleDeployEvent(&proto.DeployEvent{Status: Complete})
+// DeployComplete notifies that a deployment to a kubectl context has completed.
+func DeployComplete(kubeContext string) {
+	handler.handleDeployEvent(&proto.DeployEvent{
+		Status:      Complete,
+		PhaseStatus: proto.PhaseStatus_SUCCEEDED,
+		KubeContext: kubeContext,
+		Timing:      handler.timing("deploy/"+kubeContext, proto.PhaseStatus_SUCCEEDED),
+	})
 }
 
 // BuildInProgress notifies that a build has been started.
 func BuildInProgress(imageName string) {
-	handler.handleBuildEvent(&proto.BuildEvent{Artifact: imageName, Status: InProgress})
+	handler.handleBuildEvent(&proto.BuildEvent{
+		Artifact:    imageName,
+		Status:      InProgress,
+		PhaseStatus: proto.PhaseStatus_IN_PROGRESS,
+		Timing:      handler.timing("build/"+imageName, proto.PhaseStatus_IN_PROGRESS),
+	})
 }
 
 // BuildFailed notifies that a build has failed.
 func BuildFailed(imageName string, err error) {
-	handler.handleBuildEvent(&proto.BuildEvent{Artifact: imageName, Status: Failed, Err: err.Error()})
+	handler.handleBuildEvent(&proto.BuildEvent{
+		Artifact:    imageName,
+		Status:      Failed,
+		PhaseStatus: proto.PhaseStatus_FAILED,
+		Err:         err.Error(),
+		ErrCode:     proto.ErrorCode_BUILD_FAILED,
+		Timing:      handler.timing("build/"+imageName, proto.PhaseStatus_FAILED),
+	})
 }
 
 // BuildComplete notifies that a build has completed.
 func BuildComplete(imageName string) {
-	handler.handleBuildEvent(&proto.BuildEvent{Artifact: imageName, Status: Complete})
+	handler.handleBuildEvent(&proto.BuildEvent{
+		Artifact:    imageName,
+		Status:      Complete,
+		PhaseStatus: proto.PhaseStatus_SUCCEEDED,
+		Timing:      handler.timing("build/"+imageName, proto.PhaseStatus_SUCCEEDED),
+	})
+}
+
+// FileChanged notifies that files of a component watched by the dev loop have changed.
+// component is one of artifact, test, deploy or config. artifact is only set for artifacts.
+func FileChanged(component, artifact string, added, modified, deleted []string) {
+	handler.enqueue(&proto.Event{
+		EventType: &proto.Event_FileChangeEvent{
+			FileChangeEvent: &proto.FileChangeEvent{
+				Component: component,
+				Artifact:  artifact,
+				Added:     added,
+				Modified:  modified,
+				Deleted:   deleted,
+			},
+		},
+	})
+}
+
+// SyncInProgress notifies that files are being synced to the containers running an artifact.
+func SyncInProgress(artifact string, copied, deleted []string) {
+	handler.handleSyncEvent(artifact, proto.PhaseStatus_IN_PROGRESS, copied, deleted, nil)
+}
+
+// SyncFailed notifies that files couldn't be synced.
+func SyncFailed(artifact string, copied, deleted []string, err error) {
+	handler.handleSyncEvent(artifact, proto.PhaseStatus_FAILED, copied, deleted, err)
+}
+
+// SyncComplete notifies that files were synced.
+func SyncComplete(artifact string, copied, deleted []string) {
+	handler.handleSyncEvent(artifact, proto.PhaseStatus_SUCCEEDED, copied, deleted, nil)
+}
+
+func (ev *eventHandler) handleSyncEvent(artifact string, status proto.PhaseStatus, copied, deleted []string, err error) {
+	e := &proto.SyncEvent{
+		Artifact: artifact,
+		Status:   status,
+		Copied:   copied,
+		Deleted:  deleted,
+		Timing:   ev.timing("sync/"+artifact, status),
+	}
+	if err != nil {
+		e.Err = err.Error()
+		e.ErrCode = proto.ErrorCode_SYNC_FAILED
+	}
+
+	ev.enqueue(&proto.Event{
+		EventType: &proto.Event_SyncEvent{
+			SyncEvent: e,
+		},
+	})
+}
+
+// TestInProgress notifies that the tests have been started.
+func TestInProgress() {
+	handler.handleTestEvent(proto.PhaseStatus_IN_PROGRESS, nil)
+}
+
+// TestFailed notifies that the tests have failed.
+func TestFailed(err error) {
+	handler.handleTestEvent(proto.PhaseStatus_FAILED, err)
+}
+
+// TestComplete notifies that the tests have passed.
+func TestComplete() {
+	handler.handleTestEvent(proto.PhaseStatus_SUCCEEDED, nil)
+}
+
+func (ev *eventHandler) handleTestEvent(status proto.PhaseStatus, err error) {
+	e := &proto.TestEvent{
+		Status: status,
+		Timing: ev.timing("test", status),
+	}
+	if err != nil {
+		e.Err = err.Error()
+		e.ErrCode = proto.ErrorCode_TEST_FAILED
+	}
+
+	ev.enqueue(&proto.Event{
+		EventType: &proto.Event_TestEvent{
+			TestEvent: e,
+		},
+	})
+}
+
+// StatusCheckInProgress notifies that the check of the deployed resources has been started.
+func StatusCheckInProgress() {
+	handler.handleStatusCheckEvent(proto.PhaseStatus_IN_PROGRESS, nil)
+}
+
+// StatusCheckFailed notifies that some deployed resources are not healthy.
+func StatusCheckFailed(err error) {
+	handler.handleStatusCheckEvent(proto.PhaseStatus_FAILED, err)
+}
+
+// StatusCheckComplete notifies that all the deployed resources are healthy.
+func StatusCheckComplete() {
+	handler.handleStatusCheckEvent(proto.PhaseStatus_SUCCEEDED, nil)
+}
+
+func (ev *eventHandler) handleStatusCheckEvent(status proto.PhaseStatus, err error) {
+	e := &proto.StatusCheckEvent{
+		Status: status,
+		Timing: ev.timing("status-check", status),
+	}
+	if err != nil {
+		e.Err = err.Error()
+		e.ErrCode = proto.ErrorCode_STATUS_CHECK_FAILED
+	}
+
+	ev.enqueue(&proto.Event{
+		EventType: &proto.Event_StatusCheckEvent{
+			StatusCheckEvent: e,
+		},
+	})
+}
+
+// DevLoopInProgress notifies that an iteration of the dev loop has been started.
+func DevLoopInProgress(iteration int) {
+	handler.handleDevLoopEvent(iteration, proto.PhaseStatus_IN_PROGRESS, nil)
+}
+
+// DevLoopFailed notifies that an iteration of the dev loop has failed.
+func DevLoopFailed(iteration int, err error) {
+	handler.handleDevLoopEvent(iteration, proto.PhaseStatus_FAILED, err)
+}
+
+// DevLoopComplete notifies that an iteration of the dev loop has completed.
+func DevLoopComplete(iteration int) {
+	handler.handleDevLoopEvent(iteration, proto.PhaseStatus_SUCCEEDED, nil)
+}
+
+func (ev *eventHandler) handleDevLoopEvent(iteration int, status proto.PhaseStatus, err error) {
+	e := &proto.DevLoopEvent{
+		Iteration: int32(iteration),
+		Status:    status,
+		Timing:    ev.timing(fmt.Sprintf("dev-loop/%d", iteration), status),
+	}
+	if err != nil {
+		e.Err = err.Error()
+		e.ErrCode = proto.ErrorCode_DEV_LOOP_FAILED
+	}
+
+	ev.enqueue(&proto.Event{
+		EventType: &proto.Event_DevLoopEvent{
+			DevLoopEvent: e,
+		},
+	})
+}
+
+// ResourceStatusChanged notifies that the status of a deployed resource, like `pod/web`, has changed.
+func ResourceStatusChanged(resource, namespace, kubeContext, status string, ready bool, message string) {
+	handler.enqueue(&proto.Event{
+		EventType: &proto.Event_ResourceStatusEvent{
+			ResourceStatusEvent: &proto.ResourceStatusEvent{
+				Resource:    resource,
+				Namespace:   namespace,
+				KubeContext: kubeContext,
+				Status:      status,
+				Ready:       ready,
+				Message:     message,
+			},
+		},
+	})
+}
+
+// timing records the start of a phase, identified by key, or computes its timing once it's over.
+// It's called before the events are handled asynchronously, so that their timings are accurate.
+func (ev *eventHandler) timing(key string, status proto.PhaseStatus) *proto.Timing {
+	ev.timingLock.Lock()
+	defer ev.timingLock.Unlock()
+
+	if ev.started == nil {
+		ev.started = map[string]time.Time{}
+	}
+
+	t := now()
+	if status == proto.PhaseStatus_IN_PROGRESS {
+		ev.started[key] = t
+		return &proto.Timing{StartTime: timestampProto(t)}
+	}
+
+	start, found := ev.started[key]
+	if !found {
+		start = t
+	}
+	delete(ev.started, key)
+
+	return &proto.Timing{
+		StartTime: timestampProto(start),
+		EndTime:   timestampProto(t),
+		Duration:  ptypes.DurationProto(t.Sub(start)),
+	}
+}
+
+func timestampProto(t time.Time) *timestamp.Timestamp {
+	ts, _ := ptypes.TimestampProto(t)
+	return ts
 }
 
 // PortForwarded notifies that a remote port has been forwarded locally.
-func PortForwarded(localPort, remotePort int32, podName, containerName, namespace string, portName string, resourceType, resourceName string) {
-	go handler.handle(&proto.Event{
+func PortForwarded(localPort, remotePort int32, podName, containerName, namespace string, portName string, resourceType, resourceName, kubeContext string) {
+	handler.handlePortEvent(Forwarded, localPort, remotePort, podName, containerName, namespace, portName, resourceType, resourceName, kubeContext)
+}
+
+// PortForwardLost notifies that the pod behind a forwarded port went away.
+func PortForwardLost(localPort, remotePort int32, podName, containerName, namespace string, portName string, resourceType, resourceName, kubeContext string) {
+	handler.handlePortEvent(Lost, localPort, remotePort, podName, containerName, namespace, portName, resourceType, resourceName, kubeContext)
+}
+
+// PortForwardReestablished notifies that a lost port forward now goes to a new pod, on the same local port.
+func PortForwardReestablished(localPort, remotePort int32, podName, containerName, namespace string, portName string, resourceType, resourceName, kubeContext string) {
+	handler.handlePortEvent(Reestablished, localPort, remotePort, podName, containerName, namespace, portName, resourceType, resourceName, kubeContext)
+}
+
+func (ev *eventHandler) handlePortEvent(status string, localPort, remotePort int32, podName, containerName, namespace string, portName string, resourceType, resourceName, kubeContext string) {
+	ev.enqueue(&proto.Event{
 		EventType: &proto.Event_PortEvent{
 			PortEvent: &proto.PortEvent{
 				LocalPort:     localPort,
@@ -197,13 +477,33 @@ func PortForwarded(localPort, remotePort int32, podName, containerName, namespac
 				PortName:      portName,
 				ResourceType:  resourceType,
 				ResourceName:  resourceName,
+				KubeContext:   kubeContext,
+				Status:        status,
+			},
+		},
+	})
+}
+
+// PodDiagnosed notifies that a container of the run crashed or doesn't start, and explains why.
+func PodDiagnosed(podName, containerName, namespace, kubeContext, reason, message string, logs, events []string) {
+	handler.enqueue(&proto.Event{
+		EventType: &proto.Event_PodDiagnosticEvent{
+			PodDiagnosticEvent: &proto.PodDiagnosticEvent{
+				PodName:       podName,
+				ContainerName: containerName,
+				Namespace:     namespace,
+				KubeContext:   kubeContext,
+				Reason:        reason,
+				Message:       message,
+				Logs:          logs,
+				Events:        events,
 			},
 		},
 	})
 }
 
 func (ev *eventHandler) handleDeployEvent(e *proto.DeployEvent) {
-	go ev.handle(&proto.Event{
+	ev.enqueue(&proto.Event{
 		EventType: &proto.Event_DeployEvent{
 			DeployEvent: e,
 		},
@@ -211,7 +511,7 @@ func (ev *eventHandler) handleDeployEvent(e *proto.DeployEvent) {
 }
 
 func (ev *eventHandler) handleBuildEvent(e *proto.BuildEvent) {
-	go ev.handle(&proto.Event{
+	ev.enqueue(&proto.Event{
 		EventType: &proto.Event_BuildEvent{
 			BuildEvent: e,
 		},
@@ -219,25 +519,86 @@ func (ev *eventHandler) handleBuildEvent(e *proto.BuildEvent) {
 }
 
 func LogSkaffoldMetadata(info *version.Info) {
-	handler.logEvent(proto.LogEntry{
-		Timestamp: ptypes.TimestampNow(),
-		Event: &proto.Event{
-			EventType: &proto.Event_MetaEvent{
-				MetaEvent: &proto.MetaEvent{
-					Entry: fmt.Sprintf("Starting Skaffold: %+v", info),
-				},
+	handler.enqueue(&proto.Event{
+		EventType: &proto.Event_MetaEvent{
+			MetaEvent: &proto.MetaEvent{
+				Entry: fmt.Sprintf("Starting Skaffold: %+v", info),
 			},
 		},
 	})
 }
 
-func (ev *eventHandler) handle(event *proto.Event) {
-	logEntry := &proto.LogEntry{
+// enqueue handles an event in the background, without waiting for the listeners.
+// The queue is unbounded, so that a slow listener never blocks the code that emits events.
+// The events are handled one at a time, in the order they were enqueued, so that
+// the state and the event log never go back to an older status.
+func (ev *eventHandler) enqueue(event *proto.Event) {
+	ev.queueLock.Lock()
+	defer ev.queueLock.Unlock()
+
+	ev.queue = append(ev.queue, &proto.LogEntry{
 		Timestamp: ptypes.TimestampNow(),
 		Event:     event,
+	})
+	if !ev.handling {
+		ev.handling = true
+		go ev.handleQueue()
+	}
+}
+
+// handleQueue handles the enqueued events until the queue is empty.
+func (ev *eventHandler) handleQueue() {
+	for {
+		ev.queueLock.Lock()
+		if len(ev.queue) == 0 {
+			ev.handling = false
+			ev.queueLock.Unlock()
+			return
+		}
+		logEntry := ev.queue[0]
+		ev.queue[0] = nil
+		ev.queue = ev.queue[1:]
+		ev.queueLock.Unlock()
+
+		ev.handle(logEntry)
+	}
+}
+
+// updateDeployState records the status of a deployment to a kubectl context
+// and recomputes the overall status from the status of every context.
+func updateDeployState(state *proto.DeployState, de *proto.DeployEvent) {
+	if de.Status == InProgress && state.Status != InProgress {
+		// A new deployment starts: the previous status of the other contexts is stale.
+		for kubeContext := range state.KubeContexts {
+			state.KubeContexts[kubeContext] = NotStarted
+		}
+	}
+	state.KubeContexts[de.KubeContext] = de.Status
+	state.Status = aggregateDeployStatus(state.KubeContexts)
+}
+
+// aggregateDeployStatus is Failed if any context failed, Complete if every context
+// was deployed, NotStarted if none was started and InProgress otherwise.
+func aggregateDeployStatus(kubeContexts map[string]string) string {
+	counts := map[string]int{}
+	for _, status := range kubeContexts {
+		counts[status]++
 	}
 
-	switch e := event.GetEventType().(type) {
+	switch {
+	case counts[Failed] > 0:
+		return Failed
+	case counts[Complete] == len(kubeContexts):
+		return Complete
+	case counts[NotStarted] == len(kubeContexts):
+		return NotStarted
+	default:
+		return InProgress
+	}
+}
+
+func (ev *eventHandler) handle(logEntry *proto.LogEntry) {
+	switch e := logEntry.Event.GetEventType().(type) {
 	case *proto.Event_BuildEvent:
 		be := e.BuildEvent
 		ev.stateLock.Lock()
@@ -256,15 +617,15 @@ func (ev *eventHandler) handle(event *proto.Event) {
 	case *proto.Event_DeployEvent:
 		de := e.DeployEvent
 		ev.stateLock.Lock()
-		ev.state.DeployState.Status = de.Status
+		updateDeployState(ev.state.DeployState, de)
 		ev.stateLock.Unlock()
 		switch de.Status {
 		case InProgress:
-			logEntry.Entry = "Deploy started"
+			logEntry.Entry = fmt.Sprintf("Deploy started for context %s", de.KubeContext)
 		case Complete:
-			logEntry.Entry = "Deploy complete"
+			logEntry.Entry = fmt.Sprintf("Deploy complete for context %s", de.KubeContext)
 		case Failed:
-			logEntry.Entry = "Deploy failed"
+			logEntry.Entry = fmt.Sprintf("Deploy failed for context %s", de.KubeContext)
 			// logEntry.Err = de.Err
 		default:
 		}
@@ -273,7 +634,89 @@ func (ev *eventHandler) handle(event *proto.Event) {
 		ev.stateLock.Lock()
 		ev.state.ForwardedPorts[pe.ContainerName] = pe
 		ev.stateLock.Unlock()
-		logEntry.Entry = fmt.Sprintf("Forwarding container %s to local port %d", pe.ContainerName, pe.LocalPort)
+		switch pe.Status {
+		case Lost:
+			logEntry.Entry = fmt.Sprintf("Lost pod %s forwarded to local port %d", pe.PodName, pe.LocalPort)
+		case Reestablished:
+			logEntry.Entry = fmt.Sprintf("Forwarding pod %s to local port %d again", pe.PodName, pe.LocalPort)
+		default:
+			logEntry.Entry = fmt.Sprintf("Forwarding container %s to local port %d", pe.ContainerName, pe.LocalPort)
+		}
+	case *proto.Event_FileChangeEvent:
+		fe := e.FileChangeEvent
+		changes := len(fe.Added) + len(fe.Modified) + len(fe.Deleted)
+		if fe.Artifact != "" {
+			logEntry.Entry = fmt.Sprintf("%d files changed for artifact %s", changes, fe.Artifact)
+		} else {
+			logEntry.Entry = fmt.Sprintf("%d files changed for %s", changes, fe.Component)
+		}
+	case *proto.Event_SyncEvent:
+		se := e.SyncEvent
+		ev.stateLock.Lock()
+		ev.state.SyncState.Artifacts[se.Artifact] = se
+		ev.stateLock.Unlock()
+		switch se.Status {
+		case proto.PhaseStatus_IN_PROGRESS:
+			logEntry.Entry = fmt.Sprintf("File sync started for artifact %s", se.Artifact)
+		case proto.PhaseStatus_SUCCEEDED:
+			logEntry.Entry = fmt.Sprintf("File sync completed for artifact %s", se.Artifact)
+		case proto.PhaseStatus_FAILED:
+			logEntry.Entry = fmt.Sprintf("File sync failed for artifact %s", se.Artifact)
+		}
+	case *proto.Event_TestEvent:
+		te := e.TestEvent
+		ev.stateLock.Lock()
+		ev.state.TestState.Status = te.Status
+		ev.stateLock.Unlock()
+		switch te.Status {
+		case proto.PhaseStatus_IN_PROGRESS:
+			logEntry.Entry = "Tests started"
+		case proto.PhaseStatus_SUCCEEDED:
+			logEntry.Entry = "Tests passed"
+		case proto.PhaseStatus_FAILED:
+			logEntry.Entry = "Tests failed"
+		}
+	case *proto.Event_StatusCheckEvent:
+		se := e.StatusCheckEvent
+		ev.stateLock.Lock()
+		ev.state.StatusCheckState.Status = se.Status
+		ev.stateLock.Unlock()
+		switch se.Status {
+		case proto.PhaseStatus_IN_PROGRESS:
+			logEntry.Entry = "Status check started"
+		case proto.PhaseStatus_SUCCEEDED:
+			logEntry.Entry = "Status check succeeded"
+		case proto.PhaseStatus_FAILED:
+			logEntry.Entry = "Status check failed"
+		}
+	case *proto.Event_DevLoopEvent:
+		de := e.DevLoopEvent
+		ev.stateLock.Lock()
+		ev.state.DevLoopState.Iteration = de.Iteration
+		ev.state.DevLoopState.Status = de.Status
+		ev.stateLock.Unlock()
+		switch de.Status {
+		case proto.PhaseStatus_IN_PROGRESS:
+			logEntry.Entry = fmt.Sprintf("Dev loop iteration %d started", de.Iteration)
+		case proto.PhaseStatus_SUCCEEDED:
+			logEntry.Entry = fmt.Sprintf("Dev loop iteration %d completed", de.Iteration)
+		case proto.PhaseStatus_FAILED:
+			logEntry.Entry = fmt.Sprintf("Dev loop iteration %d failed", de.Iteration)
+		}
+	case *proto.Event_ResourceStatusEvent:
+		re := e.ResourceStatusEvent
+		ev.stateLock.Lock()
+		ev.state.Resources[re.Namespace+"/"+re.Resource] = re
+		ev.stateLock.Unlock()
+		logEntry.Entry = fmt.Sprintf("Resource %s is %s", re.Resource, re.Status)
+	case *proto.Event_PodDiagnosticEvent:
+		de := e.PodDiagnosticEvent
+		if de.ContainerName == "" {
+			logEntry.Entry = fmt.Sprintf("Pod %s: %s", de.PodName, de.Reason)
+		} else {
+			logEntry.Entry = fmt.Sprintf("Pod %s, container %s: %s", de.PodName, de.ContainerName, de.Reason)
+		}
+	case *proto.Event_MetaEvent:
 	default:
 		return
 	}
@@ -18,12 +18,14 @@ package event
 
 import (
 	"errors"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -75,8 +77,9 @@ func TestDeployInProgress(t *testing.T) {
 	}
 
 	wait(t, func() bool { return handler.getState().DeployState.Status == NotStarted })
-	DeployInProgress()
+	DeployInProgress("kube-context")
 	wait(t, func() bool { return handler.getState().DeployState.Status == InProgress })
+	testutil.CheckDeepEqual(t, InProgress, handler.getState().DeployState.KubeContexts["kube-context"])
 }
 
 func TestDeployFailed(t *testing.T) {
@@ -87,7 +90,7 @@ func TestDeployFailed(t *testing.T) {
 	}
 
 	wait(t, func() bool { return handler.getState().DeployState.Status == NotStarted })
-	DeployFailed(errors.New("BUG"))
+	DeployFailed("kube-context", errors.New("BUG"))
 	wait(t, func() bool { return handler.getState().DeployState.Status == Failed })
 }
 
@@ -99,10 +102,44 @@ func TestDeployComplete(t *testing.T) {
 	}
 
 	wait(t, func() bool { return handler.getState().DeployState.Status == NotStarted })
-	DeployComplete()
+	DeployComplete("kube-context")
 	wait(t, func() bool { return handler.getState().DeployState.Status == Complete })
 }
 
+func TestDeployMultipleKubeContexts(t *testing.T) {
+	defer func() { handler = nil }()
+
+	state := emptyState(nil)
+	state.DeployState.KubeContexts["cluster1"] = NotStarted
+	state.DeployState.KubeContexts["cluster2"] = NotStarted
+	handler = &eventHandler{
+		state: state,
+	}
+
+	DeployInProgress("cluster1")
+	wait(t, func() bool { return handler.getState().DeployState.KubeContexts["cluster1"] == InProgress })
+	testutil.CheckDeepEqual(t, InProgress, handler.getState().DeployState.Status)
+
+	DeployComplete("cluster1")
+	wait(t, func() bool { return handler.getState().DeployState.KubeContexts["cluster1"] == Complete })
+	testutil.CheckDeepEqual(t, InProgress, handler.getState().DeployState.Status)
+
+	DeployInProgress("cluster2")
+	DeployComplete("cluster2")
+	wait(t, func() bool { return handler.getState().DeployState.KubeContexts["cluster2"] == Complete })
+	testutil.CheckDeepEqual(t, Complete, handler.getState().DeployState.Status)
+
+	// Redeploy
+	DeployInProgress("cluster1")
+	wait(t, func() bool { return handler.getState().DeployState.KubeContexts["cluster1"] == InProgress })
+	testutil.CheckDeepEqual(t, InProgress, handler.getState().DeployState.Status)
+	testutil.CheckDeepEqual(t, NotStarted, handler.getState().DeployState.KubeContexts["cluster2"])
+
+	DeployFailed("cluster1", errors.New("BUG"))
+	wait(t, func() bool { return handler.getState().DeployState.KubeContexts["cluster1"] == Failed })
+	testutil.CheckDeepEqual(t, Failed, handler.getState().DeployState.Status)
+}
+
 func TestBuildInProgress(t *testing.T) {
 	defer func() { handler = nil }()
 
@@ -159,10 +196,239 @@ func TestPortForwarded(t *testing.T) {
 	}
 
 	wait(t, func() bool { return handler.getState().ForwardedPorts["container"] == nil })
-	PortForwarded(8080, 8888, "pod", "container", "ns", "portname", "resourceType", "resourceName")
+	PortForwarded(8080, 8888, "pod", "container", "ns", "portname", "resourceType", "resourceName", "kube-context")
 	wait(t, func() bool { return handler.getState().ForwardedPorts["container"] != nil })
 }
 
+func TestPortForwardLost(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	PortForwarded(8080, 8888, "pod", "container", "ns", "portname", "resourceType", "resourceName", "kube-context")
+	wait(t, func() bool { return handler.getState().ForwardedPorts["container"].GetStatus() == Forwarded })
+	PortForwardLost(8080, 8888, "pod", "container", "ns", "portname", "resourceType", "resourceName", "kube-context")
+	wait(t, func() bool { return handler.getState().ForwardedPorts["container"].GetStatus() == Lost })
+}
+
+func TestPortForwardReestablished(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	PortForwardLost(8080, 8888, "pod", "container", "ns", "portname", "resourceType", "resourceName", "kube-context")
+	wait(t, func() bool { return handler.getState().ForwardedPorts["container"].GetStatus() == Lost })
+	PortForwardReestablished(8080, 8888, "pod2", "container", "ns", "portname", "resourceType", "resourceName", "kube-context")
+	wait(t, func() bool {
+		port := handler.getState().ForwardedPorts["container"]
+		return port.GetStatus() == Reestablished && port.GetPodName() == "pod2"
+	})
+}
+
+func TestPodDiagnosed(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	PodDiagnosed("pod", "container", "ns", "kube-context", "OOMKilled", "exit code 137", []string{"allocating"}, []string{"Warning BackOff"})
+	wait(t, func() bool {
+		handler.logLock.Lock()
+		defer handler.logLock.Unlock()
+		return len(handler.eventLog) == 1 && handler.eventLog[0].Entry == "Pod pod, container container: OOMKilled"
+	})
+}
+
+func TestBuildTiming(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+	start := time.Date(2019, 6, 13, 10, 30, 0, 0, time.UTC)
+	current := start
+	reset := testutil.Override(t, &now, func() time.Time { return current })
+	defer reset()
+
+	BuildInProgress("img")
+	current = start.Add(3 * time.Second)
+	BuildFailed("img", errors.New("BUG"))
+
+	wait(t, func() bool {
+		handler.logLock.Lock()
+		defer handler.logLock.Unlock()
+		return len(handler.eventLog) == 2
+	})
+
+	var failed *proto.BuildEvent
+	handler.logLock.Lock()
+	for _, entry := range handler.eventLog {
+		if be := entry.Event.GetBuildEvent(); be.GetPhaseStatus() == proto.PhaseStatus_FAILED {
+			failed = be
+		}
+	}
+	handler.logLock.Unlock()
+
+	testutil.CheckDeepEqual(t, proto.ErrorCode_BUILD_FAILED, failed.GetErrCode())
+	testutil.CheckDeepEqual(t, "BUG", failed.GetErr())
+	testutil.CheckDeepEqual(t, int64(3), failed.GetTiming().GetDuration().GetSeconds())
+	testutil.CheckDeepEqual(t, start.Unix(), failed.GetTiming().GetStartTime().GetSeconds())
+	testutil.CheckDeepEqual(t, start.Unix()+3, failed.GetTiming().GetEndTime().GetSeconds())
+}
+
+func TestTestEvents(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	testutil.CheckDeepEqual(t, proto.PhaseStatus_NOT_STARTED, handler.getState().TestState.Status)
+	TestInProgress()
+	wait(t, func() bool { return handler.getState().TestState.Status == proto.PhaseStatus_IN_PROGRESS })
+	TestFailed(errors.New("BUG"))
+	wait(t, func() bool { return handler.getState().TestState.Status == proto.PhaseStatus_FAILED })
+}
+
+func TestSyncEvents(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	SyncInProgress("img", []string{"index.html"}, nil)
+	wait(t, func() bool {
+		return handler.getState().SyncState.Artifacts["img"].GetStatus() == proto.PhaseStatus_IN_PROGRESS
+	})
+	SyncComplete("img", []string{"index.html"}, nil)
+	wait(t, func() bool {
+		sync := handler.getState().SyncState.Artifacts["img"]
+		return sync.GetStatus() == proto.PhaseStatus_SUCCEEDED && len(sync.GetCopied()) == 1
+	})
+}
+
+func TestStatusCheckEvents(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	StatusCheckInProgress()
+	wait(t, func() bool { return handler.getState().StatusCheckState.Status == proto.PhaseStatus_IN_PROGRESS })
+	StatusCheckComplete()
+	wait(t, func() bool { return handler.getState().StatusCheckState.Status == proto.PhaseStatus_SUCCEEDED })
+}
+
+func TestDevLoopEvents(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	DevLoopInProgress(2)
+	wait(t, func() bool {
+		state := handler.getState().DevLoopState
+		return state.Iteration == 2 && state.Status == proto.PhaseStatus_IN_PROGRESS
+	})
+	DevLoopFailed(2, errors.New("BUG"))
+	wait(t, func() bool { return handler.getState().DevLoopState.Status == proto.PhaseStatus_FAILED })
+}
+
+func TestResourceStatusChanged(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	ResourceStatusChanged("pod/web", "default", "kube-context", "Running", true, "")
+	wait(t, func() bool {
+		resource := handler.getState().Resources["default/pod/web"]
+		return resource.GetStatus() == "Running" && resource.GetReady()
+	})
+}
+
+func TestFileChanged(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	FileChanged("artifact", "img", []string{"new.go"}, []string{"main.go"}, nil)
+	wait(t, func() bool {
+		handler.logLock.Lock()
+		defer handler.logLock.Unlock()
+		return len(handler.eventLog) == 1 && handler.eventLog[0].Entry == "2 files changed for artifact img"
+	})
+}
+
+func TestEventsInOrder(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	for i := 1; i <= 50; i++ {
+		DevLoopInProgress(i)
+		DevLoopComplete(i)
+	}
+	wait(t, func() bool {
+		handler.logLock.Lock()
+		defer handler.logLock.Unlock()
+		return len(handler.eventLog) == 100
+	})
+
+	for i, entry := range handler.eventLog {
+		testutil.CheckDeepEqual(t, int32(i/2+1), entry.GetEvent().GetDevLoopEvent().GetIteration())
+	}
+	state := handler.getState()
+	testutil.CheckDeepEqual(t, int32(50), state.DevLoopState.Iteration)
+	testutil.CheckDeepEqual(t, proto.PhaseStatus_SUCCEEDED, state.DevLoopState.Status)
+}
+
+func TestSlowListener(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	unblock := make(chan struct{})
+	go ForEachEvent(func(*proto.LogEntry) error {
+		<-unblock
+		return nil
+	})
+	wait(t, func() bool {
+		handler.logLock.Lock()
+		defer handler.logLock.Unlock()
+		return len(handler.listeners) == 1
+	})
+
+	// Emitting events doesn't wait for the listener.
+	LogSkaffoldMetadata(&version.Info{Version: "v1.0.0"})
+	for i := 1; i <= 500; i++ {
+		DevLoopInProgress(i)
+	}
+	close(unblock)
+
+	wait(t, func() bool {
+		handler.logLock.Lock()
+		defer handler.logLock.Unlock()
+		return len(handler.eventLog) == 501
+	})
+	testutil.CheckDeepEqual(t, true, strings.HasPrefix(handler.eventLog[0].GetEvent().GetMetaEvent().GetEntry(), "Starting Skaffold: &{Version:v1.0.0 "))
+}
+
 func wait(t *testing.T, condition func() bool) {
 	ticker := time.NewTicker(10 * time.Millisecond)
 	defer ticker.Stop()
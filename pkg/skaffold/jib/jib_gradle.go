@@ -47,6 +47,11 @@ func getCommandGradle(ctx context.Context, workspace string, a *latest.JibGradle
 	return GradleCommand.CreateCommand(ctx, workspace, args)
 }
 
+func getSyncMapCommandGradle(ctx context.Context, workspace string, a *latest.JibGradleArtifact) exec.Cmd {
+	args := []string{gradleCommand(a, "_jibSkaffoldSyncMap"), "-q"}
+	return GradleCommand.CreateCommand(ctx, workspace, args)
+}
+
 // GenerateGradleArgs generates the arguments to Gradle for building the project as an image.
 func GenerateGradleArgs(task string, imageName string, a *latest.JibGradleArtifact, skipTests bool) []string {
 	// disable jib's rich progress footer; we could use `--console=plain`
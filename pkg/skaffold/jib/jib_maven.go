@@ -47,6 +47,13 @@ func getCommandMaven(ctx context.Context, workspace string, a *latest.JibMavenAr
 	return MavenCommand.CreateCommand(ctx, workspace, args)
 }
 
+func getSyncMapCommandMaven(ctx context.Context, workspace string, a *latest.JibMavenArtifact) exec.Cmd {
+	args := mavenArgs(a)
+	args = append(args, "-DskipTests=true", "prepare-package", "jib:_skaffold-sync-map", "--quiet")
+
+	return MavenCommand.CreateCommand(ctx, workspace, args)
+}
+
 // GenerateMavenArgs generates the arguments to Maven for building the project as an image.
 func GenerateMavenArgs(goal string, imageName string, a *latest.JibMavenArtifact, skipTests bool) []string {
 	// disable jib's rich progress footer on builds; we could use --batch-mode
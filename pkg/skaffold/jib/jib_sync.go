@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// syncLists is the mapping of local files to container paths output by Jib.
+type syncLists struct {
+	// Direct lists the files copied as is, like the extra directories.
+	Direct []syncEntry `json:"direct"`
+
+	// Generated lists the files produced by the build, like compiled classes and processed resources.
+	Generated []syncEntry `json:"generated"`
+}
+
+type syncEntry struct {
+	Src  string `json:"src"`
+	Dest string `json:"dest"`
+}
+
+// syncMapEntry gives the container paths of a local file.
+type syncMapEntry struct {
+	Dest     []string
+	FileTime time.Time
+	IsDirect bool
+}
+
+// syncMap maps absolute local paths to their container paths.
+type syncMap map[string]syncMapEntry
+
+// syncMaps caches the last sync map of each project, to find which generated files changed.
+var syncMaps = map[string]syncMap{}
+
+// GetSyncMap returns the container paths of the files of a jib artifact,
+// keyed by their paths relative to the workspace.
+func GetSyncMap(ctx context.Context, workspace string, a *latest.Artifact) (map[string][]string, error) {
+	cmd, _, err := syncMapCommand(ctx, workspace, a)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := getSyncMap(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceRoots, err := calculateRoots(workspace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to resolve workspace %s", workspace)
+	}
+
+	destinations := map[string][]string{}
+	for src, entry := range files {
+		if relative, err := relativize(src, workspaceRoots...); err == nil {
+			src = relative
+		}
+		destinations[src] = entry.Dest
+	}
+	return destinations, nil
+}
+
+// GetSyncDiff returns the files to copy to and delete from the containers after files
+// of a jib artifact changed. Changed sources are compiled by Jib first.
+// A nil result means that the artifact needs to be rebuilt.
+func GetSyncDiff(ctx context.Context, workspace string, a *latest.Artifact, e watch.Events) (map[string][]string, map[string][]string, error) {
+	cmd, projectName, err := syncMapCommand(ctx, workspace, a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Changes to the build definitions can change the dependencies.
+	buildDefinitions := watchedFiles[projectName].BuildDefinitions
+	for _, f := range append(append(e.Added, e.Modified...), e.Deleted...) {
+		if contains(buildDefinitions, absPath(f)) {
+			logrus.Infof("Build definition %s changed. Skipping sync", f)
+			return nil, nil, nil
+		}
+	}
+
+	key := workspace + ":" + projectName
+	previous, cached := syncMaps[key]
+
+	toCopy := map[string][]string{}
+	toDelete := map[string][]string{}
+	needsCompile := !cached
+	for _, f := range append(e.Added, e.Modified...) {
+		if entry, found := previous[absPath(f)]; found && entry.IsDirect {
+			toCopy[f] = entry.Dest
+		} else {
+			needsCompile = true
+		}
+	}
+	for _, f := range e.Deleted {
+		if entry, found := previous[absPath(f)]; found && entry.IsDirect {
+			toDelete[f] = entry.Dest
+		} else {
+			needsCompile = true
+		}
+	}
+
+	if !needsCompile {
+		return toCopy, toDelete, nil
+	}
+
+	current, err := getSyncMap(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	syncMaps[key] = current
+
+	for _, f := range append(e.Added, e.Modified...) {
+		if entry, found := current[absPath(f)]; found && entry.IsDirect {
+			toCopy[f] = entry.Dest
+		}
+	}
+	for src, entry := range current {
+		if entry.IsDirect {
+			continue
+		}
+		if old, found := previous[src]; !found || !old.FileTime.Equal(entry.FileTime) {
+			toCopy[src] = entry.Dest
+		}
+	}
+	for src, entry := range previous {
+		if _, found := current[src]; !found && !entry.IsDirect {
+			toDelete[src] = entry.Dest
+		}
+	}
+
+	return toCopy, toDelete, nil
+}
+
+// syncMapCommand returns the command that compiles a jib artifact and outputs its sync map.
+func syncMapCommand(ctx context.Context, workspace string, a *latest.Artifact) (exec.Cmd, string, error) {
+	switch {
+	case a.JibMavenArtifact != nil:
+		return getSyncMapCommandMaven(ctx, workspace, a.JibMavenArtifact), a.JibMavenArtifact.Module, nil
+
+	case a.JibGradleArtifact != nil:
+		return getSyncMapCommandGradle(ctx, workspace, a.JibGradleArtifact), a.JibGradleArtifact.Project, nil
+
+	default:
+		return exec.Cmd{}, "", fmt.Errorf("%s is not a jib artifact", a.ImageName)
+	}
+}
+
+// getSyncMap calls out to Jib to get the files it puts in the container.
+func getSyncMap(cmd exec.Cmd) (syncMap, error) {
+	stdout, err := util.RunCmdOut(&cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Jib sync map; it's possible you are using an old version of Jib (Skaffold requires Jib v2.0.0+ to sync files)")
+	}
+
+	// Jib's Maven/Gradle output takes the following form:
+	// ...
+	// BEGIN JIB JSON: SYNCMAP/1
+	// {"direct":[{"src":"/path/to/file","dest":"/in/container"}],"generated":[{"src":"/path/to/class","dest":"/in/container"}]}
+	// ...
+	matches := regexp.MustCompile(`BEGIN JIB JSON: SYNCMAP/1\r?\n({.*})`).FindSubmatch(stdout)
+	if len(matches) == 0 {
+		return nil, errors.New("failed to get Jib sync map")
+	}
+
+	var lists syncLists
+	line := bytes.Replace(matches[1], []byte(`\`), []byte(`\\`), -1)
+	if err := json.Unmarshal(line, &lists); err != nil {
+		return nil, errors.Wrap(err, "failed to parse Jib sync map")
+	}
+
+	files := syncMap{}
+	if err := addSyncEntries(files, lists.Direct, true); err != nil {
+		return nil, err
+	}
+	if err := addSyncEntries(files, lists.Generated, false); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func addSyncEntries(files syncMap, entries []syncEntry, isDirect bool) error {
+	for _, entry := range entries {
+		info, err := os.Stat(entry.Src)
+		if err != nil {
+			return errors.Wrapf(err, "unable to stat file %s", entry.Src)
+		}
+
+		files[entry.Src] = syncMapEntry{
+			Dest:     append(files[entry.Src].Dest, entry.Dest),
+			FileTime: info.ModTime(),
+			IsDirect: isDirect,
+		}
+	}
+	return nil
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func contains(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func syncMapOutput(direct, generated string) string {
+	return fmt.Sprintf("BEGIN JIB JSON: SYNCMAP/1\n{\"direct\":[{\"src\":\"%s\",\"dest\":\"/app/static/index.html\"}],\"generated\":[{\"src\":\"%s\",\"dest\":\"/app/classes/App.class\"}]}", direct, generated)
+}
+
+func TestGetSyncMap(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Touch("src/main/jib/static/index.html", "target/classes/App.class")
+		artifact := &latest.Artifact{
+			ArtifactType: latest.ArtifactType{
+				JibMavenArtifact: &latest.JibMavenArtifact{},
+			},
+		}
+		ctx := context.Background()
+
+		t.Override(&util.DefaultExecCommand, t.FakeRunOut(
+			strings.Join(getSyncMapCommandMaven(ctx, tmpDir.Root(), artifact.JibMavenArtifact).Args, " "),
+			syncMapOutput(tmpDir.Path("src/main/jib/static/index.html"), tmpDir.Path("target/classes/App.class")),
+		))
+
+		syncMap, err := GetSyncMap(ctx, tmpDir.Root(), artifact)
+
+		t.CheckErrorAndDeepEqual(false, err, map[string][]string{
+			"src/main/jib/static/index.html": {"/app/static/index.html"},
+			"target/classes/App.class":       {"/app/classes/App.class"},
+		}, syncMap)
+	})
+}
+
+func TestGetSyncDiff(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Touch("build.gradle", "src/main/jib/static/index.html", "src/main/java/App.java", "build/classes/App.class")
+		index := tmpDir.Path("src/main/jib/static/index.html")
+		source := tmpDir.Path("src/main/java/App.java")
+		class := tmpDir.Path("build/classes/App.class")
+		artifact := &latest.Artifact{
+			ArtifactType: latest.ArtifactType{
+				JibGradleArtifact: &latest.JibGradleArtifact{Project: "sync-test"},
+			},
+		}
+		ctx := context.Background()
+		command := strings.Join(getSyncMapCommandGradle(ctx, tmpDir.Root(), artifact.JibGradleArtifact).Args, " ")
+
+		t.Override(&watchedFiles, map[string]filesLists{
+			"sync-test": {BuildDefinitions: []string{tmpDir.Path("build.gradle")}},
+		})
+		t.Override(&syncMaps, map[string]syncMap{})
+		t.Override(&util.DefaultExecCommand, t.FakeRunOut(command, syncMapOutput(index, class)))
+
+		// Without a previous sync map, every generated file is synced.
+		toCopy, toDelete, err := GetSyncDiff(ctx, tmpDir.Root(), artifact, watch.Events{Modified: []string{index}})
+		t.CheckNoError(err)
+		t.CheckDeepEqual(map[string][]string{
+			index: {"/app/static/index.html"},
+			class: {"/app/classes/App.class"},
+		}, toCopy)
+		t.CheckDeepEqual(map[string][]string{}, toDelete)
+
+		// Direct files are synced without calling Jib.
+		t.Override(&util.DefaultExecCommand, t.FakeRunOutErr(command, "", fmt.Errorf("unexpected call")))
+		toCopy, _, err = GetSyncDiff(ctx, tmpDir.Root(), artifact, watch.Events{Modified: []string{index}})
+		t.CheckNoError(err)
+		t.CheckDeepEqual(map[string][]string{index: {"/app/static/index.html"}}, toCopy)
+
+		// Sources are compiled and only the classes that changed are synced.
+		later := time.Now().Add(time.Hour)
+		t.CheckNoError(os.Chtimes(class, later, later))
+		t.Override(&util.DefaultExecCommand, t.FakeRunOut(command, syncMapOutput(index, class)))
+		toCopy, _, err = GetSyncDiff(ctx, tmpDir.Root(), artifact, watch.Events{Modified: []string{source}})
+		t.CheckNoError(err)
+		t.CheckDeepEqual(map[string][]string{class: {"/app/classes/App.class"}}, toCopy)
+
+		// Changes to the build definitions need a rebuild.
+		toCopy, toDelete, err = GetSyncDiff(ctx, tmpDir.Root(), artifact, watch.Events{Modified: []string{tmpDir.Path("build.gradle")}})
+		t.CheckNoError(err)
+		t.CheckDeepEqual(map[string][]string(nil), toCopy)
+		t.CheckDeepEqual(map[string][]string(nil), toDelete)
+	})
+}
+
+func TestGetSyncMapError(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		artifact := &latest.Artifact{
+			ArtifactType: latest.ArtifactType{
+				JibMavenArtifact: &latest.JibMavenArtifact{},
+			},
+		}
+		ctx := context.Background()
+		t.Override(&util.DefaultExecCommand, t.FakeRunOut(
+			strings.Join(getSyncMapCommandMaven(ctx, ".", artifact.JibMavenArtifact).Args, " "),
+			"no sync map",
+		))
+
+		_, err := GetSyncMap(ctx, ".", artifact)
+
+		t.CheckErrorContains("failed to get Jib sync map", err)
+	})
+}
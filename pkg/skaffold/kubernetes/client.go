@@ -29,17 +29,26 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+// GetClientset returns a client for the current kubectl context.
 func GetClientset() (kubernetes.Interface, error) {
-	config, err := getClientConfig()
+	return GetClientsetForContext("")
+}
+
+// GetClientsetForContext returns a client for the given kubectl context.
+// An empty context name selects the current context.
+func GetClientsetForContext(kubeContext string) (kubernetes.Interface, error) {
+	config, err := getClientConfig(kubeContext)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting client config for kubernetes client")
 	}
 	return kubernetes.NewForConfig(config)
 }
 
-func getClientConfig() (*restclient.Config, error) {
+func getClientConfig(kubeContext string) (*restclient.Config, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: kubeContext,
+	})
 	clientConfig, err := kubeConfig.ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("error creating kubeConfig: %s", err)
@@ -47,8 +56,15 @@ func getClientConfig() (*restclient.Config, error) {
 	return clientConfig, nil
 }
 
+// GetDynamicClient returns a dynamic client for the current kubectl context.
 func GetDynamicClient() (dynamic.Interface, error) {
-	config, err := getClientConfig()
+	return GetDynamicClientForContext("")
+}
+
+// GetDynamicClientForContext returns a dynamic client for the given kubectl context.
+// An empty context name selects the current context.
+func GetDynamicClientForContext(kubeContext string) (dynamic.Interface, error) {
+	config, err := getClientConfig(kubeContext)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting client config for dynamic client")
 	}
@@ -42,6 +42,7 @@ var colorCodes = []color.Color{
 // from each pod.
 type ColorPicker interface {
 	Pick(pod *v1.Pod) color.Color
+	PickImage(image string) color.Color
 }
 
 type colorPicker struct {
@@ -78,6 +79,15 @@ func (p *colorPicker) Pick(pod *v1.Pod) color.Color {
 	return color.None
 }
 
+// PickImage returns the color associated with an image, or the none color.
+func (p *colorPicker) PickImage(image string) color.Color {
+	if c, present := p.imageColors[stripTag(image)]; present {
+		return c
+	}
+
+	return color.None
+}
+
 func stripTag(image string) string {
 	if !strings.Contains(image, ":") {
 		return image
@@ -0,0 +1,443 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// Reasons why a container crashes or doesn't start.
+const (
+	CrashLoopBackOff = "CrashLoopBackOff"
+	OOMKilled        = "OOMKilled"
+	ImagePullBackOff = "ImagePullBackOff"
+	FailedScheduling = "FailedScheduling"
+	Unready          = "Unready"
+)
+
+const (
+	diagnosticLogLines = 10
+	diagnosticEvents   = 5
+)
+
+// readinessGracePeriod is how long a running container can stay unready before it is diagnosed.
+var readinessGracePeriod = 30 * time.Second
+
+// Diagnostic explains why a container crashed or doesn't start.
+// Container is empty for the problems of the whole pod, like failed scheduling.
+type Diagnostic struct {
+	KubeContext string
+	Namespace   string
+	Pod         string
+	Container   string
+	Reason      string
+	Message     string
+	Logs        []string
+	Events      []string
+}
+
+// problem is a Diagnostic found in a pod status, before the logs and events are gathered.
+type problem struct {
+	Diagnostic
+
+	// key identifies the problem so that it's reported only once.
+	key string
+	// logs tells if the logs of the container should be gathered, previous if
+	// these are the logs of the previous instance of the container.
+	logs     bool
+	previous bool
+	// readiness tells if the problem is only confirmed after readinessGracePeriod.
+	readiness bool
+}
+
+// PodDiagnoser looks for the pods of a run that crash or don't start.
+// For each problem, it prints a diagnostic with the last logs of the container and
+// the warning events of the pod, and emits a pod diagnostic event.
+// A nil PodDiagnoser diagnoses nothing.
+type PodDiagnoser struct {
+	runSelectors []labels.Selector
+
+	outputLock sync.Mutex
+	lock       sync.Mutex
+	reported   map[string]bool
+	statuses   map[string]string
+}
+
+// NewPodDiagnoser creates a PodDiagnoser for the pods matching any of the given selectors.
+func NewPodDiagnoser(runSelectors []labels.Selector) *PodDiagnoser {
+	return &PodDiagnoser{
+		runSelectors: runSelectors,
+		reported:     map[string]bool{},
+		statuses:     map[string]string{},
+	}
+}
+
+// Check reports the status of a pod when it changes, looks for new problems
+// with the pod and diagnoses them in the background.
+func (d *PodDiagnoser) Check(ctx context.Context, out io.Writer, kubeContext string, pod *v1.Pod) {
+	if d == nil || !d.selects(pod) {
+		return
+	}
+
+	status, ready, message := podStatus(pod)
+	if d.statusChanged(kubeContext, pod, fmt.Sprintf("%s/%t/%s", status, ready, message)) {
+		event.ResourceStatusChanged("pod/"+pod.Name, pod.Namespace, kubeContext, status, ready, message)
+	}
+
+	for _, p := range podProblems(pod) {
+		if !d.markReported(p.key) {
+			continue
+		}
+
+		p.KubeContext = kubeContext
+		go d.diagnose(ctx, out, pod, p)
+	}
+}
+
+// selects returns true if the pod was deployed by the current run.
+func (d *PodDiagnoser) selects(pod *v1.Pod) bool {
+	for _, selector := range d.runSelectors {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// explains returns true if the diagnoser reports the problem of a container,
+// so that its state doesn't need to be printed.
+func (d *PodDiagnoser) explains(pod *v1.Pod, container string) bool {
+	if d == nil || !d.selects(pod) {
+		return false
+	}
+
+	for _, p := range podProblems(pod) {
+		if p.Container == container && !p.readiness {
+			return true
+		}
+	}
+	return false
+}
+
+// statusChanged records the status of a pod and returns true if it changed.
+func (d *PodDiagnoser) statusChanged(kubeContext string, pod *v1.Pod, status string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s", kubeContext, pod.Namespace, pod.Name)
+	if d.statuses[key] == status {
+		return false
+	}
+	d.statuses[key] = status
+	return true
+}
+
+// podStatus summarizes the status of a pod like `kubectl get pods` does: the reason
+// why a container is waiting or terminated if any, the phase of the pod otherwise.
+func podStatus(pod *v1.Pod) (string, bool, string) {
+	ready := false
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			ready = condition.Status == v1.ConditionTrue
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return "Terminating", false, ""
+	}
+
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "ContainerCreating" && cs.State.Waiting.Reason != "PodInitializing":
+			return cs.State.Waiting.Reason, ready, cs.State.Waiting.Message
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && cs.State.Terminated.Reason != "Completed":
+			return cs.State.Terminated.Reason, ready, cs.State.Terminated.Message
+		}
+	}
+
+	return string(pod.Status.Phase), ready, pod.Status.Message
+}
+
+// markReported returns true if the problem wasn't reported yet.
+func (d *PodDiagnoser) markReported(key string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.reported[key] {
+		return false
+	}
+	d.reported[key] = true
+	return true
+}
+
+func (d *PodDiagnoser) forget(key string) {
+	d.lock.Lock()
+	delete(d.reported, key)
+	d.lock.Unlock()
+}
+
+func (d *PodDiagnoser) diagnose(ctx context.Context, out io.Writer, pod *v1.Pod, p problem) {
+	if p.readiness {
+		select {
+		case <-ctx.Done():
+			d.forget(p.key)
+			return
+		case <-time.After(readinessGracePeriod):
+		}
+
+		unready, err := stillUnready(p.KubeContext, pod, p.Container)
+		if err != nil || !unready {
+			d.forget(p.key)
+			return
+		}
+	}
+
+	events, err := warningEvents(p.KubeContext, pod)
+	if err != nil {
+		logrus.Debugf("listing events of pod %s: %s", pod.Name, err)
+	}
+	p.Events = events
+
+	if p.readiness {
+		for _, e := range events {
+			if strings.HasPrefix(e, "Unhealthy: Readiness probe failed") {
+				p.Message = strings.TrimPrefix(e, "Unhealthy: ")
+			}
+		}
+	}
+
+	if p.logs {
+		logs, err := lastLogs(ctx, p.KubeContext, pod, p.Container, p.previous)
+		if err != nil {
+			logrus.Debugf("reading logs of container %s: %s", p.Container, err)
+		}
+		p.Logs = logs
+	}
+
+	d.print(out, p.Diagnostic)
+	event.PodDiagnosed(p.Pod, p.Container, p.Namespace, p.KubeContext, p.Reason, p.Message, p.Logs, p.Events)
+}
+
+func (d *PodDiagnoser) print(out io.Writer, diag Diagnostic) {
+	d.outputLock.Lock()
+	defer d.outputLock.Unlock()
+
+	subject := fmt.Sprintf("Pod %s", diag.Pod)
+	if diag.Container != "" {
+		subject = fmt.Sprintf("Container %s of pod %s", diag.Container, diag.Pod)
+	}
+	color.Red.Fprintf(out, "%s: %s", subject, diag.Reason)
+	if diag.Message != "" {
+		fmt.Fprintf(out, " - %s", diag.Message)
+	}
+	fmt.Fprintln(out)
+
+	if len(diag.Logs) > 0 {
+		fmt.Fprintln(out, "  Last logs:")
+		for _, line := range diag.Logs {
+			fmt.Fprintln(out, "    "+line)
+		}
+	}
+	if len(diag.Events) > 0 {
+		fmt.Fprintln(out, "  Events:")
+		for _, e := range diag.Events {
+			fmt.Fprintln(out, "    "+e)
+		}
+	}
+}
+
+// podProblems finds the problems visible in the status of a pod.
+func podProblems(pod *v1.Pod) []problem {
+	var problems []problem
+
+	// instance tells apart the successive occurrences of a problem, like the restarts of a container.
+	newProblem := func(container, reason, message string, instance interface{}) problem {
+		return problem{
+			Diagnostic: Diagnostic{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Container: container,
+				Reason:    reason,
+				Message:   message,
+			},
+			key: fmt.Sprintf("%s/%s/%s/%s/%v", pod.Namespace, pod.Name, container, reason, instance),
+		}
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable {
+			problems = append(problems, newProblem("", FailedScheduling, condition.Message, ""))
+		}
+	}
+
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		last := cs.LastTerminationState.Terminated
+
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason == OOMKilled:
+			p := newProblem(cs.Name, OOMKilled, terminationMessage(cs.State.Terminated, cs.RestartCount), cs.RestartCount)
+			p.logs = true
+			problems = append(problems, p)
+
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason == CrashLoopBackOff:
+			reason := CrashLoopBackOff
+			if last != nil && last.Reason == OOMKilled {
+				reason = OOMKilled
+			}
+			message := cs.State.Waiting.Message
+			if last != nil {
+				message = terminationMessage(last, cs.RestartCount)
+			}
+			p := newProblem(cs.Name, reason, message, cs.RestartCount)
+			p.logs = true
+			p.previous = true
+			problems = append(problems, p)
+
+		case cs.State.Waiting != nil && isOneOf(cs.State.Waiting.Reason, []string{"ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull"}):
+			problems = append(problems, newProblem(cs.Name, ImagePullBackOff, cs.State.Waiting.Message, cs.Image))
+
+		case cs.State.Running != nil && !cs.Ready && hasReadinessProbe(pod, cs.Name):
+			p := newProblem(cs.Name, Unready, "the readiness probe keeps failing", cs.RestartCount)
+			p.readiness = true
+			problems = append(problems, p)
+		}
+	}
+
+	return problems
+}
+
+func terminationMessage(terminated *v1.ContainerStateTerminated, restarts int32) string {
+	message := fmt.Sprintf("exit code %d", terminated.ExitCode)
+	if terminated.Reason != "" {
+		message += fmt.Sprintf(" (%s)", terminated.Reason)
+	}
+	switch {
+	case restarts == 1:
+		message += ", restarted once"
+	case restarts > 1:
+		message += fmt.Sprintf(", restarted %d times", restarts)
+	}
+	if terminated.Message != "" {
+		message += ": " + strings.TrimSpace(terminated.Message)
+	}
+	return message
+}
+
+func hasReadinessProbe(pod *v1.Pod, container string) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == container {
+			return c.ReadinessProbe != nil
+		}
+	}
+	return false
+}
+
+// stillUnready checks if a container is still running but unready, without having restarted.
+func stillUnready(kubeContext string, pod *v1.Pod, container string) (bool, error) {
+	client, err := Client(kubeContext)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := client.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, cs := range current.Status.ContainerStatuses {
+		if cs.Name == container {
+			return cs.State.Running != nil && !cs.Ready, nil
+		}
+	}
+	return false, nil
+}
+
+// warningEvents lists the last warning events of a pod, oldest first.
+func warningEvents(kubeContext string, pod *v1.Pod) ([]string, error) {
+	client, err := Client(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.CoreV1().Events(pod.Namespace).List(metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": pod.Name,
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []v1.Event
+	for _, e := range list.Items {
+		if e.Type == v1.EventTypeWarning && e.InvolvedObject.Name == pod.Name {
+			warnings = append(warnings, e)
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].LastTimestamp.Before(&warnings[j].LastTimestamp)
+	})
+	if len(warnings) > diagnosticEvents {
+		warnings = warnings[len(warnings)-diagnosticEvents:]
+	}
+
+	var events []string
+	for _, e := range warnings {
+		events = append(events, fmt.Sprintf("%s: %s", e.Reason, strings.TrimSpace(e.Message)))
+	}
+	return events, nil
+}
+
+// lastLogs reads the last lines of logs of a container, or of its previous instance.
+func lastLogs(ctx context.Context, kubeContext string, pod *v1.Pod, container string, previous bool) ([]string, error) {
+	args := []string{"logs", fmt.Sprintf("--tail=%d", diagnosticLogLines), pod.Name, "-c", container, "--namespace", pod.Namespace}
+	if previous {
+		args = append(args, "--previous")
+	}
+	if kubeContext != "" {
+		args = append([]string{"--context", kubeContext}, args...)
+	}
+
+	out, err := util.RunCmdOut(exec.CommandContext(ctx, "kubectl", args...))
+	if err != nil {
+		return nil, err
+	}
+
+	logs := strings.TrimRight(string(out), "\n")
+	if logs == "" {
+		return nil, nil
+	}
+	return strings.Split(logs, "\n"), nil
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodProblems(t *testing.T) {
+	tests := []struct {
+		description string
+		status      v1.PodStatus
+		probe       bool
+		expected    []Diagnostic
+	}{
+		{
+			description: "running",
+			status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			description: "crash loop",
+			status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:                 "web",
+					RestartCount:         3,
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "Back-off 40s restarting failed container"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+				}},
+			},
+			expected: []Diagnostic{{Pod: "web-1", Namespace: "default", Container: "web", Reason: CrashLoopBackOff, Message: "exit code 1 (Error), restarted 3 times"}},
+		},
+		{
+			description: "crash loop after running out of memory",
+			status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:                 "web",
+					RestartCount:         1,
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+				}},
+			},
+			expected: []Diagnostic{{Pod: "web-1", Namespace: "default", Container: "web", Reason: OOMKilled, Message: "exit code 137 (OOMKilled), restarted once"}},
+		},
+		{
+			description: "out of memory",
+			status: v1.PodStatus{
+				InitContainerStatuses: []v1.ContainerStatus{{
+					Name:  "init",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", Message: "killed\n"}},
+				}},
+			},
+			expected: []Diagnostic{{Pod: "web-1", Namespace: "default", Container: "init", Reason: OOMKilled, Message: "exit code 137 (OOMKilled): killed"}},
+		},
+		{
+			description: "image pull",
+			status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:  "web",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ErrImagePull", Message: "manifest unknown"}},
+				}},
+			},
+			expected: []Diagnostic{{Pod: "web-1", Namespace: "default", Container: "web", Reason: ImagePullBackOff, Message: "manifest unknown"}},
+		},
+		{
+			description: "failed scheduling",
+			status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient cpu."}},
+			},
+			expected: []Diagnostic{{Pod: "web-1", Namespace: "default", Reason: FailedScheduling, Message: "0/3 nodes are available: 3 Insufficient cpu."}},
+		},
+		{
+			description: "unready",
+			probe:       true,
+			status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}},
+			},
+			expected: []Diagnostic{{Pod: "web-1", Namespace: "default", Container: "web", Reason: Unready, Message: "the readiness probe keeps failing"}},
+		},
+		{
+			description: "unready without readiness probe",
+			status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web"}}},
+				Status:     test.status,
+			}
+			if test.probe {
+				pod.Spec.Containers[0].ReadinessProbe = &v1.Probe{}
+			}
+
+			var diagnostics []Diagnostic
+			for _, p := range podProblems(pod) {
+				diagnostics = append(diagnostics, p.Diagnostic)
+			}
+
+			t.CheckDeepEqual(test.expected, diagnostics)
+		})
+	}
+}
+
+func TestPodDiagnoserCheck(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		event.InitializeState(&runcontext.RunContext{Cfg: &latest.Pipeline{Build: latest.BuildConfig{}}})
+		t.Override(&Client, func(string) (kubernetes.Interface, error) {
+			return fake.NewSimpleClientset(
+				&v1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "backoff", Namespace: "default"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+					Type:           v1.EventTypeWarning,
+					Reason:         "BackOff",
+					Message:        "Back-off restarting failed container",
+					LastTimestamp:  metav1.NewTime(time.Date(2019, 6, 13, 10, 30, 0, 0, time.UTC)),
+				},
+				&v1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "pulled", Namespace: "default"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+					Type:           v1.EventTypeNormal,
+					Reason:         "Pulled",
+					Message:        "Container image already present on machine",
+				},
+			), nil
+		})
+		t.Override(&util.DefaultExecCommand, t.FakeRunOut("kubectl --context kube-context logs --tail=10 web-1 -c web --namespace default --previous", "starting\npanic: no database\n"))
+
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"skaffold.dev/run-id": "1234"}},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:                 "web",
+					RestartCount:         2,
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"}},
+				}},
+			},
+		}
+		diagnoser := NewPodDiagnoser(runSelectors())
+		problems := podProblems(pod)
+		t.CheckDeepEqual(1, len(problems))
+		t.CheckDeepEqual(true, diagnoser.markReported(problems[0].key))
+		t.CheckDeepEqual(false, diagnoser.markReported(problems[0].key))
+		t.CheckDeepEqual(true, diagnoser.explains(pod, "web"))
+
+		var out bytes.Buffer
+		problems[0].KubeContext = "kube-context"
+		diagnoser.diagnose(context.Background(), &out, pod, problems[0])
+
+		t.CheckDeepEqual(`Container web of pod web-1: CrashLoopBackOff - exit code 2 (Error), restarted 2 times
+  Last logs:
+    starting
+    panic: no database
+  Events:
+    BackOff: Back-off restarting failed container
+`, out.String())
+	})
+}
+
+func TestPodDiagnoserReadiness(t *testing.T) {
+	tests := []struct {
+		description string
+		ready       bool
+		expected    string
+	}{
+		{
+			description: "still unready",
+			expected: `Container web of pod web-1: Unready - Readiness probe failed: HTTP probe failed with statuscode: 503
+  Events:
+    Unhealthy: Readiness probe failed: HTTP probe failed with statuscode: 503
+`,
+		},
+		{
+			description: "ready in time",
+			ready:       true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			event.InitializeState(&runcontext.RunContext{Cfg: &latest.Pipeline{Build: latest.BuildConfig{}}})
+
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"skaffold.dev/run-id": "1234"}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", ReadinessProbe: &v1.Probe{}}}},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}},
+				},
+			}
+			current := pod.DeepCopy()
+			current.Status.ContainerStatuses[0].Ready = test.ready
+
+			t.Override(&readinessGracePeriod, time.Duration(0))
+			t.Override(&Client, func(string) (kubernetes.Interface, error) {
+				return fake.NewSimpleClientset(current, &v1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "unhealthy", Namespace: "default"},
+					InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+					Type:           v1.EventTypeWarning,
+					Reason:         "Unhealthy",
+					Message:        "Readiness probe failed: HTTP probe failed with statuscode: 503",
+				}), nil
+			})
+
+			diagnoser := NewPodDiagnoser(runSelectors())
+			problems := podProblems(pod)
+			t.CheckDeepEqual(1, len(problems))
+			diagnoser.markReported(problems[0].key)
+
+			var out bytes.Buffer
+			diagnoser.diagnose(context.Background(), &out, pod, problems[0])
+
+			t.CheckDeepEqual(test.expected, out.String())
+			t.CheckDeepEqual(!test.ready, diagnoser.reported[problems[0].key])
+		})
+	}
+}
+
+func TestPodStatus(t *testing.T) {
+	tests := []struct {
+		description     string
+		pod             v1.Pod
+		expectedStatus  string
+		expectedReady   bool
+		expectedMessage string
+	}{
+		{
+			description: "ready",
+			pod: v1.Pod{Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				Conditions:        []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}},
+			}},
+			expectedStatus: "Running",
+			expectedReady:  true,
+		},
+		{
+			description: "creating",
+			pod: v1.Pod{Status: v1.PodStatus{
+				Phase:             v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}}},
+			}},
+			expectedStatus: "Pending",
+		},
+		{
+			description: "crash loop",
+			pod: v1.Pod{Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "Back-off"}}}},
+			}},
+			expectedStatus:  "CrashLoopBackOff",
+			expectedMessage: "Back-off",
+		},
+		{
+			description: "terminating",
+			pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{}},
+				Status:     v1.PodStatus{Phase: v1.PodRunning},
+			},
+			expectedStatus: "Terminating",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			status, ready, message := podStatus(&test.pod)
+
+			t.CheckDeepEqual(test.expectedStatus, status)
+			t.CheckDeepEqual(test.expectedReady, ready)
+			t.CheckDeepEqual(test.expectedMessage, message)
+		})
+	}
+}
+
+func TestPodDiagnoserStatusChanged(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+	diagnoser := NewPodDiagnoser(nil)
+
+	testutil.CheckDeepEqual(t, true, diagnoser.statusChanged("kube-context", pod, "Pending"))
+	testutil.CheckDeepEqual(t, false, diagnoser.statusChanged("kube-context", pod, "Pending"))
+	testutil.CheckDeepEqual(t, true, diagnoser.statusChanged("kube-context", pod, "Running"))
+	testutil.CheckDeepEqual(t, true, diagnoser.statusChanged("other-context", pod, "Running"))
+}
+
+func TestPodDiagnoserOtherRun(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Labels: map[string]string{"skaffold.dev/run-id": "5678"}},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+		},
+	}
+
+	diagnoser := NewPodDiagnoser(runSelectors())
+
+	testutil.CheckDeepEqual(t, false, diagnoser.explains(pod, "web"))
+
+	var nilDiagnoser *PodDiagnoser
+	testutil.CheckDeepEqual(t, false, nilDiagnoser.explains(pod, "web"))
+	nilDiagnoser.Check(context.Background(), &bytes.Buffer{}, "", pod)
+}
+
+func TestPodDiagnoserReleasedPod(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-1", Labels: map[string]string{"release": "db"}},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "db", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+		},
+	}
+
+	diagnoser := NewPodDiagnoser(runSelectors())
+
+	testutil.CheckDeepEqual(t, true, diagnoser.explains(pod, "db"))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// Exec runs a command in a container through the Kubernetes API, without the kubectl binary.
+// It's here for testing.
+var Exec = execInContainer
+
+// ExecOptions describes a command to run in a container.
+type ExecOptions struct {
+	KubeContext string
+	Namespace   string
+	Pod         string
+	Container   string
+	Command     []string
+	Stdin       io.Reader
+	Stdout      io.Writer
+	Stderr      io.Writer
+}
+
+func execInContainer(ctx context.Context, opts ExecOptions) error {
+	config, err := getClientConfig(opts.KubeContext)
+	if err != nil {
+		return errors.Wrap(err, "getting client config")
+	}
+
+	return streamExec(ctx, config, opts)
+}
+
+// streamExec runs a command with the SPDY executor of client-go. Only the non nil
+// streams are attached. Stdin is closed on the remote side once it's fully read.
+// Cancelling the context closes the connection, which interrupts the stream.
+func streamExec(ctx context.Context, config *restclient.Config, opts ExecOptions) error {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	req := client.CoreV1().RESTClient().Post().
+		Namespace(opts.Namespace).
+		Resource("pods").
+		Name(opts.Pod).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil,
+		}, scheme.ParameterCodec)
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return errors.Wrap(err, "creating round tripper")
+	}
+
+	conns := &closingUpgrader{Upgrader: upgrader}
+	executor, err := remotecommand.NewSPDYExecutorForTransports(transport, conns, http.MethodPost, req.URL())
+	if err != nil {
+		return errors.Wrapf(err, "connecting to pod %s", opts.Pod)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- executor.Stream(remotecommand.StreamOptions{
+			Stdin:  opts.Stdin,
+			Stdout: opts.Stdout,
+			Stderr: opts.Stderr,
+		})
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		conns.close()
+		return ctx.Err()
+	}
+}
+
+// closingUpgrader keeps track of the connection it upgrades, so that it can be closed.
+type closingUpgrader struct {
+	spdy.Upgrader
+
+	lock   sync.Mutex
+	conn   httpstream.Connection
+	closed bool
+}
+
+func (u *closingUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.conn = conn
+	if u.closed {
+		conn.Close()
+	}
+	return conn, nil
+}
+
+// close closes the connection, now or as soon as it's upgraded.
+func (u *closingUpgrader) close() {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.closed = true
+	if u.conn != nil {
+		u.conn.Close()
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	restclient "k8s.io/client-go/rest"
+)
+
+// fakeExecServer accepts SPDY exec requests, echoes stdin to stdout
+// and ends the command with the given status.
+func fakeExecServer(t *testutil.T, status string, requests chan<- *http.Request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+
+		if _, err := httpstream.Handshake(r, w, []string{remotecommandconsts.StreamProtocolV4Name}); err != nil {
+			return
+		}
+
+		streams := make(chan httpstream.Stream, 4)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(stream httpstream.Stream, _ <-chan struct{}) error {
+			streams <- stream
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+
+		// The error stream is always there, the others only when requested.
+		expected := 1
+		for _, streamType := range []string{v1.StreamTypeStdin, v1.StreamTypeStdout, v1.StreamTypeStderr} {
+			if r.URL.Query().Get(streamType) == "true" {
+				expected++
+			}
+		}
+		byType := map[string]httpstream.Stream{}
+		for i := 0; i < expected; i++ {
+			stream := <-streams
+			byType[stream.Headers().Get(v1.StreamType)] = stream
+		}
+
+		if stderr, ok := byType[v1.StreamTypeStderr]; ok {
+			stderr.Write([]byte("starting"))
+			stderr.Close()
+		}
+		if stdout, ok := byType[v1.StreamTypeStdout]; ok {
+			// Reading stdin until the end tells that the client closes it.
+			if stdin, ok := byType[v1.StreamTypeStdin]; ok {
+				io.Copy(stdout, stdin)
+			}
+			stdout.Close()
+		}
+
+		errorStream := byType[v1.StreamTypeError]
+		errorStream.Write([]byte(status))
+		errorStream.Close()
+
+		<-conn.CloseChan()
+	}))
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		description string
+		status      string
+		stdin       io.Reader
+		expectedOut string
+		shouldErr   bool
+	}{
+		{
+			description: "success",
+			status:      `{"status":"Success"}`,
+			stdin:       bytes.NewBufferString("hello"),
+			expectedOut: "hello",
+		},
+		{
+			description: "without stdin",
+			status:      `{"status":"Success"}`,
+		},
+		{
+			description: "failure",
+			status:      `{"status":"Failure","message":"command terminated with non-zero exit code"}`,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			requests := make(chan *http.Request, 1)
+			server := fakeExecServer(t, test.status, requests)
+			defer server.Close()
+
+			var stdout, stderr bytes.Buffer
+			err := streamExec(context.Background(), &restclient.Config{Host: server.URL}, ExecOptions{
+				Namespace: "ns",
+				Pod:       "pod",
+				Container: "container",
+				Command:   []string{"tar", "xmf", "-"},
+				Stdin:     test.stdin,
+				Stdout:    &stdout,
+				Stderr:    &stderr,
+			})
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expectedOut, stdout.String())
+			t.CheckDeepEqual("starting", stderr.String())
+
+			request := <-requests
+			t.CheckDeepEqual(http.MethodPost, request.Method)
+			t.CheckDeepEqual("/api/v1/namespaces/ns/pods/pod/exec", request.URL.Path)
+			t.CheckDeepEqual([]string{"tar", "xmf", "-"}, request.URL.Query()["command"])
+			t.CheckDeepEqual("container", request.URL.Query().Get("container"))
+		})
+	}
+}
+
+func TestExecRejected(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "pods \"pod\" is forbidden", http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		err := streamExec(context.Background(), &restclient.Config{Host: server.URL}, ExecOptions{Namespace: "ns", Pod: "pod"})
+
+		t.CheckErrorContains("forbidden", err)
+	})
+}
+
+func TestExecCancelled(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		started := make(chan struct{})
+		closed := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := httpstream.Handshake(r, w, []string{remotecommandconsts.StreamProtocolV4Name}); err != nil {
+				return
+			}
+
+			conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(httpstream.Stream, <-chan struct{}) error {
+				return nil
+			})
+			if conn == nil {
+				return
+			}
+
+			// The command never ends.
+			close(started)
+			<-conn.CloseChan()
+			close(closed)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		err := streamExec(ctx, &restclient.Config{Host: server.URL}, ExecOptions{Namespace: "ns", Pod: "pod", Stdout: ioutil.Discard})
+		t.CheckDeepEqual(true, err == context.Canceled)
+
+		// The connection is closed, so the stream doesn't outlive the call.
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Errorf("the connection wasn't closed")
+		}
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os/exec"
 	"sync"
 	"sync/atomic"
@@ -32,19 +33,23 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runlog"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 )
 
 // Client is for tests
-var Client = GetClientset
-var DynamicClient = GetDynamicClient
+var Client = GetClientsetForContext
+var DynamicClient = GetDynamicClientForContext
 
 // LogAggregator aggregates the logs for all the deployed pods.
 type LogAggregator struct {
 	output      io.Writer
 	podSelector PodSelector
-	namespaces  []string
+	namespaces  map[string][]string
 	colorPicker ColorPicker
+	printer     *LogPrinter
+	archive     *runlog.Archive
+	diagnoser   *PodDiagnoser
 
 	muted             int32
 	startTime         time.Time
@@ -53,12 +58,18 @@ type LogAggregator struct {
 }
 
 // NewLogAggregator creates a new LogAggregator for a given output.
-func NewLogAggregator(out io.Writer, baseImageNames []string, podSelector PodSelector, namespaces []string) *LogAggregator {
+// namespaces lists, for each kubectl context, the namespaces to watch for pods.
+// printer filters and formats the lines of logs. archive, if not nil, receives every line of logs,
+// even when the logs are muted or filtered out. diagnoser, if not nil, explains why the pods crash or don't start.
+func NewLogAggregator(out io.Writer, baseImageNames []string, podSelector PodSelector, namespaces map[string][]string, printer *LogPrinter, archive *runlog.Archive, diagnoser *PodDiagnoser) *LogAggregator {
 	return &LogAggregator{
 		output:      out,
 		podSelector: podSelector,
 		namespaces:  namespaces,
 		colorPicker: NewColorPicker(baseImageNames),
+		printer:     printer,
+		archive:     archive,
+		diagnoser:   diagnoser,
 		trackedContainers: trackedContainers{
 			ids: map[string]bool{},
 		},
@@ -72,11 +83,22 @@ func (a *LogAggregator) Start(ctx context.Context) error {
 	a.cancel = cancel
 	a.startTime = time.Now()
 
+	for kubeContext, namespaces := range a.namespaces {
+		if err := a.watchPods(cancelCtx, kubeContext, namespaces); err != nil {
+			cancel()
+			return errors.Wrapf(err, "initializing aggregate pod watcher for context %s", kubeContext)
+		}
+	}
+
+	return nil
+}
+
+func (a *LogAggregator) watchPods(ctx context.Context, kubeContext string, namespaces []string) error {
 	aggregate := make(chan watch.Event)
-	stopWatchers, err := AggregatePodWatcher(a.namespaces, aggregate)
+	stopWatchers, err := AggregatePodWatcher(kubeContext, namespaces, aggregate)
 	if err != nil {
 		stopWatchers()
-		return errors.Wrap(err, "initializing aggregate pod watcher")
+		return err
 	}
 
 	go func() {
@@ -84,7 +106,7 @@ func (a *LogAggregator) Start(ctx context.Context) error {
 
 		for {
 			select {
-			case <-cancelCtx.Done():
+			case <-ctx.Done():
 				return
 			case evt, ok := <-aggregate:
 				if !ok {
@@ -100,11 +122,21 @@ func (a *LogAggregator) Start(ctx context.Context) error {
 					continue
 				}
 
+				a.diagnoser.Check(ctx, a.output, kubeContext, pod)
+
 				if !a.podSelector.Select(pod) {
 					continue
 				}
 
 				for _, container := range append(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses...) {
+					if cs, ok := a.podSelector.(containerSelector); ok && !cs.SelectContainer(container.Name) {
+						continue
+					}
+
+					if a.diagnoser.explains(pod, container.Name) {
+						continue
+					}
+
 					if container.ContainerID == "" {
 						if container.State.Waiting != nil && container.State.Waiting.Message != "" {
 							color.Red.Fprintln(a.output, container.State.Waiting.Message)
@@ -118,7 +150,7 @@ func (a *LogAggregator) Start(ctx context.Context) error {
 					}
 
 					if !a.trackedContainers.add(container.ContainerID) {
-						go a.streamContainerLogs(cancelCtx, pod, container)
+						go a.streamContainerLogs(ctx, kubeContext, pod, container)
 					}
 				}
 			}
@@ -145,8 +177,8 @@ func sinceSeconds(d time.Duration) int64 {
 	return 1
 }
 
-func (a *LogAggregator) streamContainerLogs(ctx context.Context, pod *v1.Pod, container v1.ContainerStatus) {
-	logrus.Infof("Stream logs from pod: %s container: %s", pod.Name, container.Name)
+func (a *LogAggregator) streamContainerLogs(ctx context.Context, kubeContext string, pod *v1.Pod, container v1.ContainerStatus) {
+	logrus.Infof("Stream logs from pod: %s container: %s context: %s", pod.Name, container.Name, kubeContext)
 
 	// In theory, it's more precise to use --since-time='' but there can be a time
 	// difference between the user's machine and the server.
@@ -154,14 +186,33 @@ func (a *LogAggregator) streamContainerLogs(ctx context.Context, pod *v1.Pod, co
 	sinceSeconds := fmt.Sprintf("--since=%ds", sinceSeconds(time.Since(a.startTime)))
 
 	tr, tw := io.Pipe()
-	cmd := exec.CommandContext(ctx, "kubectl", "logs", sinceSeconds, "-f", pod.Name, "-c", container.Name, "--namespace", pod.Namespace)
+	args := []string{"logs", sinceSeconds, "-f", pod.Name, "-c", container.Name, "--namespace", pod.Namespace}
+	if kubeContext != "" {
+		args = append([]string{"--context", kubeContext}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
 	cmd.Stdout = tw
 	go util.RunCmd(cmd)
 
 	color := a.colorPicker.Pick(pod)
 	prefix := prefix(pod, container)
+	if len(a.namespaces) > 1 {
+		// Pods with the same name can run in several clusters.
+		prefix = fmt.Sprintf("[%s]%s", kubeContext, prefix)
+	}
+	source := LogSource{
+		Pod:       pod.Name,
+		Container: container.Name,
+		Image:     containerImage(pod, container),
+		Labels:    pod.Labels,
+	}
+	archive, err := a.archive.Writer("pods", kubeContext, pod.Namespace, pod.Name, container.Name+".log")
+	if err != nil {
+		logrus.Warnf("archiving logs of %s: %s", prefix, err)
+		archive = ioutil.Discard
+	}
 	go func() {
-		if err := a.streamRequest(ctx, color, prefix, tr); err != nil {
+		if err := a.streamRequest(ctx, color, prefix, source, tr, archive); err != nil {
 			logrus.Errorf("streaming request %s", err)
 		}
 		a.trackedContainers.remove(container.ContainerID)
@@ -175,7 +226,23 @@ func prefix(pod *v1.Pod, container v1.ContainerStatus) string {
 	return fmt.Sprintf("[%s]", container.Name)
 }
 
-func (a *LogAggregator) streamRequest(ctx context.Context, headerColor color.Color, header string, rc io.Reader) error {
+// containerImage returns the image of a container, as written in the pod spec.
+func containerImage(pod *v1.Pod, container v1.ContainerStatus) string {
+	for _, c := range append(pod.Spec.Containers, pod.Spec.InitContainers...) {
+		if c.Name == container.Name {
+			return c.Image
+		}
+	}
+	return container.Image
+}
+
+func (a *LogAggregator) streamRequest(ctx context.Context, headerColor color.Color, header string, source LogSource, rc io.Reader, archive io.Writer) error {
+	return StreamLogs(ctx, a.output, a.printer, headerColor, header, source, rc, archive, a.IsMuted)
+}
+
+// StreamLogs reads the lines of logs of a container until the stream ends or the context is cancelled.
+// Each line goes to the archive and, unless the logs are muted, is printed with the given header.
+func StreamLogs(ctx context.Context, out io.Writer, printer *LogPrinter, headerColor color.Color, header string, source LogSource, rc io.Reader, archive io.Writer, muted func() bool) error {
 	r := bufio.NewReader(rc)
 	for {
 		select {
@@ -194,15 +261,16 @@ func (a *LogAggregator) streamRequest(ctx context.Context, headerColor color.Col
 			return errors.Wrap(err, "reading bytes from log stream")
 		}
 
-		if a.IsMuted() {
-			continue
+		if _, err := archive.Write(line); err != nil {
+			logrus.Debugf("archiving logs of %s: %s", header, err)
 		}
 
-		if _, err := headerColor.Fprintf(a.output, "%s ", header); err != nil {
-			return errors.Wrap(err, "writing pod prefix header to out")
+		if muted() {
+			continue
 		}
-		if _, err := fmt.Fprint(a.output, string(line)); err != nil {
-			return errors.Wrap(err, "writing pod log to out")
+
+		if err := printer.Print(out, headerColor, header, source, string(line)); err != nil {
+			return err
 		}
 	}
 	logrus.Infof("%s exited", header)
@@ -0,0 +1,205 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// logLevels ranks the levels found in JSON logs.
+var logLevels = map[string]int{
+	"trace":     0,
+	"debug":     1,
+	"info":      2,
+	"notice":    2,
+	"warn":      3,
+	"warning":   3,
+	"error":     4,
+	"err":       4,
+	"critical":  5,
+	"fatal":     5,
+	"panic":     5,
+	"alert":     5,
+	"emergency": 5,
+}
+
+// levelFields are the fields that usually hold the level of JSON logs.
+var levelFields = []string{"level", "severity", "lvl", "loglevel"}
+
+// LogSource describes the container that a line of logs comes from.
+type LogSource struct {
+	Pod       string
+	Container string
+	Image     string
+	Labels    map[string]string
+}
+
+// LogFilter selects the containers and the lines of logs to print.
+// A nil LogFilter selects everything.
+type LogFilter struct {
+	images     []string
+	containers []string
+	selector   labels.Selector
+	include    *regexp.Regexp
+	exclude    *regexp.Regexp
+	minLevel   int
+}
+
+// NewLogFilter creates a LogFilter from the command line options.
+func NewLogFilter(opts config.LogOptions) (*LogFilter, error) {
+	filter := &LogFilter{
+		images:     opts.Images,
+		containers: opts.Containers,
+		minLevel:   -1,
+	}
+
+	if opts.Selector != "" {
+		selector, err := labels.Parse(opts.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing log selector %s", opts.Selector)
+		}
+		filter.selector = selector
+	}
+
+	if opts.Include != "" {
+		include, err := regexp.Compile(opts.Include)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing log include pattern %s", opts.Include)
+		}
+		filter.include = include
+	}
+
+	if opts.Exclude != "" {
+		exclude, err := regexp.Compile(opts.Exclude)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing log exclude pattern %s", opts.Exclude)
+		}
+		filter.exclude = exclude
+	}
+
+	if opts.Level != "" {
+		level, found := logLevels[strings.ToLower(opts.Level)]
+		if !found {
+			return nil, fmt.Errorf("unknown log level %s", opts.Level)
+		}
+		filter.minLevel = level
+	}
+
+	return filter, nil
+}
+
+// selectSource tells if the logs of a container should be printed.
+func (f *LogFilter) selectSource(source LogSource) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.images) > 0 && !containsAny(source.Image, f.images) {
+		return false
+	}
+	if len(f.containers) > 0 && !isOneOf(source.Container, f.containers) {
+		return false
+	}
+	if f.selector != nil && !f.selector.Matches(labels.Set(source.Labels)) {
+		return false
+	}
+	return true
+}
+
+// selectLine tells if a line of logs should be printed. fields holds
+// the content of the line if it's a JSON object, and is nil otherwise.
+func (f *LogFilter) selectLine(text string, fields map[string]interface{}) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.include != nil && !f.include.MatchString(text) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(text) {
+		return false
+	}
+	if f.minLevel >= 0 {
+		// Lines without a known level are always printed.
+		if level, found := levelOf(fields); found && level < f.minLevel {
+			return false
+		}
+	}
+	return true
+}
+
+// levelOf finds the level of a JSON log line. Numeric levels follow
+// the bunyan and pino convention: 10 for trace up to 60 for fatal.
+func levelOf(fields map[string]interface{}) (int, bool) {
+	for _, name := range levelFields {
+		for key, value := range fields {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+
+			switch v := value.(type) {
+			case string:
+				if level, found := logLevels[strings.ToLower(v)]; found {
+					return level, true
+				}
+				if n, err := strconv.Atoi(v); err == nil {
+					return numericLevel(n), true
+				}
+			case float64:
+				return numericLevel(int(v)), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func numericLevel(n int) int {
+	level := n/10 - 1
+	switch {
+	case level < 0:
+		return 0
+	case level > 5:
+		return 5
+	default:
+		return level
+	}
+}
+
+func containsAny(value string, substrings []string) bool {
+	for _, s := range substrings {
+		if strings.Contains(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isOneOf(value string, values []string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNewLogFilter(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        config.LogOptions
+		shouldErr   bool
+	}{
+		{
+			description: "no filter",
+		},
+		{
+			description: "valid options",
+			opts:        config.LogOptions{Selector: "app=web,tier!=db", Include: "GET .*", Exclude: "healthz", Level: "WARN"},
+		},
+		{
+			description: "invalid selector",
+			opts:        config.LogOptions{Selector: "app in (web"},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid include pattern",
+			opts:        config.LogOptions{Include: "("},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid exclude pattern",
+			opts:        config.LogOptions{Exclude: "["},
+			shouldErr:   true,
+		},
+		{
+			description: "unknown level",
+			opts:        config.LogOptions{Level: "verbose"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			_, err := NewLogFilter(test.opts)
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestSelectSource(t *testing.T) {
+	source := LogSource{
+		Pod:       "web-1",
+		Container: "server",
+		Image:     "gcr.io/project/web:v1",
+		Labels:    map[string]string{"app": "web"},
+	}
+
+	tests := []struct {
+		description string
+		opts        config.LogOptions
+		expected    bool
+	}{
+		{
+			description: "no filter",
+			expected:    true,
+		},
+		{
+			description: "matching image",
+			opts:        config.LogOptions{Images: []string{"other", "project/web"}},
+			expected:    true,
+		},
+		{
+			description: "other image",
+			opts:        config.LogOptions{Images: []string{"db"}},
+		},
+		{
+			description: "matching container",
+			opts:        config.LogOptions{Containers: []string{"server"}},
+			expected:    true,
+		},
+		{
+			description: "container names must be equal",
+			opts:        config.LogOptions{Containers: []string{"serv"}},
+		},
+		{
+			description: "matching labels",
+			opts:        config.LogOptions{Selector: "app in (web, api)"},
+			expected:    true,
+		},
+		{
+			description: "other labels",
+			opts:        config.LogOptions{Selector: "app=db"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			filter, err := NewLogFilter(test.opts)
+			t.CheckNoError(err)
+
+			t.CheckDeepEqual(test.expected, filter.selectSource(source))
+		})
+	}
+}
+
+func TestSelectLine(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        config.LogOptions
+		line        string
+		expected    bool
+	}{
+		{
+			description: "no filter",
+			line:        "GET /healthz",
+			expected:    true,
+		},
+		{
+			description: "included",
+			opts:        config.LogOptions{Include: "^GET"},
+			line:        "GET /index.html",
+			expected:    true,
+		},
+		{
+			description: "not included",
+			opts:        config.LogOptions{Include: "^GET"},
+			line:        "POST /form",
+		},
+		{
+			description: "excluded",
+			opts:        config.LogOptions{Exclude: "healthz"},
+			line:        "GET /healthz",
+		},
+		{
+			description: "level above minimum",
+			opts:        config.LogOptions{Level: "warn"},
+			line:        `{"level":"ERROR","msg":"failed"}`,
+			expected:    true,
+		},
+		{
+			description: "level below minimum",
+			opts:        config.LogOptions{Level: "warn"},
+			line:        `{"severity":"info","msg":"started"}`,
+		},
+		{
+			description: "numeric level",
+			opts:        config.LogOptions{Level: "warn"},
+			line:        `{"level":30,"msg":"started"}`,
+		},
+		{
+			description: "line without level",
+			opts:        config.LogOptions{Level: "warn"},
+			line:        `{"msg":"started"}`,
+			expected:    true,
+		},
+		{
+			description: "not JSON",
+			opts:        config.LogOptions{Level: "warn"},
+			line:        "INFO started",
+			expected:    true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			filter, err := NewLogFilter(test.opts)
+			t.CheckNoError(err)
+
+			t.CheckDeepEqual(test.expected, filter.selectLine(test.line, parseJSONLine(test.line)))
+		})
+	}
+}
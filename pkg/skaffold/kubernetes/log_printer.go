@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/pkg/errors"
+)
+
+// Log formats
+const (
+	TextLogFormat = "text"
+	JSONLogFormat = "json"
+)
+
+// For testing
+var logTimestamp = time.Now
+
+// LogPrinter filters the logs of containers and prints them, either as text with
+// a colored header, or as JSON objects for tooling.
+// A nil LogPrinter prints every line as text.
+type LogPrinter struct {
+	lock   sync.RWMutex
+	filter *LogFilter
+
+	json   bool
+	fields []string
+}
+
+// logRecord is a line of logs printed in the JSON format.
+type logRecord struct {
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// MessageWriter prints Skaffold's own messages as JSON records, so that they
+// can be told apart from the logs of the containers in the JSON format.
+type MessageWriter struct {
+	lock sync.Mutex
+	out  io.Writer
+	line []byte
+}
+
+// NewMessageWriter creates a MessageWriter that prints the records to out.
+func NewMessageWriter(out io.Writer) *MessageWriter {
+	return &MessageWriter{out: out}
+}
+
+// Write prints every complete line as a record whose source is `skaffold`.
+// Blank lines are dropped.
+func (w *MessageWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.line = append(w.line, p...)
+	for {
+		end := bytes.IndexByte(w.line, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+
+		message := strings.TrimRight(string(w.line[:end]), "\r")
+		w.line = w.line[end+1:]
+		if strings.TrimSpace(message) == "" {
+			continue
+		}
+
+		record := logRecord{
+			Timestamp: logTimestamp().Format(time.RFC3339Nano),
+			Source:    "skaffold",
+			Message:   message,
+		}
+		if err := json.NewEncoder(w.out).Encode(record); err != nil {
+			return 0, errors.Wrap(err, "writing message record to out")
+		}
+	}
+}
+
+// NewLogPrinter creates a LogPrinter from the command line options.
+func NewLogPrinter(opts config.LogOptions) (*LogPrinter, error) {
+	filter, err := NewLogFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	printer := &LogPrinter{
+		filter: filter,
+		fields: opts.Fields,
+	}
+
+	switch opts.Format {
+	case "", TextLogFormat:
+	case JSONLogFormat:
+		printer.json = true
+	default:
+		return nil, fmt.Errorf("unknown log format %s, should be %s or %s", opts.Format, TextLogFormat, JSONLogFormat)
+	}
+
+	return printer, nil
+}
+
+// SetFilter replaces the filter while logs are being printed.
+func (p *LogPrinter) SetFilter(filter *LogFilter) {
+	p.lock.Lock()
+	p.filter = filter
+	p.lock.Unlock()
+}
+
+// SetLogFilter replaces the filter with one given through the API.
+func (p *LogPrinter) SetLogFilter(filter *proto.LogFilter) error {
+	logFilter, err := NewLogFilter(config.LogOptions{
+		Images:     filter.Images,
+		Containers: filter.Containers,
+		Selector:   filter.Selector,
+		Include:    filter.Include,
+		Exclude:    filter.Exclude,
+		Level:      filter.Level,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.SetFilter(logFilter)
+	return nil
+}
+
+func (p *LogPrinter) currentFilter() *LogFilter {
+	if p == nil {
+		return nil
+	}
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.filter
+}
+
+// Print writes a line of logs from a container, unless it's filtered out.
+func (p *LogPrinter) Print(out io.Writer, headerColor color.Color, header string, source LogSource, line string) error {
+	text := strings.TrimSuffix(line, "\n")
+	fields := parseJSONLine(text)
+
+	filter := p.currentFilter()
+	if !filter.selectSource(source) || !filter.selectLine(text, fields) {
+		return nil
+	}
+
+	if p != nil && p.json {
+		// Log records go through unchanged.
+		if w, ok := out.(*MessageWriter); ok {
+			w.lock.Lock()
+			defer w.lock.Unlock()
+			out = w.out
+		}
+
+		record := logRecord{
+			Timestamp: logTimestamp().Format(time.RFC3339Nano),
+			Pod:       source.Pod,
+			Container: source.Container,
+			Message:   text,
+		}
+		return errors.Wrap(json.NewEncoder(out).Encode(record), "writing log record to out")
+	}
+
+	if p != nil && len(p.fields) > 0 && fields != nil {
+		if formatted := formatFields(fields, p.fields); formatted != "" {
+			text = formatted
+		}
+	}
+
+	if _, err := headerColor.Fprintf(out, "%s ", header); err != nil {
+		return errors.Wrap(err, "writing container prefix header to out")
+	}
+	if _, err := fmt.Fprintln(out, text); err != nil {
+		return errors.Wrap(err, "writing container log to out")
+	}
+	return nil
+}
+
+// parseJSONLine returns the fields of a line of logs that is a JSON object, or nil.
+func parseJSONLine(text string) map[string]interface{} {
+	if !strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// formatFields prints the chosen fields of a JSON log line as `key=value` pairs.
+// Fields are looked up without considering case.
+func formatFields(fields map[string]interface{}, chosen []string) string {
+	var pairs []string
+	for _, name := range chosen {
+		for key, value := range fields {
+			if strings.EqualFold(key, name) {
+				pairs = append(pairs, fmt.Sprintf("%s=%s", key, formatValue(value)))
+				break
+			}
+		}
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func formatValue(value interface{}) string {
+	var s string
+	if str, ok := value.(string); ok {
+		s = str
+	} else {
+		buf, _ := json.Marshal(value)
+		s = string(buf)
+	}
+
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
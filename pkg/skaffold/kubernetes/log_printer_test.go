@@ -0,0 +1,165 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestLogPrinter(t *testing.T) {
+	source := LogSource{
+		Pod:       "web-1",
+		Container: "server",
+		Image:     "web",
+	}
+
+	tests := []struct {
+		description string
+		opts        config.LogOptions
+		line        string
+		expected    string
+	}{
+		{
+			description: "text",
+			line:        "started\n",
+			expected:    "[web-1 server] started\n",
+		},
+		{
+			description: "filtered out",
+			opts:        config.LogOptions{Containers: []string{"sidecar"}},
+			line:        "started\n",
+		},
+		{
+			description: "chosen fields",
+			opts:        config.LogOptions{Fields: []string{"level", "msg", "status"}},
+			line:        `{"time":"12:00","Level":"info","msg":"request served","status":200}` + "\n",
+			expected:    `[web-1 server] Level=info msg="request served" status=200` + "\n",
+		},
+		{
+			description: "no chosen field",
+			opts:        config.LogOptions{Fields: []string{"msg"}},
+			line:        `{"message":"started"}` + "\n",
+			expected:    `[web-1 server] {"message":"started"}` + "\n",
+		},
+		{
+			description: "fields of a text line",
+			opts:        config.LogOptions{Fields: []string{"msg"}},
+			line:        "started\n",
+			expected:    "[web-1 server] started\n",
+		},
+		{
+			description: "json",
+			opts:        config.LogOptions{Format: "json"},
+			line:        `{"msg":"started"}` + "\n",
+			expected:    `{"timestamp":"2019-06-13T10:30:00Z","pod":"web-1","container":"server","message":"{\"msg\":\"started\"}"}` + "\n",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&logTimestamp, func() time.Time { return time.Date(2019, 6, 13, 10, 30, 0, 0, time.UTC) })
+
+			printer, err := NewLogPrinter(test.opts)
+			t.CheckNoError(err)
+
+			var out bytes.Buffer
+			err = printer.Print(&out, color.Default, "[web-1 server]", source, test.line)
+
+			t.CheckErrorAndDeepEqual(false, err, test.expected, out.String())
+		})
+	}
+}
+
+func TestMessageWriter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&logTimestamp, func() time.Time { return time.Date(2019, 6, 13, 10, 30, 0, 0, time.UTC) })
+		printer, err := NewLogPrinter(config.LogOptions{Format: "json"})
+		t.CheckNoError(err)
+
+		var out bytes.Buffer
+		w := NewMessageWriter(&out)
+		fmt.Fprint(w, "Deploying...\n\nWatching ")
+		printer.Print(w, color.Default, "[web-1 server]", LogSource{Pod: "web-1", Container: "server"}, "started\n")
+		fmt.Fprint(w, "for changes...\r\n")
+
+		t.CheckDeepEqual(`{"timestamp":"2019-06-13T10:30:00Z","source":"skaffold","message":"Deploying..."}
+{"timestamp":"2019-06-13T10:30:00Z","pod":"web-1","container":"server","message":"started"}
+{"timestamp":"2019-06-13T10:30:00Z","source":"skaffold","message":"Watching for changes..."}
+`, out.String())
+	})
+}
+
+func TestLogPrinterUnknownFormat(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		_, err := NewLogPrinter(config.LogOptions{Format: "yaml"})
+
+		t.CheckErrorContains("unknown log format", err)
+	})
+}
+
+func TestLogPrinterSetFilter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		printer, err := NewLogPrinter(config.LogOptions{})
+		t.CheckNoError(err)
+		filter, err := NewLogFilter(config.LogOptions{Exclude: "healthz"})
+		t.CheckNoError(err)
+
+		var out bytes.Buffer
+		printer.Print(&out, color.Default, "[web]", LogSource{}, "GET /healthz\n")
+		printer.SetFilter(filter)
+		printer.Print(&out, color.Default, "[web]", LogSource{}, "GET /healthz\n")
+		printer.Print(&out, color.Default, "[web]", LogSource{}, "GET /index.html\n")
+
+		t.CheckDeepEqual("[web] GET /healthz\n[web] GET /index.html\n", out.String())
+	})
+}
+
+func TestLogPrinterSetLogFilter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		printer, err := NewLogPrinter(config.LogOptions{})
+		t.CheckNoError(err)
+
+		err = printer.SetLogFilter(&proto.LogFilter{Containers: []string{"web"}})
+		t.CheckNoError(err)
+		err = printer.SetLogFilter(&proto.LogFilter{Level: "unknown"})
+		t.CheckErrorContains("unknown log level", err)
+
+		var out bytes.Buffer
+		printer.Print(&out, color.Default, "[db]", LogSource{Container: "db"}, "started\n")
+		printer.Print(&out, color.Default, "[web]", LogSource{Container: "web"}, "started\n")
+
+		t.CheckDeepEqual("[web] started\n", out.String())
+	})
+}
+
+func TestNilLogPrinter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		var printer *LogPrinter
+
+		var out bytes.Buffer
+		err := printer.Print(&out, color.Default, "[web]", LogSource{}, "started\n")
+
+		t.CheckErrorAndDeepEqual(false, err, "[web] started\n", out.String())
+	})
+}
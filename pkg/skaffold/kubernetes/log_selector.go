@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"path"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// TailSelector selects the pods to tail: the pods selected by a base selector,
+// usually the images built by Skaffold, and the pods selected by any of
+// the additional selectors given on the command line.
+type TailSelector struct {
+	base      PodSelector
+	selectors []labels.Selector
+	images    []string
+	excluded  []string
+}
+
+// NewTailSelector creates a TailSelector from the command line options.
+// runSelectors select the pods deployed by the current run.
+func NewTailSelector(base PodSelector, opts config.LogOptions, runSelectors []labels.Selector) (*TailSelector, error) {
+	for _, pattern := range opts.TailImages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "parsing image pattern %s", pattern)
+		}
+	}
+
+	s := &TailSelector{
+		base:     base,
+		images:   opts.TailImages,
+		excluded: opts.ExcludeContainers,
+	}
+
+	for _, selector := range opts.TailSelectors {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing selector %s", selector)
+		}
+		s.selectors = append(s.selectors, parsed)
+	}
+	if opts.TailRun {
+		s.selectors = append(s.selectors, runSelectors...)
+	}
+
+	return s, nil
+}
+
+// Select returns true if the base selector or one of the additional selectors selects the pod.
+func (s *TailSelector) Select(pod *v1.Pod) bool {
+	if s.base != nil && s.base.Select(pod) {
+		return true
+	}
+
+	for _, selector := range s.selectors {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, pattern := range s.images {
+			if matchImage(pattern, container.Image) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchImage matches a glob pattern against an image, or against its last path element,
+// so that `postgres:*` matches `docker.io/library/postgres:11`.
+func matchImage(pattern, image string) bool {
+	if matched, _ := path.Match(pattern, image); matched {
+		return true
+	}
+	matched, _ := path.Match(pattern, path.Base(image))
+	return matched
+}
+
+// containerSelector is implemented by the pod selectors that also choose which containers to tail.
+type containerSelector interface {
+	SelectContainer(name string) bool
+}
+
+// SelectContainer returns false for the containers that should never be tailed.
+func (s *TailSelector) SelectContainer(name string) bool {
+	return !isOneOf(name, s.excluded)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestNewTailSelector(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        config.LogOptions
+		shouldErr   bool
+	}{
+		{
+			description: "no options",
+		},
+		{
+			description: "valid options",
+			opts:        config.LogOptions{TailSelectors: []string{"app=db", "tier in (cache)"}, TailImages: []string{"postgres:*"}, TailRun: true},
+		},
+		{
+			description: "invalid selector",
+			opts:        config.LogOptions{TailSelectors: []string{"app in (db"}},
+			shouldErr:   true,
+		},
+		{
+			description: "invalid image pattern",
+			opts:        config.LogOptions{TailImages: []string{"postgres:["}},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			_, err := NewTailSelector(NewImageList(), test.opts, runSelectors())
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestTailSelectorSelect(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        config.LogOptions
+		pod         *v1.Pod
+		expected    bool
+	}{
+		{
+			description: "image built by skaffold",
+			pod:         podWithImage("app", "skaffold/app:abcd", nil),
+			expected:    true,
+		},
+		{
+			description: "other image",
+			pod:         podWithImage("db", "postgres:11", nil),
+		},
+		{
+			description: "matching selector",
+			opts:        config.LogOptions{TailSelectors: []string{"app=db"}},
+			pod:         podWithImage("db", "postgres:11", map[string]string{"app": "db"}),
+			expected:    true,
+		},
+		{
+			description: "not matching selector",
+			opts:        config.LogOptions{TailSelectors: []string{"app=db"}},
+			pod:         podWithImage("cache", "redis", map[string]string{"app": "cache"}),
+		},
+		{
+			description: "matching image pattern",
+			opts:        config.LogOptions{TailImages: []string{"postgres:*"}},
+			pod:         podWithImage("db", "docker.io/library/postgres:11", nil),
+			expected:    true,
+		},
+		{
+			description: "matching full image pattern",
+			opts:        config.LogOptions{TailImages: []string{"gcr.io/project/*"}},
+			pod:         podWithImage("db", "gcr.io/project/db", nil),
+			expected:    true,
+		},
+		{
+			description: "not matching image pattern",
+			opts:        config.LogOptions{TailImages: []string{"postgres:*"}},
+			pod:         podWithImage("cache", "redis:5", nil),
+		},
+		{
+			description: "deployed by this run",
+			opts:        config.LogOptions{TailRun: true},
+			pod:         podWithImage("db", "postgres:11", map[string]string{"skaffold.dev/run-id": "1234"}),
+			expected:    true,
+		},
+		{
+			description: "deployed by another run",
+			opts:        config.LogOptions{TailRun: true},
+			pod:         podWithImage("db", "postgres:11", map[string]string{"skaffold.dev/run-id": "5678"}),
+		},
+		{
+			description: "released by this run",
+			opts:        config.LogOptions{TailRun: true},
+			pod:         podWithImage("db", "postgres:11", map[string]string{"release": "db"}),
+			expected:    true,
+		},
+		{
+			description: "released by another run",
+			opts:        config.LogOptions{TailRun: true},
+			pod:         podWithImage("db", "postgres:11", map[string]string{"release": "other"}),
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			imageList := NewImageList()
+			imageList.Add("skaffold/app:abcd")
+
+			selector, err := NewTailSelector(imageList, test.opts, runSelectors())
+			t.CheckNoError(err)
+
+			t.CheckDeepEqual(test.expected, selector.Select(test.pod))
+		})
+	}
+}
+
+func TestSelectContainer(t *testing.T) {
+	selector, err := NewTailSelector(NewImageList(), config.LogOptions{ExcludeContainers: []string{"istio-proxy"}}, nil)
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, true, selector.SelectContainer("app"))
+	testutil.CheckDeepEqual(t, false, selector.SelectContainer("istio-proxy"))
+}
+
+func podWithImage(name, image string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: name, Image: image}},
+		},
+	}
+}
+
+// runSelectors select the pods labelled with the run id 1234, or released as `db`.
+func runSelectors() []labels.Selector {
+	return []labels.Selector{
+		labels.SelectorFromSet(map[string]string{"skaffold.dev/run-id": "1234"}),
+		labels.SelectorFromSet(map[string]string{"release": "db"}),
+	}
+}
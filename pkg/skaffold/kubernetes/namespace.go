@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CreateNamespace creates a namespace with the given labels in a kubectl context.
+// It returns false if the namespace already exists.
+func CreateNamespace(kubeContext string, name string, labels map[string]string) (bool, error) {
+	client, err := Client(kubeContext)
+	if err != nil {
+		return false, errors.Wrap(err, "getting k8s client")
+	}
+
+	if _, err := client.CoreV1().Namespaces().Get(name, metav1.GetOptions{}); err == nil {
+		return false, nil
+	} else if !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "getting namespace %s", name)
+	}
+
+	_, err = client.CoreV1().Namespaces().Create(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	})
+	if apierrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "creating namespace %s", name)
+	}
+	return true, nil
+}
+
+// DeleteNamespace deletes a namespace from a kubectl context, but only if it carries
+// all the given labels. This protects namespaces that were not created by skaffold.
+// It returns false if the namespace was not deleted.
+func DeleteNamespace(kubeContext string, name string, labels map[string]string) (bool, error) {
+	client, err := Client(kubeContext)
+	if err != nil {
+		return false, errors.Wrap(err, "getting k8s client")
+	}
+
+	ns, err := client.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "getting namespace %s", name)
+	}
+
+	for k, v := range labels {
+		if ns.Labels[k] != v {
+			logrus.Debugf("Not deleting namespace %s: label %s is not %s", name, k, v)
+			return false, nil
+		}
+	}
+
+	if err := client.CoreV1().Namespaces().Delete(name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "deleting namespace %s", name)
+	}
+	return true, nil
+}
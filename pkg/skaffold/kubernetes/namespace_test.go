@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func namespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestCreateNamespace(t *testing.T) {
+	tests := []struct {
+		description     string
+		existing        []runtime.Object
+		expectedCreated bool
+		expectedLabels  map[string]string
+	}{
+		{
+			description:     "create missing namespace",
+			expectedCreated: true,
+			expectedLabels:  map[string]string{"skaffold.dev/owner": "jane"},
+		},
+		{
+			description:    "keep existing namespace",
+			existing:       []runtime.Object{namespace("dev", map[string]string{"team": "a"})},
+			expectedLabels: map[string]string{"team": "a"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.existing...)
+			t.Override(&Client, func(kubeContext string) (kubernetes.Interface, error) {
+				t.CheckDeepEqual("cluster1", kubeContext)
+				return client, nil
+			})
+
+			created, err := CreateNamespace("cluster1", "dev", map[string]string{"skaffold.dev/owner": "jane"})
+			t.CheckErrorAndDeepEqual(false, err, test.expectedCreated, created)
+
+			ns, err := client.CoreV1().Namespaces().Get("dev", metav1.GetOptions{})
+			t.CheckErrorAndDeepEqual(false, err, test.expectedLabels, ns.Labels)
+		})
+	}
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	tests := []struct {
+		description     string
+		existing        []runtime.Object
+		expectedDeleted bool
+	}{
+		{
+			description:     "delete owned namespace",
+			existing:        []runtime.Object{namespace("dev", map[string]string{"skaffold.dev/owner": "jane", "other": "label"})},
+			expectedDeleted: true,
+		},
+		{
+			description: "keep namespace owned by someone else",
+			existing:    []runtime.Object{namespace("dev", map[string]string{"skaffold.dev/owner": "john"})},
+		},
+		{
+			description: "keep namespace without labels",
+			existing:    []runtime.Object{namespace("dev", nil)},
+		},
+		{
+			description: "missing namespace",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.existing...)
+			t.Override(&Client, func(string) (kubernetes.Interface, error) { return client, nil })
+
+			deleted, err := DeleteNamespace("cluster1", "dev", map[string]string{"skaffold.dev/owner": "jane"})
+			t.CheckErrorAndDeepEqual(false, err, test.expectedDeleted, deleted)
+
+			namespaces, err := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+			t.CheckNoError(err)
+			t.CheckDeepEqual(len(test.existing) > 0 && !test.expectedDeleted, len(namespaces.Items) > 0)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// podCacheRetryDelay is the time to wait before watching pods again after the watch ended.
+var podCacheRetryDelay = time.Second
+
+// PodCache keeps track of the pods matching a label selector in the namespaces
+// of a kubectl context, so that they don't have to be listed over and over.
+type PodCache struct {
+	kubeContext string
+	namespaces  []string
+	selector    labels.Selector
+
+	lock    sync.Mutex
+	started bool
+	pods    map[types.UID]v1.Pod
+	// stop is closed to stop the current watches. Each start gets a new one.
+	stop chan struct{}
+}
+
+// NewPodCache returns a PodCache for the pods matching a label selector.
+// Pods are watched from the first call to RunningPods.
+func NewPodCache(kubeContext string, namespaces []string, selector map[string]string) *PodCache {
+	return &PodCache{
+		kubeContext: kubeContext,
+		namespaces:  namespaces,
+		selector:    labels.SelectorFromSet(selector),
+	}
+}
+
+// RunningPods returns the running pods that aren't being deleted.
+func (c *PodCache) RunningPods() ([]v1.Pod, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.started {
+		if err := c.start(); err != nil {
+			return nil, err
+		}
+		c.started = true
+	}
+
+	var pods []v1.Pod
+	for _, pod := range c.pods {
+		if pod.Status.Phase == v1.PodRunning && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// Stop stops watching the pods.
+func (c *PodCache) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.started {
+		close(c.stop)
+		c.started = false
+	}
+}
+
+// start lists the pods and watches them. If a namespace can't be watched,
+// the watches already started are stopped.
+func (c *PodCache) start() error {
+	client, err := Client(c.kubeContext)
+	if err != nil {
+		return errors.Wrap(err, "getting k8s client")
+	}
+
+	c.pods = map[types.UID]v1.Pod{}
+	c.stop = make(chan struct{})
+	if err := c.watchNamespaces(client, c.stop); err != nil {
+		close(c.stop)
+		return err
+	}
+	return nil
+}
+
+func (c *PodCache) watchNamespaces(client kubernetes.Interface, stop chan struct{}) error {
+	for _, ns := range c.namespaces {
+		list, err := client.CoreV1().Pods(ns).List(meta_v1.ListOptions{
+			LabelSelector: c.selector.String(),
+		})
+		if err != nil {
+			return errors.Wrap(err, "listing pods in namespace "+ns)
+		}
+
+		for _, pod := range list.Items {
+			c.pods[pod.UID] = pod
+		}
+
+		w, err := client.CoreV1().Pods(ns).Watch(meta_v1.ListOptions{
+			LabelSelector:   c.selector.String(),
+			ResourceVersion: list.ResourceVersion,
+		})
+		if err != nil {
+			return errors.Wrap(err, "watching pods in namespace "+ns)
+		}
+
+		go c.watch(ns, w, stop)
+	}
+
+	return nil
+}
+
+// watch updates the cache with the events of a watcher, and watches the
+// namespace again when the API server ends the watch.
+func (c *PodCache) watch(ns string, w watch.Interface, stop chan struct{}) {
+	for {
+		if !c.consume(w, stop) {
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(podCacheRetryDelay):
+		}
+
+		client, err := Client(c.kubeContext)
+		if err != nil {
+			logrus.Warnln("Unable to watch pods again:", err)
+			continue
+		}
+
+		// The resource version of the last event could be too old by now, so start afresh.
+		list, err := client.CoreV1().Pods(ns).List(meta_v1.ListOptions{
+			LabelSelector: c.selector.String(),
+		})
+		if err != nil {
+			logrus.Warnln("Unable to list pods again:", err)
+			continue
+		}
+
+		c.lock.Lock()
+		for uid, pod := range c.pods {
+			if pod.Namespace == ns {
+				delete(c.pods, uid)
+			}
+		}
+		for _, pod := range list.Items {
+			c.pods[pod.UID] = pod
+		}
+		c.lock.Unlock()
+
+		w, err = client.CoreV1().Pods(ns).Watch(meta_v1.ListOptions{
+			LabelSelector:   c.selector.String(),
+			ResourceVersion: list.ResourceVersion,
+		})
+		if err != nil {
+			logrus.Warnln("Unable to watch pods again:", err)
+			continue
+		}
+	}
+}
+
+// consume processes the events of a watcher until it ends.
+// It returns false if the cache was stopped.
+func (c *PodCache) consume(w watch.Interface, stop chan struct{}) bool {
+	defer w.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return false
+
+		case evt, ok := <-w.ResultChan():
+			if !ok {
+				return true
+			}
+
+			pod, ok := evt.Object.(*v1.Pod)
+			if !ok || !c.selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+
+			c.lock.Lock()
+			switch evt.Type {
+			case watch.Added, watch.Modified:
+				c.pods[pod.UID] = *pod
+			case watch.Deleted:
+				delete(c.pods, pod.UID)
+			}
+			c.lock.Unlock()
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	fake_testing "k8s.io/client-go/testing"
+)
+
+func labelledPod(name, namespace string, labels map[string]string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(namespace + "/" + name),
+			Labels:    labels,
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func podNames(pods []v1.Pod) []string {
+	var names []string
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPodCache(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		run := map[string]string{"skaffold.dev/run-id": "1234"}
+		client := fake.NewSimpleClientset(
+			labelledPod("web", "ns1", run, v1.PodRunning),
+			labelledPod("pending", "ns1", run, v1.PodPending),
+			labelledPod("other-run", "ns1", map[string]string{"skaffold.dev/run-id": "5678"}, v1.PodRunning),
+			labelledPod("app", "ns2", run, v1.PodRunning),
+			labelledPod("not-watched", "ns3", run, v1.PodRunning),
+		)
+		t.Override(&Client, func(string) (kubernetes.Interface, error) { return client, nil })
+
+		cache := NewPodCache("kubecontext", []string{"ns1", "ns2"}, run)
+		defer cache.Stop()
+
+		pods, err := cache.RunningPods()
+		t.CheckErrorAndDeepEqual(false, err, []string{"app", "web"}, podNames(pods))
+
+		// Pods are updated by watching them.
+		_, err = client.CoreV1().Pods("ns1").UpdateStatus(labelledPod("pending", "ns1", run, v1.PodRunning))
+		t.CheckNoError(err)
+		err = client.CoreV1().Pods("ns2").Delete("app", nil)
+		t.CheckNoError(err)
+
+		var names []string
+		for i := 0; i < 100; i++ {
+			pods, err = cache.RunningPods()
+			t.CheckNoError(err)
+			if names = podNames(pods); len(names) == 2 && names[0] == "pending" {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.CheckDeepEqual([]string{"pending", "web"}, names)
+	})
+}
+
+func TestPodCacheRestart(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		run := map[string]string{"skaffold.dev/run-id": "1234"}
+		client := fake.NewSimpleClientset(labelledPod("web", "ns1", run, v1.PodRunning))
+		var watchers []*watch.RaceFreeFakeWatcher
+		failing := true
+		client.PrependWatchReactor("pods", func(action fake_testing.Action) (bool, watch.Interface, error) {
+			if failing && action.GetNamespace() == "ns2" {
+				return true, nil, errors.New("forbidden")
+			}
+			w := watch.NewRaceFreeFake()
+			watchers = append(watchers, w)
+			return true, w, nil
+		})
+		t.Override(&Client, func(string) (kubernetes.Interface, error) { return client, nil })
+
+		cache := NewPodCache("kubecontext", []string{"ns1", "ns2"}, run)
+		defer cache.Stop()
+
+		// The watch of ns1 is stopped when ns2 can't be watched.
+		_, err := cache.RunningPods()
+		t.CheckError(true, err)
+		t.CheckDeepEqual(1, len(watchers))
+		for i := 0; i < 100 && !watchers[0].IsStopped(); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.CheckDeepEqual(true, watchers[0].IsStopped())
+
+		// The pods are watched again after a stop.
+		failing = false
+		pods, err := cache.RunningPods()
+		t.CheckErrorAndDeepEqual(false, err, []string{"web"}, podNames(pods))
+		cache.Stop()
+
+		pods, err = cache.RunningPods()
+		t.CheckErrorAndDeepEqual(false, err, []string{"web"}, podNames(pods))
+
+		watchers[len(watchers)-1].Add(labelledPod("app", "ns2", run, v1.PodRunning))
+		var names []string
+		for i := 0; i < 100; i++ {
+			pods, err = cache.RunningPods()
+			t.CheckNoError(err)
+			if names = podNames(pods); len(names) == 2 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.CheckDeepEqual([]string{"app", "web"}, names)
+	})
+}
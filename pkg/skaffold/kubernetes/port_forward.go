@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwardConnection is a connection to the Kubernetes API that forwards a port of a pod.
+// Like `kubectl port-forward`, it carries all the forwarded connections to that pod.
+type PortForwardConnection interface {
+	// Open opens a new forwarded connection.
+	Open() (io.ReadWriteCloser, error)
+
+	// Closed is closed once the connection to the Kubernetes API is lost.
+	Closed() <-chan bool
+
+	// Close closes the connection and all its forwarded connections.
+	Close() error
+}
+
+// DialPortForward connects to the Kubernetes API to forward a port of a pod, without the kubectl binary.
+// It's here for testing.
+var DialPortForward = dialPortForwardForContext
+
+func dialPortForwardForContext(kubeContext, namespace, pod string, port int32) (PortForwardConnection, error) {
+	config, err := getClientConfig(kubeContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting client config")
+	}
+
+	return dialPortForward(config, namespace, pod, port)
+}
+
+// dialPortForward upgrades a port-forward request to a SPDY connection.
+func dialPortForward(config *restclient.Config, namespace, pod string, port int32) (PortForwardConnection, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting kubernetes client")
+	}
+
+	req := client.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(pod).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating round tripper")
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "forwarding port %d of pod %s", port, pod)
+	}
+
+	return &portForwardConnection{
+		conn: conn,
+		pod:  pod,
+		port: port,
+	}, nil
+}
+
+type portForwardConnection struct {
+	conn httpstream.Connection
+	pod  string
+	port int32
+
+	lock      sync.Mutex
+	requestID int
+}
+
+// Open opens the error and data streams of a new forwarded connection.
+// Each forwarded connection has its own request ID.
+func (c *portForwardConnection) Open() (io.ReadWriteCloser, error) {
+	c.lock.Lock()
+	requestID := c.requestID
+	c.requestID++
+	c.lock.Unlock()
+
+	stream, err := openPortForwardStreams(c.conn, c.port, requestID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "forwarding port %d of pod %s", c.port, c.pod)
+	}
+	return stream, nil
+}
+
+func (c *portForwardConnection) Closed() <-chan bool {
+	return c.conn.CloseChan()
+}
+
+func (c *portForwardConnection) Close() error {
+	return c.conn.Close()
+}
+
+func openPortForwardStreams(conn httpstream.Connection, port int32, requestID int) (*portForwardStream, error) {
+	headers := http.Header{}
+	headers.Set(v1.StreamType, v1.StreamTypeError)
+	headers.Set(v1.PortHeader, strconv.Itoa(int(port)))
+	headers.Set(v1.PortForwardRequestIDHeader, strconv.Itoa(requestID))
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating error stream")
+	}
+	// Nothing is ever written to the error stream.
+	errorStream.Close()
+
+	headers.Set(v1.StreamType, v1.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		errorStream.Reset()
+		return nil, errors.Wrap(err, "creating data stream")
+	}
+
+	failures := make(chan error, 1)
+	go func() {
+		message, err := ioutil.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			failures <- errors.Wrap(err, "reading error stream")
+		case len(message) > 0:
+			failures <- fmt.Errorf("port forward failed: %s", message)
+		}
+		close(failures)
+	}()
+
+	return &portForwardStream{
+		error:    errorStream,
+		data:     dataStream,
+		failures: failures,
+	}, nil
+}
+
+// portForwardStream carries the data of a forwarded connection.
+type portForwardStream struct {
+	error    httpstream.Stream
+	data     httpstream.Stream
+	failures chan error
+}
+
+// Read reads from the data stream. Once the data stream is over,
+// it reports the error that the API server sent, if any.
+func (s *portForwardStream) Read(p []byte) (int, error) {
+	n, err := s.data.Read(p)
+	if err != nil {
+		if failure := <-s.failures; failure != nil {
+			return n, failure
+		}
+	}
+	return n, err
+}
+
+func (s *portForwardStream) Write(p []byte) (int, error) {
+	return s.data.Write(p)
+}
+
+// Close closes the streams of this forwarded connection.
+// The connection to the Kubernetes API stays open for other forwarded connections.
+func (s *portForwardStream) Close() error {
+	s.error.Reset()
+	return s.data.Reset()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+)
+
+// fakePortForwardServer accepts SPDY port-forward requests on port 8080. For each forwarded
+// connection, it echoes the first five bytes it receives and sends the given error, if any,
+// on the error stream. It reports the request ID of each forwarded connection.
+func fakePortForwardServer(t *testutil.T, failure string, requests chan<- *http.Request, requestIDs chan<- string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+
+		if _, err := httpstream.Handshake(r, w, []string{portforward.PortForwardProtocolV1Name}); err != nil {
+			return
+		}
+
+		streams := make(chan httpstream.Stream)
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(stream httpstream.Stream, _ <-chan struct{}) error {
+			streams <- stream
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+
+		errorStreams := map[string]httpstream.Stream{}
+		dataStreams := map[string]httpstream.Stream{}
+		for {
+			var stream httpstream.Stream
+			select {
+			case stream = <-streams:
+			case <-conn.CloseChan():
+				return
+			}
+
+			t.CheckDeepEqual("8080", stream.Headers().Get(v1.PortHeader))
+			requestID := stream.Headers().Get(v1.PortForwardRequestIDHeader)
+			if stream.Headers().Get(v1.StreamType) == v1.StreamTypeError {
+				errorStreams[requestID] = stream
+			} else {
+				dataStreams[requestID] = stream
+			}
+
+			errorStream, data := errorStreams[requestID], dataStreams[requestID]
+			if errorStream == nil || data == nil {
+				continue
+			}
+			requestIDs <- requestID
+
+			go func() {
+				if failure != "" {
+					errorStream.Write([]byte(failure))
+				} else {
+					message := make([]byte, 5)
+					_, err := io.ReadFull(data, message)
+					t.CheckNoError(err)
+					data.Write(message)
+				}
+				errorStream.Close()
+				data.Close()
+			}()
+		}
+	}))
+}
+
+func TestPortForward(t *testing.T) {
+	tests := []struct {
+		description string
+		failure     string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "echo",
+			expected:    "hello",
+		},
+		{
+			description: "failure",
+			failure:     "connection refused",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			requests := make(chan *http.Request, 1)
+			requestIDs := make(chan string, 2)
+			server := fakePortForwardServer(t, test.failure, requests, requestIDs)
+			defer server.Close()
+
+			conn, err := dialPortForward(&restclient.Config{Host: server.URL}, "ns", "pod", 8080)
+			t.CheckNoError(err)
+			defer conn.Close()
+
+			// Both forwarded connections go through the same connection to the API.
+			for i := 0; i < 2; i++ {
+				stream, err := conn.Open()
+				t.CheckNoError(err)
+
+				if test.failure == "" {
+					_, err = stream.Write([]byte("hello"))
+					t.CheckNoError(err)
+				}
+				received, err := ioutil.ReadAll(stream)
+				stream.Close()
+
+				t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, string(received))
+				t.CheckDeepEqual(strconv.Itoa(i), <-requestIDs)
+			}
+
+			request := <-requests
+			t.CheckDeepEqual(http.MethodPost, request.Method)
+			t.CheckDeepEqual("/api/v1/namespaces/ns/pods/pod/portforward", request.URL.Path)
+			t.CheckDeepEqual(0, len(requests))
+		})
+	}
+}
@@ -33,6 +33,12 @@ var (
 	forwardingTimeoutTime = time.Minute
 )
 
+// EntryForwarder forwards the port of a single entry.
+type EntryForwarder interface {
+	Forward(parentCtx context.Context, pfe *portForwardEntry) error
+	Terminate(p *portForwardEntry)
+}
+
 // EntryManager handles forwarding entries and keeping track of
 // forwarded ports and resources.
 type EntryManager struct {
@@ -53,7 +59,7 @@ func NewEntryManager(out io.Writer) EntryManager {
 		output:             out,
 		forwardedPorts:     &sync.Map{},
 		forwardedResources: &sync.Map{},
-		EntryForwarder:     &KubectlForwarder{},
+		EntryForwarder:     &NativeForwarder{output: out},
 	}
 }
 
@@ -69,11 +75,11 @@ func (b *EntryManager) forwardPortForwardEntry(ctx context.Context, entry *portF
 	if err != nil {
 		return err
 	}
-	event.PortForwarded(entry.localPort, entry.resource.Port, entry.podName, entry.containerName, entry.resource.Namespace, entry.portName, string(entry.resource.Type), entry.resource.Name)
+	event.PortForwarded(entry.localPort, entry.resource.Port, entry.podName, entry.containerName, entry.resource.Namespace, entry.portName, string(entry.resource.Type), entry.resource.Name, entry.kubeContext)
 	return nil
 }
 
-// Stop terminates all kubectl port-forward commands.
+// Stop terminates all port forwards.
 func (b *EntryManager) Stop() {
 	b.forwardedResources.Range(func(key, value interface{}) bool {
 		entry := value.(*portForwardEntry)
@@ -18,8 +18,8 @@ package portforward
 
 import (
 	"io/ioutil"
+	"reflect"
 	"sync"
-	"sync/atomic"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
@@ -34,10 +34,24 @@ func TestNewEntryManager(t *testing.T) {
 		output:             out,
 		forwardedPorts:     &sync.Map{},
 		forwardedResources: &sync.Map{},
-		EntryForwarder:     &KubectlForwarder{},
+		EntryForwarder:     &NativeForwarder{output: out},
 	}
 	actual := NewEntryManager(out)
-	testutil.CheckDeepEqual(t, expected, actual, cmp.AllowUnexported(EntryManager{}, sync.Map{}, sync.Mutex{}, atomic.Value{}))
+	testutil.CheckDeepEqual(t, expected, actual, cmp.AllowUnexported(EntryManager{}, NativeForwarder{}), cmp.Comparer(sameSyncMap))
+}
+
+// sameSyncMap compares the contents of sync.Maps, whose fields depend on the version of Go.
+func sameSyncMap(x, y *sync.Map) bool {
+	return reflect.DeepEqual(syncMapContents(x), syncMapContents(y))
+}
+
+func syncMapContents(m *sync.Map) map[interface{}]interface{} {
+	contents := map[interface{}]interface{}{}
+	m.Range(func(key, value interface{}) bool {
+		contents[key] = value
+		return true
+	})
+	return contents
 }
 
 func TestStop(t *testing.T) {
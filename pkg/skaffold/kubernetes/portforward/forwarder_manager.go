@@ -22,6 +22,7 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 )
 
 // Forwarder is an interface that can modify and manage port-forward processes
@@ -42,22 +43,28 @@ var (
 	emptyForwarderManager = &ForwarderManager{}
 )
 
-// NewForwarderManager returns a new port manager which handles starting and stopping port forwarding
-func NewForwarderManager(out io.Writer, podSelector kubernetes.PodSelector, namespaces []string, label string, opts config.PortForwardOptions) *ForwarderManager {
+// NewForwarderManager returns a new port manager which handles starting and stopping port forwarding.
+// namespaces lists, for each kubectl context, the namespaces to watch for pods.
+// userDefined lists, for each kubectl context, the resources to forward from the configuration.
+func NewForwarderManager(out io.Writer, podSelector kubernetes.PodSelector, namespaces map[string][]string, label string, opts config.PortForwardOptions, userDefined map[string][]*latest.PortForwardResource) *ForwarderManager {
 	if !opts.Enabled {
 		return emptyForwarderManager
 	}
 
+	// A single entry manager makes sure local ports are not reused across contexts.
 	em := NewEntryManager(out)
 
 	ForwarderManager := &ForwarderManager{
-		output:     out,
-		Forwarders: []Forwarder{NewResourceForwarder(em, label)},
+		output: out,
 	}
 
-	if opts.ForwardPods {
-		f := NewWatchingPodForwarder(em, podSelector, namespaces)
-		ForwarderManager.Forwarders = append(ForwarderManager.Forwarders, f)
+	for kubeContext, ns := range namespaces {
+		ForwarderManager.Forwarders = append(ForwarderManager.Forwarders, NewResourceForwarder(em, kubeContext, label, userDefined[kubeContext]))
+
+		if opts.ForwardPods {
+			f := NewWatchingPodForwarder(em, kubeContext, podSelector, ns)
+			ForwarderManager.Forwarders = append(ForwarderManager.Forwarders, f)
+		}
 	}
 
 	return ForwarderManager
@@ -0,0 +1,374 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+var (
+	// For testing
+	dialPortForward   = kubernetes.DialPortForward
+	resolveForwarded  = resolvePod
+	reconnectInterval = time.Second
+)
+
+// NativeForwarder forwards ports through the Kubernetes API, without the kubectl binary.
+// It keeps track of the pod behind each forwarded resource and, when that pod goes away,
+// transparently reconnects to a new ready pod on the same local port.
+type NativeForwarder struct {
+	output io.Writer
+}
+
+// localAddress returns the local address a resource is forwarded on.
+func localAddress(resource latest.PortForwardResource) string {
+	if resource.Address == "" {
+		return util.Loopback
+	}
+	return resource.Address
+}
+
+// Forward listens on the local port of an entry and forwards each connection to a ready pod of its resource.
+// It returns an error if no pod is ready yet or if the local port can't be listened on.
+func (f *NativeForwarder) Forward(parentCtx context.Context, pfe *portForwardEntry) error {
+	logrus.Debugf("Port forwarding %v", pfe)
+
+	pod, err := resolveForwarded(pfe.kubeContext, pfe.resource)
+	if err != nil {
+		return errors.Wrapf(err, "port forwarding %s/%s", pfe.resource.Type, pfe.resource.Name)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(localAddress(pfe.resource), strconv.Itoa(int(pfe.localPort))))
+	if err != nil {
+		return errors.Wrapf(err, "port forwarding %s/%s to local port %d", pfe.resource.Type, pfe.resource.Name, pfe.localPort)
+	}
+
+	ctx, cancel := context.WithCancel(parentCtx)
+	// Closing the listener right away frees the local port for the next generation of a pod.
+	pfe.cancel = func() {
+		cancel()
+		listener.Close()
+	}
+
+	t := newTunnel(pfe, f.output, pod)
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+		t.closeConnection()
+	}()
+	go t.accept(ctx, listener)
+	go t.follow(ctx)
+
+	return nil
+}
+
+// Terminate stops forwarding an entry and closes its local port.
+func (*NativeForwarder) Terminate(p *portForwardEntry) {
+	logrus.Debugf("Terminating port-forward %v", p)
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// tunnel forwards the connections on a local port to the current pod of a resource.
+type tunnel struct {
+	entry  *portForwardEntry
+	output io.Writer
+
+	lock sync.Mutex
+	// pod is nil while the pod is lost. Connections then wait for the available channel to be closed.
+	pod       *forwardedPod
+	available chan struct{}
+	// conn carries all the forwarded connections to the current pod. It's dialed on the first connection.
+	conn kubernetes.PortForwardConnection
+}
+
+func newTunnel(entry *portForwardEntry, out io.Writer, pod *forwardedPod) *tunnel {
+	t := &tunnel{
+		entry:  entry,
+		output: out,
+	}
+	t.setPod(pod)
+	return t
+}
+
+func (t *tunnel) setPod(pod *forwardedPod) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.conn != nil {
+		t.conn.Close()
+		t.conn = nil
+	}
+
+	t.pod = pod
+	if pod != nil {
+		t.entry.podName = pod.name
+	}
+	if pod == nil {
+		t.available = make(chan struct{})
+	} else if t.available == nil {
+		t.available = make(chan struct{})
+		close(t.available)
+	} else {
+		close(t.available)
+	}
+}
+
+// currentPod returns the pod connections go to, waiting for a new one if it was lost.
+func (t *tunnel) currentPod(ctx context.Context) (*forwardedPod, error) {
+	for {
+		t.lock.Lock()
+		pod, available := t.pod, t.available
+		t.lock.Unlock()
+
+		if pod != nil {
+			return pod, nil
+		}
+
+		select {
+		case <-available:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// open opens a forwarded connection to the current pod.
+func (t *tunnel) open(ctx context.Context) (io.ReadWriteCloser, error) {
+	for {
+		pod, err := t.currentPod(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		t.lock.Lock()
+		if t.pod != pod {
+			// The pod changed in the meantime.
+			t.lock.Unlock()
+			continue
+		}
+		conn, err := t.connection(pod)
+		t.lock.Unlock()
+		if err != nil {
+			return nil, errors.Wrapf(err, "forwarding connection to pod %s", pod.name)
+		}
+
+		return conn.Open()
+	}
+}
+
+// connection returns the connection to a pod, dialing it again if it was lost.
+// It must be called with the lock held.
+func (t *tunnel) connection(pod *forwardedPod) (kubernetes.PortForwardConnection, error) {
+	if t.conn != nil {
+		select {
+		case <-t.conn.Closed():
+			t.conn = nil
+		default:
+			return t.conn, nil
+		}
+	}
+
+	conn, err := dialPortForward(t.entry.kubeContext, resourceNamespace(t.entry.resource), pod.name, pod.port)
+	if err != nil {
+		return nil, err
+	}
+	t.conn = conn
+	return conn, nil
+}
+
+func (t *tunnel) closeConnection() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.conn != nil {
+		t.conn.Close()
+		t.conn = nil
+	}
+}
+
+func (t *tunnel) accept(ctx context.Context, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				logrus.Warnf("Port forwarding %v stopped: %v", t.entry, err)
+			}
+			return
+		}
+
+		go t.serve(ctx, conn)
+	}
+}
+
+// serve copies data both ways between a local connection and the pod, until either side closes.
+func (t *tunnel) serve(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	stream, err := t.open(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logrus.Warnf("Unable to forward connection: %v", err)
+		}
+		return
+	}
+	defer stream.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(stream, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, stream)
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// follow waits for the pod to go away and then reconnects to a new ready pod, until the context is cancelled.
+func (t *tunnel) follow(ctx context.Context) {
+	client, err := kubernetes.Client(t.entry.kubeContext)
+	if err != nil {
+		logrus.Warnf("Unable to follow the pods of %v: %v", t.entry, err)
+		return
+	}
+
+	namespace := resourceNamespace(t.entry.resource)
+	for {
+		t.lock.Lock()
+		pod := t.pod
+		t.lock.Unlock()
+
+		waitForPodLoss(ctx, client, namespace, pod)
+		if ctx.Err() != nil {
+			return
+		}
+
+		t.setPod(nil)
+		color.Yellow.Fprintf(t.output, "Lost pod %s forwarded to local port %d, waiting for a new pod.\n", pod.name, t.entry.localPort)
+		event.PortForwardLost(t.entry.localPort, pod.port, pod.name, t.entry.containerName, t.entry.resource.Namespace, t.entry.portName, string(t.entry.resource.Type), t.entry.resource.Name, t.entry.kubeContext)
+
+		var newPod *forwardedPod
+		err := wait.PollImmediateUntil(reconnectInterval, func() (bool, error) {
+			p, err := resolveForwarded(t.entry.kubeContext, t.entry.resource)
+			if err != nil {
+				logrus.Debugf("Waiting for a new pod for %v: %v", t.entry, err)
+				return false, nil
+			}
+			newPod = p
+			return true, nil
+		}, ctx.Done())
+		if err != nil {
+			return
+		}
+
+		t.setPod(newPod)
+		color.Default.Fprintf(t.output, "Forwarding pod %s to local port %d again.\n", newPod.name, t.entry.localPort)
+		event.PortForwardReestablished(t.entry.localPort, newPod.port, newPod.name, t.entry.containerName, t.entry.resource.Namespace, t.entry.portName, string(t.entry.resource.Type), t.entry.resource.Name, t.entry.kubeContext)
+	}
+}
+
+// waitForPodLoss blocks until a pod is deleted or stops being ready, or until the context is cancelled.
+func waitForPodLoss(ctx context.Context, client k8s.Interface, namespace string, pod *forwardedPod) {
+	resourceVersion := pod.resourceVersion
+	for {
+		lost, err := watchPod(ctx, client, namespace, pod.name, resourceVersion)
+		if lost || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logrus.Debugf("Unable to watch pod %s: %v", pod.name, err)
+			select {
+			case <-time.After(reconnectInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		// The watch ended: check the pod again before watching it from its current version.
+		current, err := client.CoreV1().Pods(namespace).Get(pod.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return
+		}
+		if err != nil {
+			logrus.Debugf("Unable to get pod %s: %v", pod.name, err)
+			continue
+		}
+		if !isReady(current) {
+			return
+		}
+		resourceVersion = current.ResourceVersion
+	}
+}
+
+// watchPod tells if a pod was deleted or stopped being ready before the watch ended.
+func watchPod(ctx context.Context, client k8s.Interface, namespace, name, resourceVersion string) (bool, error) {
+	w, err := client.CoreV1().Pods(namespace).Watch(metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: resourceVersion,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case evt, ok := <-w.ResultChan():
+			if !ok {
+				return false, nil
+			}
+
+			pod, ok := evt.Object.(*v1.Pod)
+			if !ok || pod.Name != name {
+				continue
+			}
+			if evt.Type == watch.Deleted || !isReady(pod) {
+				return true, nil
+			}
+		}
+	}
+}
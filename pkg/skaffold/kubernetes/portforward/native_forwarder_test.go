@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	fake_testing "k8s.io/client-go/testing"
+)
+
+// fakeDialPortForward connects to a fake pod and counts the connections dialed to each pod.
+func fakeDialPortForward(dials map[string]int) func(string, string, string, int32) (kubernetes.PortForwardConnection, error) {
+	var lock sync.Mutex
+	return func(_, _, pod string, port int32) (kubernetes.PortForwardConnection, error) {
+		lock.Lock()
+		dials[pod]++
+		lock.Unlock()
+
+		return &fakePortForwardConnection{pod: pod, port: port, closed: make(chan bool)}, nil
+	}
+}
+
+// fakePortForwardConnection forwards connections to a fake pod that writes its name and port,
+// then closes the connection.
+type fakePortForwardConnection struct {
+	pod    string
+	port   int32
+	closed chan bool
+	once   sync.Once
+}
+
+func (c *fakePortForwardConnection) Open() (io.ReadWriteCloser, error) {
+	local, remote := net.Pipe()
+	go func() {
+		fmt.Fprintf(remote, "%s:%d", c.pod, c.port)
+		remote.Close()
+	}()
+	return local, nil
+}
+
+func (c *fakePortForwardConnection) Closed() <-chan bool {
+	return c.closed
+}
+
+func (c *fakePortForwardConnection) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func freeLocalPort(t *testutil.T) int32 {
+	l, err := net.Listen("tcp", net.JoinHostPort(util.Loopback, "0"))
+	t.CheckNoError(err)
+	defer l.Close()
+	return int32(l.Addr().(*net.TCPAddr).Port)
+}
+
+func readLocalPort(port int32) (string, error) {
+	conn, err := net.Dial("tcp", net.JoinHostPort(util.Loopback, strconv.Itoa(int(port))))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	content, err := ioutil.ReadAll(conn)
+	return string(content), err
+}
+
+func TestNativeForwarderReconnects(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		event.InitializeState(&runcontext.RunContext{Cfg: &latest.Pipeline{Build: latest.BuildConfig{}}})
+
+		app := map[string]string{"app": "web"}
+		client := fake.NewSimpleClientset(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}, Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: app}}},
+			readyPodObject("web-1", app, time.Now()),
+		)
+		watchers := make(chan *watch.RaceFreeFakeWatcher, 10)
+		client.PrependWatchReactor("pods", func(fake_testing.Action) (bool, watch.Interface, error) {
+			w := watch.NewRaceFreeFake()
+			watchers <- w
+			return true, w, nil
+		})
+		t.Override(&kubernetes.Client, func(string) (k8s.Interface, error) { return client, nil })
+		dials := map[string]int{}
+		t.Override(&dialPortForward, fakeDialPortForward(dials))
+		t.Override(&reconnectInterval, 10*time.Millisecond)
+
+		port := freeLocalPort(t)
+		entry := &portForwardEntry{
+			resource:  latest.PortForwardResource{Type: "deployment", Name: "web", Namespace: "ns", Port: 8080},
+			localPort: port,
+		}
+		forwarder := &NativeForwarder{output: ioutil.Discard}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := forwarder.Forward(ctx, entry)
+		t.CheckNoError(err)
+		t.CheckDeepEqual("web-1", entry.podName)
+
+		// Connections to the same pod share a single connection to the API.
+		for i := 0; i < 2; i++ {
+			content, err := readLocalPort(port)
+			t.CheckErrorAndDeepEqual(false, err, "web-1:8080", content)
+		}
+		t.CheckDeepEqual(1, dials["web-1"])
+
+		// The deployment replaces its pod.
+		watcher := <-watchers
+		t.CheckNoError(client.CoreV1().Pods("ns").Delete("web-1", nil))
+		watcher.Delete(readyPodObject("web-1", app, time.Now()))
+		_, err = client.CoreV1().Pods("ns").Create(readyPodObject("web-2", app, time.Now()))
+		t.CheckNoError(err)
+
+		// The same local port now goes to the new pod.
+		var content string
+		for i := 0; i < 100 && content != "web-2:8080"; i++ {
+			time.Sleep(10 * time.Millisecond)
+			content, err = readLocalPort(port)
+			t.CheckNoError(err)
+		}
+		t.CheckDeepEqual("web-2:8080", content)
+		t.CheckDeepEqual("web-2", entry.podName)
+
+		// Terminating frees the local port.
+		forwarder.Terminate(entry)
+		_, err = readLocalPort(port)
+		t.CheckError(true, err)
+	})
+}
+
+func TestNativeForwarderNoReadyPod(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := fake.NewSimpleClientset(unreadyPodObject("web-1", nil, time.Now()))
+		t.Override(&kubernetes.Client, func(string) (k8s.Interface, error) { return client, nil })
+
+		port := freeLocalPort(t)
+		entry := &portForwardEntry{
+			resource:  latest.PortForwardResource{Type: "pod", Name: "web-1", Namespace: "ns", Port: 8080},
+			localPort: port,
+		}
+
+		err := (&NativeForwarder{output: ioutil.Discard}).Forward(context.Background(), entry)
+		t.CheckError(true, err)
+
+		// The local port is not taken.
+		l, err := net.Listen("tcp", net.JoinHostPort(util.Loopback, strconv.Itoa(int(port))))
+		t.CheckNoError(err)
+		l.Close()
+	})
+}
@@ -39,14 +39,16 @@ var (
 // container ports within those pods. It also tracks and manages the port-forward connections.
 type WatchingPodForwarder struct {
 	EntryManager
+	kubeContext string
 	namespaces  []string
 	podSelector kubernetes.PodSelector
 }
 
 // NewWatchingPodForwarder returns a struct that tracks and port-forwards pods as they are created and modified
-func NewWatchingPodForwarder(em EntryManager, podSelector kubernetes.PodSelector, namespaces []string) *WatchingPodForwarder {
+func NewWatchingPodForwarder(em EntryManager, kubeContext string, podSelector kubernetes.PodSelector, namespaces []string) *WatchingPodForwarder {
 	return &WatchingPodForwarder{
 		EntryManager: em,
+		kubeContext:  kubeContext,
 		podSelector:  podSelector,
 		namespaces:   namespaces,
 	}
@@ -54,7 +56,7 @@ func NewWatchingPodForwarder(em EntryManager, podSelector kubernetes.PodSelector
 
 func (p *WatchingPodForwarder) Start(ctx context.Context) error {
 	aggregate := make(chan watch.Event)
-	stopWatchers, err := aggregatePodWatcher(p.namespaces, aggregate)
+	stopWatchers, err := aggregatePodWatcher(p.kubeContext, p.namespaces, aggregate)
 	if err != nil {
 		stopWatchers()
 		return errors.Wrap(err, "initializing pod watcher")
@@ -140,6 +142,7 @@ func (p *WatchingPodForwarder) podForwardingEntry(resourceVersion, containerName
 	}
 	entry := &portForwardEntry{
 		resource:               resource,
+		kubeContext:            p.kubeContext,
 		resourceVersion:        rv,
 		podName:                resource.Name,
 		containerName:          containerName,
@@ -157,7 +160,7 @@ func (p *WatchingPodForwarder) podForwardingEntry(resourceVersion, containerName
 	}
 
 	// retrieve an open port on the host
-	entry.localPort = int32(retrieveAvailablePort(int(resource.Port), p.forwardedPorts))
+	entry.localPort = int32(retrieveAvailablePort(localAddress(resource), int(resource.Port), p.forwardedPorts))
 
 	return entry, nil
 }
@@ -439,7 +439,7 @@ func TestAutomaticPortForwardPod(t *testing.T) {
 				forwardedPorts:     &sync.Map{},
 				forwardedResources: &sync.Map{},
 			}
-			p := NewWatchingPodForwarder(entryManager, kubernetes.NewImageList(), nil)
+			p := NewWatchingPodForwarder(entryManager, "", kubernetes.NewImageList(), nil)
 			if test.forwarder == nil {
 				test.forwarder = newTestForwarder(nil)
 			}
@@ -497,7 +497,7 @@ func TestStartPodForwarder(t *testing.T) {
 			client.PrependWatchReactor("*", testutil.SetupFakeWatcher(fakeWatcher))
 
 			waitForWatcher := make(chan bool)
-			testutil.Override(t, &aggregatePodWatcher, func(_ []string, aggregate chan<- watch.Event) (func(), error) {
+			testutil.Override(t, &aggregatePodWatcher, func(_ string, _ []string, aggregate chan<- watch.Event) (func(), error) {
 				go func() {
 					waitForWatcher <- true
 					for msg := range fakeWatcher.ResultChan() {
@@ -510,7 +510,7 @@ func TestStartPodForwarder(t *testing.T) {
 			imageList := kubernetes.NewImageList()
 			imageList.Add("image")
 
-			p := NewWatchingPodForwarder(NewEntryManager(ioutil.Discard), imageList, nil)
+			p := NewWatchingPodForwarder(NewEntryManager(ioutil.Discard), "", imageList, nil)
 			fakeForwarder := newTestForwarder(nil)
 			p.EntryForwarder = fakeForwarder
 			p.Start(context.Background())
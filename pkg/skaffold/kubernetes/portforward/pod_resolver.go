@@ -0,0 +1,259 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// forwardedPod is the pod, and the port on that pod, that a resource is forwarded to.
+type forwardedPod struct {
+	name string
+	port int32
+
+	resourceVersion string
+}
+
+// resolvePod finds a ready pod behind a resource, the same way kubectl port-forward does.
+// For a service, the port is translated into the target port of that pod.
+func resolvePod(kubeContext string, resource latest.PortForwardResource) (*forwardedPod, error) {
+	client, err := kubernetes.Client(kubeContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting kubernetes client")
+	}
+
+	namespace := resourceNamespace(resource)
+
+	var (
+		pods     []v1.Pod
+		selector *metav1.LabelSelector
+	)
+
+	switch strings.ToLower(string(resource.Type)) {
+	case "pod":
+		pod, err := client.CoreV1().Pods(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting pod %s", resource.Name)
+		}
+		pods = []v1.Pod{*pod}
+	case "service":
+		service, err := client.CoreV1().Services(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting service %s", resource.Name)
+		}
+		if len(service.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("service %s has no selector", resource.Name)
+		}
+		pod, err := readyPod(client, namespace, &metav1.LabelSelector{MatchLabels: service.Spec.Selector})
+		if err != nil {
+			return nil, errors.Wrapf(err, "selecting pods of service %s", resource.Name)
+		}
+		port, err := targetPort(service, pod, resource.Port)
+		if err != nil {
+			return nil, err
+		}
+		return &forwardedPod{name: pod.Name, port: port, resourceVersion: pod.ResourceVersion}, nil
+	case "deployment":
+		deployment, err := client.AppsV1().Deployments(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting deployment %s", resource.Name)
+		}
+		selector = deployment.Spec.Selector
+	case "replicaset":
+		replicaSet, err := client.AppsV1().ReplicaSets(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting replicaset %s", resource.Name)
+		}
+		selector = replicaSet.Spec.Selector
+	case "statefulset":
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting statefulset %s", resource.Name)
+		}
+		selector = statefulSet.Spec.Selector
+	case "daemonset":
+		daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting daemonset %s", resource.Name)
+		}
+		selector = daemonSet.Spec.Selector
+	case "replicationcontroller":
+		controller, err := client.CoreV1().ReplicationControllers(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting replicationcontroller %s", resource.Name)
+		}
+		selector = &metav1.LabelSelector{MatchLabels: controller.Spec.Selector}
+	case "job":
+		job, err := client.BatchV1().Jobs(namespace).Get(resource.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting job %s", resource.Name)
+		}
+		selector = job.Spec.Selector
+	case "cronjob":
+		pods, err = cronJobPods(client, namespace, resource.Name)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resource.Type)
+	}
+
+	if selector != nil {
+		pod, err := readyPod(client, namespace, selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "selecting pods of %s %s", resource.Type, resource.Name)
+		}
+		pods = []v1.Pod{*pod}
+	}
+
+	pod := oldestReadyPod(pods)
+	if pod == nil {
+		return nil, fmt.Errorf("no ready pod for %s %s", resource.Type, resource.Name)
+	}
+	return &forwardedPod{name: pod.Name, port: resource.Port, resourceVersion: pod.ResourceVersion}, nil
+}
+
+// resourceNamespace returns the namespace of a resource, which kubectl port-forward defaults to `default`.
+func resourceNamespace(resource latest.PortForwardResource) string {
+	if resource.Namespace == "" {
+		return "default"
+	}
+	return resource.Namespace
+}
+
+// readyPod returns a ready pod matching a label selector.
+func readyPod(client k8s.Interface, namespace string, selector *metav1.LabelSelector) (*v1.Pod, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing selector")
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: s.String()})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+
+	pod := oldestReadyPod(pods.Items)
+	if pod == nil {
+		return nil, errors.New("no ready pod")
+	}
+	return pod, nil
+}
+
+// cronJobPods lists the pods of the jobs started by a cron job.
+func cronJobPods(client k8s.Interface, namespace, name string) ([]v1.Pod, error) {
+	jobs, err := client.BatchV1().Jobs(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing jobs")
+	}
+
+	var pods []v1.Pod
+	for _, job := range jobs.Items {
+		if !ownedBy(job.OwnerReferences, "CronJob", name) {
+			continue
+		}
+
+		s, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing selector of job %s", job.Name)
+		}
+		list, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: s.String()})
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing pods of job %s", job.Name)
+		}
+		pods = append(pods, list.Items...)
+	}
+	return pods, nil
+}
+
+func ownedBy(owners []metav1.OwnerReference, kind, name string) bool {
+	for _, owner := range owners {
+		if owner.Kind == kind && owner.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// oldestReadyPod picks the ready pod that has been running for the longest time.
+func oldestReadyPod(pods []v1.Pod) *v1.Pod {
+	var ready []v1.Pod
+	for _, pod := range pods {
+		if isReady(&pod) {
+			ready = append(ready, pod)
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].CreationTimestamp.Equal(&ready[j].CreationTimestamp) {
+			return ready[i].Name < ready[j].Name
+		}
+		return ready[i].CreationTimestamp.Before(&ready[j].CreationTimestamp)
+	})
+	return &ready[0]
+}
+
+func isReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// targetPort translates a service port into the port of one of its pods.
+func targetPort(service *v1.Service, pod *v1.Pod, port int32) (int32, error) {
+	for _, p := range service.Spec.Ports {
+		if p.Port != port {
+			continue
+		}
+
+		switch {
+		case p.TargetPort.Type == intstr.String:
+			for _, c := range pod.Spec.Containers {
+				for _, cp := range c.Ports {
+					if cp.Name == p.TargetPort.StrVal {
+						return cp.ContainerPort, nil
+					}
+				}
+			}
+			return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, p.TargetPort.StrVal)
+		case p.TargetPort.IntVal != 0:
+			return p.TargetPort.IntVal, nil
+		default:
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("service %s has no port %d", service.Name, port)
+}
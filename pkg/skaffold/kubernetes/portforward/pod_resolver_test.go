@@ -0,0 +1,178 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readyPodObject(name string, labels map[string]string, created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "ns",
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:  "web",
+				Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
+
+func unreadyPodObject(name string, labels map[string]string, created time.Time) *v1.Pod {
+	pod := readyPodObject(name, labels, created)
+	pod.Status.Conditions[0].Status = v1.ConditionFalse
+	return pod
+}
+
+func TestResolvePod(t *testing.T) {
+	app := map[string]string{"app": "web"}
+	selector := &metav1.LabelSelector{MatchLabels: app}
+	now := time.Now()
+
+	tests := []struct {
+		description  string
+		resource     latest.PortForwardResource
+		objects      []runtime.Object
+		expectedPod  string
+		expectedPort int32
+		shouldErr    bool
+	}{
+		{
+			description:  "ready pod",
+			resource:     latest.PortForwardResource{Type: "pod", Name: "web-1", Namespace: "ns", Port: 8080},
+			objects:      []runtime.Object{readyPodObject("web-1", app, now)},
+			expectedPod:  "web-1",
+			expectedPort: 8080,
+		},
+		{
+			description: "pod not ready",
+			resource:    latest.PortForwardResource{Type: "pod", Name: "web-1", Namespace: "ns", Port: 8080},
+			objects:     []runtime.Object{unreadyPodObject("web-1", app, now)},
+			shouldErr:   true,
+		},
+		{
+			description: "deployment picks the oldest ready pod",
+			resource:    latest.PortForwardResource{Type: "Deployment", Name: "web", Namespace: "ns", Port: 8080},
+			objects: []runtime.Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}, Spec: appsv1.DeploymentSpec{Selector: selector}},
+				readyPodObject("web-new", app, now),
+				readyPodObject("web-old", app, now.Add(-time.Hour)),
+				unreadyPodObject("web-oldest", app, now.Add(-2*time.Hour)),
+				readyPodObject("other", nil, now.Add(-3*time.Hour)),
+			},
+			expectedPod:  "web-old",
+			expectedPort: 8080,
+		},
+		{
+			description: "service with named target port",
+			resource:    latest.PortForwardResource{Type: "service", Name: "web", Namespace: "ns", Port: 80},
+			objects: []runtime.Object{
+				&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}, Spec: v1.ServiceSpec{
+					Selector: app,
+					Ports:    []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+				}},
+				readyPodObject("web-1", app, now),
+			},
+			expectedPod:  "web-1",
+			expectedPort: 8080,
+		},
+		{
+			description: "service with numbered target port",
+			resource:    latest.PortForwardResource{Type: "service", Name: "web", Namespace: "ns", Port: 80},
+			objects: []runtime.Object{
+				&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}, Spec: v1.ServiceSpec{
+					Selector: app,
+					Ports:    []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(9000)}},
+				}},
+				readyPodObject("web-1", app, now),
+			},
+			expectedPod:  "web-1",
+			expectedPort: 9000,
+		},
+		{
+			description: "unknown service port",
+			resource:    latest.PortForwardResource{Type: "service", Name: "web", Namespace: "ns", Port: 443},
+			objects: []runtime.Object{
+				&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}, Spec: v1.ServiceSpec{
+					Selector: app,
+					Ports:    []v1.ServicePort{{Port: 80}},
+				}},
+				readyPodObject("web-1", app, now),
+			},
+			shouldErr: true,
+		},
+		{
+			description: "cronjob",
+			resource:    latest.PortForwardResource{Type: "cronjob", Name: "nightly", Namespace: "ns", Port: 8080},
+			objects: []runtime.Object{
+				&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "nightly-1", Namespace: "ns", OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "nightly"}}},
+					Spec:       batchv1.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "nightly-1"}}},
+				},
+				readyPodObject("nightly-1-abcde", map[string]string{"job-name": "nightly-1"}, now),
+			},
+			expectedPod:  "nightly-1-abcde",
+			expectedPort: 8080,
+		},
+		{
+			description: "missing resource",
+			resource:    latest.PortForwardResource{Type: "statefulset", Name: "db", Namespace: "ns", Port: 5432},
+			shouldErr:   true,
+		},
+		{
+			description: "unsupported type",
+			resource:    latest.PortForwardResource{Type: "ingress", Name: "web", Namespace: "ns", Port: 80},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.objects...)
+			t.Override(&kubernetes.Client, func(string) (k8s.Interface, error) { return client, nil })
+
+			pod, err := resolvePod("kubecontext", test.resource)
+
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				t.CheckDeepEqual(test.expectedPod, pod.name)
+				t.CheckDeepEqual(test.expectedPort, pod.port)
+			}
+		})
+	}
+}
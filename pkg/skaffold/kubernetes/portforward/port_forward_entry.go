@@ -26,6 +26,7 @@ import (
 type portForwardEntry struct {
 	resourceVersion        int
 	resource               latest.PortForwardResource
+	kubeContext            string
 	podName                string
 	containerName          string
 	portName               string
@@ -39,10 +40,17 @@ type portForwardEntry struct {
 // if automaticPodForwarding is set, we return a key that doesn't include podName, since we want the key
 // to be the same whenever pods restart
 func (p *portForwardEntry) key() string {
+	var key string
 	if p.automaticPodForwarding {
-		return fmt.Sprintf("%s-%s-%s-%d", p.containerName, p.resource.Namespace, p.portName, p.resource.Port)
+		key = fmt.Sprintf("%s-%s-%s-%d", p.containerName, p.resource.Namespace, p.portName, p.resource.Port)
+	} else {
+		key = fmt.Sprintf("%s-%s-%s-%d", p.resource.Type, p.resource.Name, p.resource.Namespace, p.resource.Port)
 	}
-	return fmt.Sprintf("%s-%s-%s-%d", p.resource.Type, p.resource.Name, p.resource.Namespace, p.resource.Port)
+	if p.kubeContext != "" {
+		// The same resource can be deployed to several kubectl contexts
+		key = fmt.Sprintf("%s-%s", p.kubeContext, key)
+	}
+	return key
 }
 
 // String is a utility function that returns the port forward entry as a user-readable string
@@ -89,6 +89,21 @@ func TestAutomaticPodForwardingKey(t *testing.T) {
 				automaticPodForwarding: true,
 			},
 			expected: "containerName-default-portName-8080",
+		}, {
+			description: "entry for automatically port forwarded pod in a kubectl context",
+			pfe: &portForwardEntry{
+				kubeContext:   "cluster1",
+				containerName: "containerName",
+				portName:      "portName",
+				resource: latest.PortForwardResource{
+					Type:      "pod",
+					Name:      "podName",
+					Namespace: "default",
+					Port:      8080,
+				},
+				automaticPodForwarding: true,
+			},
+			expected: "cluster1-containerName-default-portName-8080",
 		},
 	}
 
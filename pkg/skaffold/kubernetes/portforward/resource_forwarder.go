@@ -18,6 +18,7 @@ package portforward
 
 import (
 	"context"
+	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
@@ -32,7 +33,9 @@ import (
 // services deployed by skaffold.
 type ResourceForwarder struct {
 	EntryManager
-	label string
+	kubeContext          string
+	label                string
+	userDefinedResources []*latest.PortForwardResource
 }
 
 var (
@@ -42,24 +45,47 @@ var (
 )
 
 // NewResourceForwarder returns a struct that tracks and port-forwards pods as they are created and modified
-func NewResourceForwarder(em EntryManager, label string) *ResourceForwarder {
+func NewResourceForwarder(em EntryManager, kubeContext string, label string, userDefinedResources []*latest.PortForwardResource) *ResourceForwarder {
 	return &ResourceForwarder{
-		EntryManager: em,
-		label:        label,
+		EntryManager:         em,
+		kubeContext:          kubeContext,
+		label:                label,
+		userDefinedResources: userDefinedResources,
 	}
 }
 
 // Start gets a list of services deployed by skaffold as []latest.PortForwardResource and
-// forwards them.
+// forwards them, along with the user defined resources.
 func (p *ResourceForwarder) Start(ctx context.Context) error {
-	serviceResources, err := retrieveServices(p.label)
+	serviceResources, err := retrieveServices(p.kubeContext, p.label)
 	if err != nil {
 		return errors.Wrap(err, "retrieving services for automatic port forwarding")
 	}
-	p.portForwardResources(ctx, serviceResources)
+
+	var resources []latest.PortForwardResource
+	for _, r := range p.userDefinedResources {
+		resources = append(resources, *r)
+	}
+	for _, s := range serviceResources {
+		if !p.isUserDefined(s) {
+			resources = append(resources, s)
+		}
+	}
+
+	p.portForwardResources(ctx, resources)
 	return nil
 }
 
+// isUserDefined tells if a service port is already forwarded by a user defined resource.
+func (p *ResourceForwarder) isUserDefined(service latest.PortForwardResource) bool {
+	for _, r := range p.userDefinedResources {
+		if strings.EqualFold(string(r.Type), string(service.Type)) && r.Name == service.Name && r.Namespace == service.Namespace && r.Port == service.Port {
+			return true
+		}
+	}
+	return false
+}
+
 // Port forward each resource individuallly in a goroutine
 func (p *ResourceForwarder) portForwardResources(ctx context.Context, resources []latest.PortForwardResource) {
 	for _, r := range resources {
@@ -82,7 +108,8 @@ func (p *ResourceForwarder) portForwardResource(ctx context.Context, resource la
 func (p *ResourceForwarder) getCurrentEntry(resource latest.PortForwardResource) *portForwardEntry {
 	// determine if we have seen this before
 	entry := &portForwardEntry{
-		resource: resource,
+		resource:    resource,
+		kubeContext: p.kubeContext,
 	}
 	// If we have, return the current entry
 	oldEntry, ok := p.forwardedResources.Load(entry.key())
@@ -93,15 +120,19 @@ func (p *ResourceForwarder) getCurrentEntry(resource latest.PortForwardResource)
 		return entry
 	}
 
-	// retrieve an open port on the host
-	entry.localPort = int32(retrieveAvailablePort(int(resource.Port), p.forwardedPorts))
+	// retrieve an open port on the local address, trying the requested local port first
+	port := resource.LocalPort
+	if port == 0 {
+		port = resource.Port
+	}
+	entry.localPort = int32(retrieveAvailablePort(localAddress(resource), int(port), p.forwardedPorts))
 	return entry
 }
 
-// retrieveServiceResources retrieves all services in the cluster matching the given label
-// as a list of PortForwardResources
-func retrieveServiceResources(label string) ([]latest.PortForwardResource, error) {
-	clientset, err := kubernetes.GetClientset()
+// retrieveServiceResources retrieves all services in the cluster of a kubectl context
+// matching the given label as a list of PortForwardResources
+func retrieveServiceResources(kubeContext string, label string) ([]latest.PortForwardResource, error) {
+	clientset, err := kubernetes.GetClientsetForContext(kubeContext)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting clientset")
 	}
@@ -58,10 +58,10 @@ func newTestForwarder(forwardErr error) *testForwarder {
 	}
 }
 
-func mockRetrieveAvailablePort(taken map[int]struct{}, availablePorts []int) func(int, *sync.Map) int {
+func mockRetrieveAvailablePort(taken map[int]struct{}, availablePorts []int) func(string, int, *sync.Map) int {
 	// Return first available port in ports that isn't taken
 	lock := sync.Mutex{}
-	return func(int, *sync.Map) int {
+	return func(string, int, *sync.Map) int {
 		for _, p := range availablePorts {
 			lock.Lock()
 			if _, ok := taken[p]; ok {
@@ -91,8 +91,16 @@ func TestStart(t *testing.T) {
 		Port:      9000,
 	}
 
+	dep := latest.PortForwardResource{
+		Type:      "deployment",
+		Name:      "dep",
+		Namespace: "default",
+		Port:      8000,
+	}
+
 	tests := []struct {
 		description    string
+		userDefined    []*latest.PortForwardResource
 		resources      []latest.PortForwardResource
 		availablePorts []int
 		expected       map[string]*portForwardEntry
@@ -112,16 +120,36 @@ func TestStart(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:    "forward user defined resources and services",
+			userDefined:    []*latest.PortForwardResource{&dep, &svc1},
+			resources:      []latest.PortForwardResource{svc1, svc2},
+			availablePorts: []int{8000, 8080, 9000},
+			expected: map[string]*portForwardEntry{
+				"deployment-dep-default-8000": {
+					resource:  dep,
+					localPort: 8000,
+				},
+				"service-svc1-default-8080": {
+					resource:  svc1,
+					localPort: 8080,
+				},
+				"service-svc2-default-9000": {
+					resource:  svc2,
+					localPort: 9000,
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
 			event.InitializeState(&runcontext.RunContext{Cfg: &latest.Pipeline{Build: latest.BuildConfig{}}})
 			fakeForwarder := newTestForwarder(nil)
-			rf := NewResourceForwarder(NewEntryManager(ioutil.Discard), "")
+			rf := NewResourceForwarder(NewEntryManager(ioutil.Discard), "", "", test.userDefined)
 			rf.EntryForwarder = fakeForwarder
 
 			t.Override(&retrieveAvailablePort, mockRetrieveAvailablePort(map[int]struct{}{}, test.availablePorts))
-			t.Override(&retrieveServices, func(string) ([]latest.PortForwardResource, error) {
+			t.Override(&retrieveServices, func(string, string) ([]latest.PortForwardResource, error) {
 				return test.resources, nil
 			})
 
@@ -135,6 +163,10 @@ func TestStart(t *testing.T) {
 			if err != nil {
 				t.Fatalf("expected entries didn't match actual entries. Expected: \n %v Actual: \n %v", test.expected, fakeForwarder.forwardedEntries)
 			}
+			for key := range test.expected {
+				_, found := fakeForwarder.forwardedEntries.Load(key)
+				t.CheckDeepEqual(true, found)
+			}
 		})
 	}
 }
@@ -188,7 +220,7 @@ func TestGetCurrentEntryFunc(t *testing.T) {
 			expectedEntry := test.expected
 			expectedEntry.resource = test.resource
 
-			rf := NewResourceForwarder(NewEntryManager(ioutil.Discard), "")
+			rf := NewResourceForwarder(NewEntryManager(ioutil.Discard), "", "", nil)
 			rf.forwardedResources = generateSyncMap(test.forwardedResources)
 
 			t.Override(&retrieveAvailablePort, mockRetrieveAvailablePort(map[int]struct{}{}, test.availablePorts))
@@ -199,6 +231,59 @@ func TestGetCurrentEntryFunc(t *testing.T) {
 	}
 }
 
+func TestGetCurrentEntryLocalPort(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&retrieveAvailablePort, func(_ string, port int, _ *sync.Map) int { return port })
+
+		rf := NewResourceForwarder(NewEntryManager(ioutil.Discard), "", "", nil)
+		entry := rf.getCurrentEntry(latest.PortForwardResource{
+			Type:      "deployment",
+			Name:      "depName",
+			Port:      8080,
+			LocalPort: 9090,
+		})
+
+		t.CheckDeepEqual(int32(9090), entry.localPort)
+	})
+}
+
+func TestGetCurrentEntryAddress(t *testing.T) {
+	tests := []struct {
+		description     string
+		address         string
+		expectedAddress string
+	}{
+		{
+			description:     "loopback by default",
+			expectedAddress: "127.0.0.1",
+		},
+		{
+			description:     "configured address",
+			address:         "0.0.0.0",
+			expectedAddress: "0.0.0.0",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var checkedAddress string
+			t.Override(&retrieveAvailablePort, func(address string, port int, _ *sync.Map) int {
+				checkedAddress = address
+				return port
+			})
+
+			rf := NewResourceForwarder(NewEntryManager(ioutil.Discard), "", "", nil)
+			rf.getCurrentEntry(latest.PortForwardResource{
+				Type:    "deployment",
+				Name:    "depName",
+				Port:    8080,
+				Address: test.address,
+			})
+
+			t.CheckDeepEqual(test.expectedAddress, checkedAddress)
+		})
+	}
+}
+
 func generateSyncMap(m map[string]*portForwardEntry) *sync.Map {
 	sm := &sync.Map{}
 	for k, v := range m {
@@ -22,8 +22,8 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 )
 
-// AggregatePodWatcher returns a watcher for multiple namespaces.
-func AggregatePodWatcher(namespaces []string, aggregate chan<- watch.Event) (func(), error) {
+// AggregatePodWatcher returns a watcher for multiple namespaces of a kubectl context.
+func AggregatePodWatcher(kubeContext string, namespaces []string, aggregate chan<- watch.Event) (func(), error) {
 	watchers := make([]watch.Interface, 0, len(namespaces))
 	stopWatchers := func() {
 		for _, w := range watchers {
@@ -31,7 +31,7 @@ func AggregatePodWatcher(namespaces []string, aggregate chan<- watch.Event) (fun
 		}
 	}
 
-	kubeclient, err := Client()
+	kubeclient, err := Client(kubeContext)
 	if err != nil {
 		return func() {}, errors.Wrap(err, "getting k8s client")
 	}
@@ -35,9 +35,9 @@ func pod(name string) *v1.Pod {
 
 func TestAggregatePodWatcher(t *testing.T) {
 	testutil.Run(t, "fail to get client", func(t *testutil.T) {
-		t.Override(&Client, func() (kubernetes.Interface, error) { return nil, errors.New("unable to get client") })
+		t.Override(&Client, func(string) (kubernetes.Interface, error) { return nil, errors.New("unable to get client") })
 
-		cleanup, err := AggregatePodWatcher([]string{"ns"}, nil)
+		cleanup, err := AggregatePodWatcher("", []string{"ns"}, nil)
 		defer cleanup()
 
 		t.CheckErrorContains("unable to get client", err)
@@ -45,13 +45,13 @@ func TestAggregatePodWatcher(t *testing.T) {
 
 	testutil.Run(t, "fail to watch pods", func(t *testutil.T) {
 		clientset := fake.NewSimpleClientset()
-		t.Override(&Client, func() (kubernetes.Interface, error) { return clientset, nil })
+		t.Override(&Client, func(string) (kubernetes.Interface, error) { return clientset, nil })
 
 		clientset.Fake.PrependWatchReactor("pods", func(action k8stesting.Action) (handled bool, ret watch.Interface, err error) {
 			return true, nil, errors.New("unable to watch")
 		})
 
-		cleanup, err := AggregatePodWatcher([]string{"ns"}, nil)
+		cleanup, err := AggregatePodWatcher("", []string{"ns"}, nil)
 		defer cleanup()
 
 		t.CheckErrorContains("unable to watch", err)
@@ -59,10 +59,10 @@ func TestAggregatePodWatcher(t *testing.T) {
 
 	testutil.Run(t, "watch 3 events", func(t *testutil.T) {
 		clientset := fake.NewSimpleClientset()
-		t.Override(&Client, func() (kubernetes.Interface, error) { return clientset, nil })
+		t.Override(&Client, func(string) (kubernetes.Interface, error) { return clientset, nil })
 
 		events := make(chan watch.Event)
-		cleanup, err := AggregatePodWatcher([]string{"ns1", "ns2"}, events)
+		cleanup, err := AggregatePodWatcher("", []string{"ns1", "ns2"}, events)
 		defer cleanup()
 		t.CheckNoError(err)
 
@@ -0,0 +1,229 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runlog
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EventsFile is the name of the file where the events are recorded.
+	EventsFile = "events.jsonl"
+
+	// IndexFile is the name of the file that summarizes the content of a run directory.
+	IndexFile = "index.json"
+)
+
+// for testing
+var now = time.Now
+
+var errClosed = errors.New("archive closed")
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Archive writes the logs and the events of a Skaffold run to files
+// under a directory named after the run. A nil Archive writes nothing.
+type Archive struct {
+	dir      string
+	runID    string
+	maxSize  int64
+	maxFiles int
+	start    time.Time
+
+	lock   sync.Mutex
+	files  map[string]*rotatingFile
+	closed bool
+}
+
+// Index summarizes the files written for a run.
+type Index struct {
+	RunID     string    `json:"runId"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Files     []File    `json:"files"`
+}
+
+// File describes a file of the run directory and its rotated parts.
+// Size is the size of the current part. Lines counts all the lines written to the file,
+// including the ones that were rotated out.
+type File struct {
+	Name    string   `json:"name"`
+	Size    int64    `json:"size"`
+	Lines   int      `json:"lines"`
+	Rotated []string `json:"rotated,omitempty"`
+}
+
+// New creates an Archive in `root/runID`. It returns a nil Archive if root is empty.
+// Files are rotated when they reach maxSize bytes, keeping at most maxFiles rotated files.
+func New(root, runID string, maxSize int64, maxFiles int) (*Archive, error) {
+	if root == "" {
+		return nil, nil
+	}
+	if maxSize <= 0 {
+		return nil, errors.Errorf("invalid maximum log file size %d", maxSize)
+	}
+	if maxFiles < 0 {
+		return nil, errors.Errorf("invalid number of rotated log files %d", maxFiles)
+	}
+
+	dir := filepath.Join(root, runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "creating run log directory %s", dir)
+	}
+
+	return &Archive{
+		dir:      dir,
+		runID:    runID,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+		start:    now(),
+		files:    map[string]*rotatingFile{},
+	}, nil
+}
+
+// Dir returns the directory where the files are written.
+func (a *Archive) Dir() string {
+	if a == nil {
+		return ""
+	}
+	return a.dir
+}
+
+// Writer returns a writer to the file made of the given path elements, relative to the run directory.
+// Characters that are not safe in file names are replaced in each element. Asking twice for the same
+// path returns the same writer, so that the logs of a restarted container go to the same file.
+func (a *Archive) Writer(elem ...string) (io.Writer, error) {
+	if a == nil {
+		return ioutil.Discard, nil
+	}
+
+	var safe []string
+	for _, e := range elem {
+		safe = append(safe, unsafeChars.ReplaceAllString(e, "_"))
+	}
+	name := filepath.Join(safe...)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.closed {
+		return nil, errClosed
+	}
+
+	if f, present := a.files[name]; present {
+		return f, nil
+	}
+
+	f, err := openRotatingFile(filepath.Join(a.dir, name), a.maxSize, a.maxFiles)
+	if err != nil {
+		return nil, err
+	}
+	a.files[name] = f
+	return f, nil
+}
+
+// RecordEvents writes every Skaffold event, past and future, as one JSON object per line.
+func (a *Archive) RecordEvents() error {
+	if a == nil {
+		return nil
+	}
+
+	w, err := a.Writer(EventsFile)
+	if err != nil {
+		return err
+	}
+
+	marshaler := jsonpb.Marshaler{}
+	go event.ForEachEvent(func(entry *proto.LogEntry) error {
+		if a.isClosed() {
+			return errClosed
+		}
+
+		line, err := marshaler.MarshalToString(entry)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, line+"\n")
+		return err
+	})
+
+	return nil
+}
+
+func (a *Archive) isClosed() bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.closed
+}
+
+// Close closes all the files and writes the index of the run directory.
+func (a *Archive) Close() error {
+	if a == nil {
+		return nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+
+	index := Index{
+		RunID:     a.runID,
+		StartTime: a.start,
+		EndTime:   now(),
+		Files:     []File{},
+	}
+
+	var firstErr error
+	for name, f := range a.files {
+		file, err := f.close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		file.Name = filepath.ToSlash(name)
+		index.Files = append(index.Files, file)
+	}
+	sort.Slice(index.Files, func(i, j int) bool { return index.Files[i].Name < index.Files[j].Name })
+
+	buf, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling run log index")
+	}
+	if err := ioutil.WriteFile(filepath.Join(a.dir, IndexFile), buf, 0644); err != nil {
+		return errors.Wrap(err, "writing run log index")
+	}
+
+	return firstErr
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runlog
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/pkg/errors"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		description string
+		maxSize     int64
+		maxFiles    int
+		shouldErr   bool
+	}{
+		{
+			description: "valid",
+			maxSize:     1024,
+			maxFiles:    3,
+		},
+		{
+			description: "no rotated files",
+			maxSize:     1024,
+		},
+		{
+			description: "invalid size",
+			maxFiles:    3,
+			shouldErr:   true,
+		},
+		{
+			description: "invalid number of files",
+			maxSize:     1024,
+			maxFiles:    -1,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir()
+
+			archive, err := New(tmpDir.Root(), "1234", test.maxSize, test.maxFiles)
+
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				t.CheckDeepEqual(tmpDir.Path("1234"), archive.Dir())
+			}
+		})
+	}
+}
+
+func TestNewDisabled(t *testing.T) {
+	archive, err := New("", "1234", 0, 0)
+
+	testutil.CheckError(t, false, err)
+	if archive != nil {
+		t.Errorf("expected no archive, got %v", archive)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&now, fakeNow(time.Date(2019, 6, 13, 10, 30, 0, 0, time.UTC)))
+		tmpDir := t.NewTempDir()
+
+		archive, err := New(tmpDir.Root(), "1234", 1024, 3)
+		t.CheckNoError(err)
+
+		w, err := archive.Writer("pods", "arn:aws:eks/cluster", "default", "web", "web.log")
+		t.CheckNoError(err)
+		io.WriteString(w, "first line\n")
+
+		again, err := archive.Writer("pods", "arn:aws:eks/cluster", "default", "web", "web.log")
+		t.CheckNoError(err)
+		io.WriteString(again, "second line\n")
+
+		other, err := archive.Writer("pods", "", "default", "db", "db.log")
+		t.CheckNoError(err)
+		io.WriteString(other, "ready\n")
+
+		t.CheckNoError(archive.Close())
+
+		_, err = archive.Writer("pods", "", "default", "db", "db.log")
+		t.CheckError(true, err)
+
+		t.CheckDeepEqual("first line\nsecond line\n", readFile(tmpDir.Path("1234/pods/arn_aws_eks_cluster/default/web/web.log")))
+		t.CheckDeepEqual("ready\n", readFile(tmpDir.Path("1234/pods/default/db/db.log")))
+
+		var index Index
+		t.CheckNoError(json.Unmarshal([]byte(readFile(tmpDir.Path("1234/index.json"))), &index))
+		t.CheckDeepEqual(Index{
+			RunID:     "1234",
+			StartTime: time.Date(2019, 6, 13, 10, 30, 0, 0, time.UTC),
+			EndTime:   time.Date(2019, 6, 13, 10, 30, 1, 0, time.UTC),
+			Files: []File{
+				{Name: "pods/arn_aws_eks_cluster/default/web/web.log", Size: 23, Lines: 2},
+				{Name: "pods/default/db/db.log", Size: 6, Lines: 1},
+			},
+		}, index)
+	})
+}
+
+func TestRecordEvents(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		event.InitializeState(&runcontext.RunContext{Cfg: &latest.Pipeline{Build: latest.BuildConfig{}}})
+		tmpDir := t.NewTempDir()
+
+		archive, err := New(tmpDir.Root(), "1234", 1024, 3)
+		t.CheckNoError(err)
+		t.CheckNoError(archive.RecordEvents())
+
+		event.DeployComplete("kube-context")
+
+		path := tmpDir.Path("1234/" + EventsFile)
+		for i := 0; i < 100 && !strings.Contains(readFile(path), "Deploy complete"); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.CheckNoError(archive.Close())
+
+		t.CheckContains(`"entry":"Deploy complete for context kube-context"`, readFile(path))
+
+		// Sending events after the archive is closed must not block.
+		event.DeployFailed("kube-context", errors.New("too late"))
+		event.DeployFailed("kube-context", errors.New("too late"))
+	})
+}
+
+func TestNilArchive(t *testing.T) {
+	var archive *Archive
+
+	w, err := archive.Writer("pods", "web.log")
+	testutil.CheckError(t, false, err)
+	_, err = io.WriteString(w, "ignored\n")
+	testutil.CheckError(t, false, err)
+	testutil.CheckError(t, false, archive.RecordEvents())
+	testutil.CheckError(t, false, archive.Close())
+	testutil.CheckDeepEqual(t, "", archive.Dir())
+}
+
+func fakeNow(start time.Time) func() time.Time {
+	current := start.Add(-time.Second)
+	return func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+}
+
+func readFile(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
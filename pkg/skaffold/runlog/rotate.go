@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runlog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// rotatingFile is a file that is renamed to `path.1` when it grows over maxSize.
+// Older files are shifted to `path.2`, `path.3`... and only maxFiles of them are kept.
+type rotatingFile struct {
+	lock     sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file    *os.File
+	size    int64
+	lines   int
+	rotated int
+}
+
+func openRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Wrapf(err, "creating directory for %s", path)
+	}
+
+	f := &rotatingFile{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", f.path)
+	}
+
+	f.file = file
+	f.size = 0
+	return nil
+}
+
+// Write writes p to the file, rotating it first if p would make it grow over maxSize.
+// A single write is never split across files so that lines are kept whole.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.file == nil {
+		return 0, errClosed
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	f.lines += bytes.Count(p[:n], []byte{'\n'})
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return errors.Wrapf(err, "closing %s", f.path)
+	}
+
+	if f.maxFiles == 0 {
+		return f.open()
+	}
+
+	// Drop the oldest file and shift the others.
+	os.Remove(rotatedPath(f.path, f.maxFiles))
+	for i := f.maxFiles - 1; i > 0; i-- {
+		if err := os.Rename(rotatedPath(f.path, i), rotatedPath(f.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "rotating %s", f.path)
+		}
+	}
+	if err := os.Rename(f.path, rotatedPath(f.path, 1)); err != nil {
+		return errors.Wrapf(err, "rotating %s", f.path)
+	}
+
+	if f.rotated < f.maxFiles {
+		f.rotated++
+	}
+	return f.open()
+}
+
+// close closes the file and describes it for the index.
+func (f *rotatingFile) close() (File, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	file := File{
+		Size:  f.size,
+		Lines: f.lines,
+	}
+	for i := 1; i <= f.rotated; i++ {
+		file.Rotated = append(file.Rotated, filepath.Base(rotatedPath(f.path, i)))
+	}
+
+	if f.file == nil {
+		return file, nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return file, errors.Wrapf(err, "closing %s", f.path)
+	}
+	return file, nil
+}
+
+func rotatedPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
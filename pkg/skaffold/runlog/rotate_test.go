@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runlog
+
+import (
+	"io"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRotatingFile(t *testing.T) {
+	tests := []struct {
+		description     string
+		maxFiles        int
+		lines           []string
+		expectedFiles   map[string]string
+		expectedRotated []string
+	}{
+		{
+			description:   "no rotation",
+			maxFiles:      2,
+			lines:         []string{"one\n", "two\n"},
+			expectedFiles: map[string]string{"web.log": "one\ntwo\n"},
+		},
+		{
+			description: "rotate",
+			maxFiles:    2,
+			lines:       []string{"one\n", "two\n", "three\n"},
+			expectedFiles: map[string]string{
+				"web.log":   "three\n",
+				"web.log.1": "one\ntwo\n",
+			},
+			expectedRotated: []string{"web.log.1"},
+		},
+		{
+			description: "drop oldest files",
+			maxFiles:    2,
+			lines:       []string{"one\n", "two\n", "three\n", "four\n", "five\n", "six\n", "seven\n"},
+			expectedFiles: map[string]string{
+				"web.log":   "six\nseven\n",
+				"web.log.1": "four\nfive\n",
+				"web.log.2": "three\n",
+				"web.log.3": "",
+			},
+			expectedRotated: []string{"web.log.1", "web.log.2"},
+		},
+		{
+			description: "no rotated files",
+			lines:       []string{"one\n", "two\n", "three\n"},
+			expectedFiles: map[string]string{
+				"web.log":   "three\n",
+				"web.log.1": "",
+			},
+		},
+		{
+			description: "line bigger than the maximum size",
+			maxFiles:    1,
+			lines:       []string{"a very long line\n", "short\n"},
+			expectedFiles: map[string]string{
+				"web.log":   "short\n",
+				"web.log.1": "a very long line\n",
+			},
+			expectedRotated: []string{"web.log.1"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir()
+
+			f, err := openRotatingFile(tmpDir.Path("web.log"), 10, test.maxFiles)
+			t.CheckNoError(err)
+			for _, line := range test.lines {
+				_, err := io.WriteString(f, line)
+				t.CheckNoError(err)
+			}
+			file, err := f.close()
+			t.CheckNoError(err)
+
+			for name, content := range test.expectedFiles {
+				t.CheckDeepEqual(content, readFile(tmpDir.Path(name)))
+			}
+			t.CheckDeepEqual(len(test.lines), file.Lines)
+			t.CheckDeepEqual(test.expectedRotated, file.Rotated)
+		})
+	}
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		f, err := openRotatingFile(t.NewTempDir().Path("logs/web.log"), 10, 1)
+		t.CheckNoError(err)
+		_, err = f.close()
+		t.CheckNoError(err)
+
+		_, err = io.WriteString(f, "too late\n")
+
+		t.CheckError(true, err)
+	})
+}
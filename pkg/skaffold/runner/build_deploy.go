@@ -21,6 +21,7 @@ import (
 	"io"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -50,9 +51,12 @@ func (r *SkaffoldRunner) BuildAndTest(ctx context.Context, out io.Writer, artifa
 	}
 
 	if !r.runCtx.Opts.SkipTests {
+		event.TestInProgress()
 		if err = r.Tester.Test(ctx, out, bRes); err != nil {
+			event.TestFailed(err)
 			return nil, errors.Wrap(err, "test failed")
 		}
+		event.TestComplete()
 	}
 
 	// Update which images are logged.
@@ -65,28 +69,38 @@ func (r *SkaffoldRunner) BuildAndTest(ctx context.Context, out io.Writer, artifa
 	return bRes, nil
 }
 
-// DeployAndLog deploys a list of already built artifacts and optionally show the logs.
+// DeployAndLog deploys a list of already built artifacts and optionally show the logs
+// and forward ports, until the context is cancelled.
 func (r *SkaffoldRunner) DeployAndLog(ctx context.Context, out io.Writer, artifacts []build.Artifact) error {
-	if !r.runCtx.Opts.Tail {
+	if !r.runCtx.Opts.Tail && !r.runCtx.Opts.PortForward.Enabled {
 		return r.Deploy(ctx, out, artifacts)
 	}
 
-	var imageNames []string
-	for _, artifact := range artifacts {
-		imageNames = append(imageNames, artifact.ImageName)
-	}
+	if r.runCtx.Opts.Tail {
+		var imageNames []string
+		for _, artifact := range artifacts {
+			imageNames = append(imageNames, artifact.ImageName)
+		}
 
-	logger := r.newLoggerForImages(out, imageNames)
-	defer logger.Stop()
+		logger := r.newLoggerForImages(out, imageNames)
+		defer logger.Stop()
 
-	if err := logger.Start(ctx); err != nil {
-		return errors.Wrap(err, "starting logger")
+		if err := logger.Start(ctx); err != nil {
+			return errors.Wrap(err, "starting logger")
+		}
 	}
 
 	if err := r.Deploy(ctx, out, artifacts); err != nil {
 		return err
 	}
 
+	forwarderManager := r.newForwarderManager(out)
+	defer forwarderManager.Stop()
+
+	if err := forwarderManager.Start(ctx); err != nil {
+		return errors.Wrap(err, "starting forwarder manager")
+	}
+
 	<-ctx.Done()
 
 	return nil
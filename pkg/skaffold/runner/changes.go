@@ -25,7 +25,7 @@ import (
 type changes struct {
 	dirtyArtifacts []*artifactChange
 	needsRebuild   []*latest.Artifact
-	needsResync    []*sync.Item
+	needsResync    []*artifactSync
 	needsRedeploy  bool
 	needsReload    bool
 }
@@ -35,16 +35,54 @@ type artifactChange struct {
 	events   watch.Events
 }
 
+type artifactSync struct {
+	artifact *latest.Artifact
+	item     *sync.Item
+}
+
 func (c *changes) AddDirtyArtifact(a *latest.Artifact, e watch.Events) {
 	c.dirtyArtifacts = append(c.dirtyArtifacts, &artifactChange{artifact: a, events: e})
 }
 
 func (c *changes) AddRebuild(a *latest.Artifact) {
+	for _, rebuild := range c.needsRebuild {
+		if rebuild == a {
+			return
+		}
+	}
 	c.needsRebuild = append(c.needsRebuild, a)
 }
 
-func (c *changes) AddResync(s *sync.Item) {
-	c.needsResync = append(c.needsResync, s)
+func (c *changes) AddResync(a *latest.Artifact, s *sync.Item) {
+	c.needsResync = append(c.needsResync, &artifactSync{artifact: a, item: s})
+}
+
+// RemoveArtifacts forgets the rebuilds and syncs of artifacts that were just built.
+func (c *changes) RemoveArtifacts(artifacts []*latest.Artifact) {
+	built := map[*latest.Artifact]bool{}
+	for _, a := range artifacts {
+		built[a] = true
+	}
+
+	var rebuild []*latest.Artifact
+	for _, a := range c.needsRebuild {
+		if !built[a] {
+			rebuild = append(rebuild, a)
+		}
+	}
+	c.needsRebuild = rebuild
+
+	var resync []*artifactSync
+	for _, s := range c.needsResync {
+		if !built[s.artifact] {
+			resync = append(resync, s)
+		}
+	}
+	c.needsResync = resync
+}
+
+func (c *changes) pending() bool {
+	return len(c.needsResync) > 0 || len(c.needsRebuild) > 0 || c.needsRedeploy
 }
 
 func (c *changes) reset() {
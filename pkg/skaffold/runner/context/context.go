@@ -17,6 +17,7 @@ limitations under the License.
 package context
 
 import (
+	"fmt"
 	"os"
 
 	configutil "github.com/GoogleContainerTools/skaffold/cmd/skaffold/app/cmd/config"
@@ -37,6 +38,10 @@ type RunContext struct {
 	WorkingDir         string
 	Namespaces         []string
 	InsecureRegistries map[string]bool
+
+	// EphemeralNamespace is the per-developer namespace that skaffold creates
+	// before deploying, if any.
+	EphemeralNamespace string
 }
 
 func GetRunContext(opts *config.SkaffoldOptions, cfg *latest.Pipeline) (*RunContext, error) {
@@ -53,7 +58,22 @@ func GetRunContext(opts *config.SkaffoldOptions, cfg *latest.Pipeline) (*RunCont
 		return nil, errors.Wrap(err, "finding current directory")
 	}
 
-	namespaces, err := runnerutil.GetAllPodNamespaces(opts.Namespace)
+	var ephemeralNamespace string
+	if cfg.Deploy.EphemeralNamespace != nil && opts.Namespace == "" {
+		ephemeralNamespace, err = runnerutil.EphemeralNamespace(cfg.Deploy.EphemeralNamespace.Template, cwd)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting ephemeral namespace")
+		}
+		logrus.Infof("Using ephemeral namespace: %s", ephemeralNamespace)
+
+		// Deploy to the ephemeral namespace as if it was given with --namespace.
+		// The options are copied because they outlive this run context.
+		withNamespace := *opts
+		withNamespace.Namespace = ephemeralNamespace
+		opts = &withNamespace
+	}
+
+	namespaces, err := runnerutil.GetAllPodNamespaces(kubeContext, opts.Namespace)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting namespace list")
 	}
@@ -83,5 +103,45 @@ func GetRunContext(opts *config.SkaffoldOptions, cfg *latest.Pipeline) (*RunCont
 		KubeContext:        kubeContext,
 		Namespaces:         namespaces,
 		InsecureRegistries: insecureRegistries,
+		EphemeralNamespace: ephemeralNamespace,
 	}, nil
 }
+
+// Targets returns a RunContext for each kubectl context listed in `deploy.kubeContexts`.
+// Without any listed context, the RunContext itself is the only target.
+func (r *RunContext) Targets() ([]*RunContext, error) {
+	if len(r.Cfg.Deploy.KubeContexts) == 0 {
+		return []*RunContext{r}, nil
+	}
+
+	kubeConfig, err := kubectx.CurrentConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting kubeconfig")
+	}
+
+	var targets []*RunContext
+	for _, kubeContext := range r.Cfg.Deploy.KubeContexts {
+		if _, found := kubeConfig.Contexts[kubeContext.Name]; !found {
+			return nil, fmt.Errorf("kubectl context %s not found in kubeconfig", kubeContext.Name)
+		}
+
+		// --namespace takes precedence over the namespace of each context.
+		opts := *r.Opts
+		if opts.Namespace == "" {
+			opts.Namespace = kubeContext.Namespace
+		}
+
+		namespaces, err := runnerutil.GetAllPodNamespaces(kubeContext.Name, opts.Namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting namespace list for context %s", kubeContext.Name)
+		}
+
+		target := *r
+		target.Opts = &opts
+		target.KubeContext = kubeContext.Name
+		target.Namespaces = namespaces
+		targets = append(targets, &target)
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestTargets(t *testing.T) {
+	tests := []struct {
+		description        string
+		namespace          string
+		kubeContexts       []latest.KubeContext
+		expectedContexts   []string
+		expectedNamespaces [][]string
+		shouldErr          bool
+	}{
+		{
+			description:        "current context",
+			expectedContexts:   []string{"cluster1"},
+			expectedNamespaces: [][]string{{"ns1"}},
+		},
+		{
+			description:        "multiple contexts",
+			kubeContexts:       []latest.KubeContext{{Name: "cluster1"}, {Name: "cluster2", Namespace: "other"}},
+			expectedContexts:   []string{"cluster1", "cluster2"},
+			expectedNamespaces: [][]string{{"ns1"}, {"other"}},
+		},
+		{
+			description:        "--namespace overrides namespaces",
+			namespace:          "flag",
+			kubeContexts:       []latest.KubeContext{{Name: "cluster1"}, {Name: "cluster2", Namespace: "other"}},
+			expectedContexts:   []string{"cluster1", "cluster2"},
+			expectedNamespaces: [][]string{{"flag"}, {"flag"}},
+		},
+		{
+			description:  "unknown context",
+			kubeContexts: []latest.KubeContext{{Name: "unknown"}},
+			shouldErr:    true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.SetupFakeKubernetesContext(api.Config{
+				CurrentContext: "cluster1",
+				Contexts: map[string]*api.Context{
+					"cluster1": {Namespace: "ns1"},
+					"cluster2": {Namespace: "ns2"},
+				},
+			})
+			kubectx.ResetCurrentConfig()
+
+			runCtx := &RunContext{
+				Opts:        &config.SkaffoldOptions{Namespace: test.namespace},
+				Cfg:         &latest.Pipeline{Deploy: latest.DeployConfig{KubeContexts: test.kubeContexts}},
+				KubeContext: "cluster1",
+				Namespaces:  []string{"ns1"},
+			}
+
+			targets, err := runCtx.Targets()
+
+			var kubeContexts []string
+			var namespaces [][]string
+			for _, target := range targets {
+				kubeContexts = append(kubeContexts, target.KubeContext)
+				namespaces = append(namespaces, target.Namespaces)
+			}
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expectedContexts, kubeContexts)
+			t.CheckDeepEqual(test.expectedNamespaces, namespaces)
+		})
+	}
+}
@@ -19,14 +19,14 @@ package runner
 import (
 	"context"
 	"io"
+	"sort"
 
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/portforward"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 )
 
 // ErrorConfigurationChanged is a special error that's returned when the skaffold configuration was changed.
@@ -38,56 +38,11 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 	logger := r.newLogger(out, artifacts)
 	defer logger.Stop()
 
-	forwarderManager := portforward.NewForwarderManager(out, r.imageList, r.runCtx.Namespaces, r.defaultLabeller.K8sManagedByLabelKeyValueString(), r.runCtx.Opts.PortForward)
+	forwarderManager := r.newForwarderManager(out)
 	defer forwarderManager.Stop()
 
 	// Create watcher and register artifacts to build current state of files.
-	changed := changes{}
-	onChange := func() error {
-		defer changed.reset()
-
-		logger.Mute()
-
-		for _, a := range changed.dirtyArtifacts {
-			s, err := sync.NewItem(a.artifact, a.events, r.builds, r.runCtx.InsecureRegistries)
-			if err != nil {
-				return errors.Wrap(err, "sync")
-			}
-			if s != nil {
-				changed.AddResync(s)
-			} else {
-				changed.AddRebuild(a.artifact)
-			}
-		}
-
-		switch {
-		case changed.needsReload:
-			return ErrorConfigurationChanged
-		case len(changed.needsResync) > 0:
-			for _, s := range changed.needsResync {
-				color.Default.Fprintf(out, "Syncing %d files for %s\n", len(s.Copy)+len(s.Delete), s.Image)
-
-				if err := r.Syncer.Sync(ctx, s); err != nil {
-					logrus.Warnln("Skipping deploy due to sync error:", err)
-					return nil
-				}
-			}
-		case len(changed.needsRebuild) > 0:
-			if _, err := r.BuildAndTest(ctx, out, changed.needsRebuild); err != nil {
-				logrus.Warnln("Skipping deploy due to error:", err)
-				return nil
-			}
-			fallthrough
-		case changed.needsRedeploy:
-			if err := r.Deploy(ctx, out, r.builds); err != nil {
-				logrus.Warnln("Skipping deploy due to error:", err)
-				return nil
-			}
-		}
-
-		logger.Unmute()
-		return nil
-	}
+	loop := newDevLoop(ctx, out, r, logger, artifacts)
 
 	// Watch artifacts
 	for i := range artifacts {
@@ -96,9 +51,21 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 			continue
 		}
 
+		// Only docker builds honor the .dockerignore file.
+		var dockerIgnorer *watch.Ignorer
+		if artifact.DockerArtifact != nil {
+			var err error
+			if dockerIgnorer, err = watch.NewDockerIgnorer(artifact.Workspace, artifact.DockerArtifact.DockerfilePath); err != nil {
+				return errors.Wrapf(err, "reading ignored files for artifact %s", artifact.ImageName)
+			}
+		}
+
 		if err := r.Watcher.Register(
-			func() ([]string, error) { return r.Builder.DependenciesForArtifact(ctx, artifact) },
-			func(e watch.Events) { changed.AddDirtyArtifact(artifact, e) },
+			dockerIgnorer.Filtered(func() ([]string, error) { return r.Builder.DependenciesForArtifact(ctx, artifact) }),
+			func(e watch.Events) {
+				event.FileChanged("artifact", artifact.ImageName, e.Added, e.Modified, e.Deleted)
+				loop.record(func(c *changes) { c.AddDirtyArtifact(artifact, e) })
+			},
 		); err != nil {
 			return errors.Wrapf(err, "watching files for artifact %s", artifact.ImageName)
 		}
@@ -107,7 +74,10 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 	// Watch test configuration
 	if err := r.Watcher.Register(
 		r.Tester.TestDependencies,
-		func(watch.Events) { changed.needsRedeploy = true },
+		func(e watch.Events) {
+			event.FileChanged("test", "", e.Added, e.Modified, e.Deleted)
+			loop.record(func(c *changes) { c.needsRedeploy = true })
+		},
 	); err != nil {
 		return errors.Wrap(err, "watching test files")
 	}
@@ -115,7 +85,10 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 	// Watch deployment configuration
 	if err := r.Watcher.Register(
 		r.Deployer.Dependencies,
-		func(watch.Events) { changed.needsRedeploy = true },
+		func(e watch.Events) {
+			event.FileChanged("deploy", "", e.Added, e.Modified, e.Deleted)
+			loop.record(func(c *changes) { c.needsRedeploy = true })
+		},
 	); err != nil {
 		return errors.Wrap(err, "watching files for deployer")
 	}
@@ -123,32 +96,58 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 	// Watch Skaffold configuration
 	if err := r.Watcher.Register(
 		func() ([]string, error) { return []string{r.runCtx.Opts.ConfigurationFile}, nil },
-		func(watch.Events) { changed.needsReload = true },
+		func(e watch.Events) {
+			event.FileChanged("config", "", e.Added, e.Modified, e.Deleted)
+			loop.record(func(c *changes) { c.needsReload = true })
+		},
 	); err != nil {
 		return errors.Wrapf(err, "watching skaffold configuration %s", r.runCtx.Opts.ConfigurationFile)
 	}
 
 	// First build
+	event.DevLoopInProgress(loop.iteration)
 	if _, err := r.BuildAndTest(ctx, out, artifacts); err != nil {
+		event.DevLoopFailed(loop.iteration, err)
 		return errors.Wrap(err, "exiting dev mode because first build failed")
 	}
 
 	// Start logs
 	if r.runCtx.Opts.TailDev {
 		if err := logger.Start(ctx); err != nil {
+			event.DevLoopFailed(loop.iteration, err)
 			return errors.Wrap(err, "starting logger")
 		}
 	}
 
 	// First deploy
 	if err := r.Deploy(ctx, out, r.builds); err != nil {
+		event.DevLoopFailed(loop.iteration, err)
 		return errors.Wrap(err, "exiting dev mode because first deploy failed")
 	}
+	event.DevLoopComplete(loop.iteration)
 
 	// Forward ports
 	if err := forwarderManager.Start(ctx); err != nil {
 		return errors.Wrap(err, "starting forwarder manager")
 	}
 
-	return r.Watcher.Run(ctx, out, onChange)
+	// Let the API control the dev loop
+	server.RegisterDevLoop(loop)
+	defer server.RegisterDevLoop(nil)
+
+	return r.Watcher.Run(ctx, out, loop.onChange)
+}
+
+// syncedFiles lists the local files copied and deleted by a sync.
+func syncedFiles(item *sync.Item) ([]string, []string) {
+	var copied, deleted []string
+	for src := range item.Copy {
+		copied = append(copied, src)
+	}
+	for src := range item.Delete {
+		deleted = append(deleted, src)
+	}
+	sort.Strings(copied)
+	sort.Strings(deleted)
+	return copied, deleted
 }
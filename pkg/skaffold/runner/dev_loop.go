@@ -0,0 +1,319 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	gosync "sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// background runs the iterations requested through the API.
+var background = func(fn func()) { go fn() }
+
+// devLoop applies the changes collected by the watcher.
+// Auto build, auto sync and auto deploy can be turned off through the API,
+// in which case the changes they would apply are kept pending.
+type devLoop struct {
+	runner    *SkaffoldRunner
+	ctx       context.Context
+	out       io.Writer
+	logger    logger
+	artifacts []*latest.Artifact
+
+	lock       gosync.Mutex
+	changed    changes
+	iteration  int
+	autoBuild  bool
+	autoSync   bool
+	autoDeploy bool
+}
+
+func newDevLoop(ctx context.Context, out io.Writer, r *SkaffoldRunner, logger logger, artifacts []*latest.Artifact) *devLoop {
+	return &devLoop{
+		runner:     r,
+		ctx:        ctx,
+		out:        out,
+		logger:     logger,
+		artifacts:  artifacts,
+		autoBuild:  true,
+		autoSync:   true,
+		autoDeploy: true,
+	}
+}
+
+// record updates the changes from a watcher callback.
+func (l *devLoop) record(update func(*changes)) {
+	l.lock.Lock()
+	update(&l.changed)
+	l.lock.Unlock()
+}
+
+// onChange is called by the watcher once the changes are recorded.
+func (l *devLoop) onChange() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.start()
+
+	for _, a := range l.changed.dirtyArtifacts {
+		s, err := sync.NewItem(l.ctx, a.artifact, a.events, l.runner.builds, l.runner.runCtx.InsecureRegistries, l.runner.Builder.SyncMap)
+		if err != nil {
+			l.changed.reset()
+			event.DevLoopFailed(l.iteration, err)
+			return errors.Wrap(err, "sync")
+		}
+		if s != nil {
+			l.changed.AddResync(a.artifact, s)
+		} else {
+			l.changed.AddRebuild(a.artifact)
+		}
+	}
+	l.changed.dirtyArtifacts = nil
+
+	if l.changed.needsReload {
+		l.changed.reset()
+		event.DevLoopComplete(l.iteration)
+		return ErrorConfigurationChanged
+	}
+
+	l.finish(l.applyPending())
+	return nil
+}
+
+func (l *devLoop) start() {
+	l.iteration++
+	event.DevLoopInProgress(l.iteration)
+	l.logger.Mute()
+}
+
+func (l *devLoop) finish(err error) {
+	if err != nil {
+		event.DevLoopFailed(l.iteration, err)
+		return
+	}
+
+	l.logger.Unmute()
+	event.DevLoopComplete(l.iteration)
+}
+
+// applyPending syncs, builds and deploys the pending changes, as far as
+// auto sync, auto build and auto deploy allow.
+func (l *devLoop) applyPending() error {
+	if err := l.syncAndBuild(); err != nil {
+		l.dropPending()
+		return err
+	}
+
+	if l.autoDeploy && l.changed.needsRedeploy {
+		if err := l.deploy(); err != nil {
+			l.dropPending()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *devLoop) syncAndBuild() error {
+	if l.autoSync {
+		resync := l.changed.needsResync
+		l.changed.needsResync = nil
+
+		for _, s := range resync {
+			color.Default.Fprintf(l.out, "Syncing %d files for %s\n", len(s.item.Copy)+len(s.item.Delete), s.item.Image)
+
+			copied, deleted := syncedFiles(s.item)
+			event.SyncInProgress(s.artifact.ImageName, copied, deleted)
+			if err := l.runner.Syncer.Sync(l.ctx, l.out, s.item); err != nil {
+				event.SyncFailed(s.artifact.ImageName, copied, deleted, err)
+				if postSyncErr, ok := errors.Cause(err).(sync.PostSyncError); ok && postSyncErr.Rebuild {
+					logrus.Warnln("Rebuilding due to post-sync error:", err)
+					l.changed.AddRebuild(s.artifact)
+					continue
+				}
+
+				logrus.Warnln("Skipping deploy due to sync error:", err)
+				return err
+			}
+			event.SyncComplete(s.artifact.ImageName, copied, deleted)
+		}
+	}
+
+	if l.autoBuild && len(l.changed.needsRebuild) > 0 {
+		if _, err := l.runner.BuildAndTest(l.ctx, l.out, l.changed.needsRebuild); err != nil {
+			logrus.Warnln("Skipping deploy due to error:", err)
+			return err
+		}
+		l.changed.needsRebuild = nil
+		l.changed.needsRedeploy = true
+	}
+
+	return nil
+}
+
+// dropPending forgets the changes that failed to be applied, but keeps
+// those waiting for a phase that was turned off.
+func (l *devLoop) dropPending() {
+	if l.autoSync {
+		l.changed.needsResync = nil
+	}
+	if l.autoBuild {
+		l.changed.needsRebuild = nil
+	}
+	if l.autoDeploy {
+		l.changed.needsRedeploy = false
+	}
+}
+
+func (l *devLoop) build(artifacts []*latest.Artifact) error {
+	if _, err := l.runner.BuildAndTest(l.ctx, l.out, artifacts); err != nil {
+		logrus.Warnln("Skipping deploy due to error:", err)
+		return err
+	}
+	l.changed.RemoveArtifacts(artifacts)
+	l.changed.needsRedeploy = true
+
+	if !l.autoDeploy {
+		return nil
+	}
+	return l.deploy()
+}
+
+func (l *devLoop) deploy() error {
+	if err := l.runner.Deploy(l.ctx, l.out, l.runner.builds); err != nil {
+		logrus.Warnln("Skipping deploy due to error:", err)
+		return err
+	}
+	l.changed.needsRedeploy = false
+	return nil
+}
+
+// iterateInBackground runs, in the background, an iteration requested through the API.
+func (l *devLoop) iterateInBackground(apply func() error) {
+	background(func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+
+		l.start()
+		l.finish(apply())
+	})
+}
+
+// SetAutoBuild turns auto build on or off. Turning it on builds the pending artifacts.
+func (l *devLoop) SetAutoBuild(enabled bool) {
+	l.setAuto(&l.autoBuild, enabled)
+}
+
+// SetAutoSync turns auto sync on or off. Turning it on syncs the pending files.
+func (l *devLoop) SetAutoSync(enabled bool) {
+	l.setAuto(&l.autoSync, enabled)
+}
+
+// SetAutoDeploy turns auto deploy on or off. Turning it on applies a pending redeploy.
+func (l *devLoop) SetAutoDeploy(enabled bool) {
+	l.setAuto(&l.autoDeploy, enabled)
+}
+
+func (l *devLoop) setAuto(auto *bool, enabled bool) {
+	l.lock.Lock()
+	*auto = enabled
+	pending := l.changed.pending()
+	l.lock.Unlock()
+
+	if enabled && pending {
+		l.iterateInBackground(l.applyPending)
+	}
+}
+
+// BuildArtifacts builds the given artifacts, or all of them if none is given,
+// whether auto build is on or not. They are then deployed if auto deploy is on.
+func (l *devLoop) BuildArtifacts(imageNames []string) error {
+	artifacts, err := l.selectArtifacts(imageNames)
+	if err != nil {
+		return err
+	}
+
+	l.iterateInBackground(func() error {
+		return l.build(artifacts)
+	})
+	return nil
+}
+
+func (l *devLoop) selectArtifacts(imageNames []string) ([]*latest.Artifact, error) {
+	if len(imageNames) == 0 {
+		return l.artifacts, nil
+	}
+
+	var selected []*latest.Artifact
+	for _, imageName := range imageNames {
+		artifact := l.artifact(imageName)
+		if artifact == nil {
+			return nil, fmt.Errorf("unknown artifact %s", imageName)
+		}
+		selected = append(selected, artifact)
+	}
+	return selected, nil
+}
+
+func (l *devLoop) artifact(imageName string) *latest.Artifact {
+	for _, artifact := range l.artifacts {
+		if artifact.ImageName == imageName {
+			return artifact
+		}
+	}
+	return nil
+}
+
+// Redeploy deploys the latest builds, whether auto deploy is on or not.
+func (l *devLoop) Redeploy() {
+	l.iterateInBackground(l.deploy)
+}
+
+// PendingChanges lists the changes that are yet to be applied.
+func (l *devLoop) PendingChanges() *proto.PendingChanges {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	pending := &proto.PendingChanges{
+		AutoBuild:  l.autoBuild,
+		AutoSync:   l.autoSync,
+		AutoDeploy: l.autoDeploy,
+		Redeploy:   l.changed.needsRedeploy,
+	}
+	for _, artifact := range l.changed.needsRebuild {
+		pending.Rebuild = append(pending.Rebuild, artifact.ImageName)
+	}
+	for _, s := range l.changed.needsResync {
+		copied, deleted := syncedFiles(s.item)
+		pending.Sync = append(pending.Sync, &proto.PendingSync{
+			Artifact: s.artifact.ImageName,
+			Copied:   copied,
+			Deleted:  deleted,
+		})
+	}
+	return pending
+}
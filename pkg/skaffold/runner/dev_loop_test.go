@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+type NoopLogger struct{}
+
+func (l *NoopLogger) Start(context.Context) error { return nil }
+func (l *NoopLogger) Stop()                       {}
+func (l *NoopLogger) Mute()                       {}
+func (l *NoopLogger) Unmute()                     {}
+
+func newTestDevLoop(t *testutil.T, testBench *TestBench) *devLoop {
+	t.SetupFakeKubernetesContext(api.Config{CurrentContext: "cluster1"})
+	t.Override(&sync.WorkingDir, func(string, map[string]bool) (string, error) { return "/", nil })
+	t.Override(&background, func(fn func()) { fn() })
+
+	artifacts := []*latest.Artifact{
+		{
+			ImageName: "img1",
+			Sync: &latest.Sync{
+				Manual: []*latest.SyncRule{{Src: "file1", Dest: "file1"}},
+			},
+		},
+		{
+			ImageName: "img2",
+		},
+	}
+
+	runner := createRunner(t, testBench)
+	_, err := runner.BuildAndTest(context.Background(), ioutil.Discard, artifacts)
+	t.CheckNoError(err)
+	testBench.enterNewCycle()
+
+	loop := newDevLoop(context.Background(), ioutil.Discard, runner, &NoopLogger{}, artifacts)
+	loop.record(func(c *changes) {
+		c.AddDirtyArtifact(artifacts[0], watch.Events{Modified: []string{"file1"}})
+		c.AddDirtyArtifact(artifacts[1], watch.Events{Modified: []string{"file2"}})
+	})
+	return loop
+}
+
+func TestDevLoopAutoTriggers(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		testBench := &TestBench{}
+		loop := newTestDevLoop(t, testBench)
+		loop.SetAutoBuild(false)
+		loop.SetAutoSync(false)
+		loop.SetAutoDeploy(false)
+
+		err := loop.onChange()
+		t.CheckNoError(err)
+		t.CheckDeepEqual(&proto.PendingChanges{
+			Rebuild: []string{"img2"},
+			Sync:    []*proto.PendingSync{{Artifact: "img1", Copied: []string{"file1"}}},
+		}, loop.PendingChanges())
+
+		testBench.enterNewCycle()
+		loop.SetAutoSync(true)
+		testBench.enterNewCycle()
+		loop.SetAutoBuild(true)
+		t.CheckDeepEqual(&proto.PendingChanges{
+			AutoBuild: true,
+			AutoSync:  true,
+			Redeploy:  true,
+		}, loop.PendingChanges())
+
+		testBench.enterNewCycle()
+		loop.SetAutoDeploy(true)
+		t.CheckDeepEqual(&proto.PendingChanges{
+			AutoBuild:  true,
+			AutoSync:   true,
+			AutoDeploy: true,
+		}, loop.PendingChanges())
+
+		t.CheckDeepEqual([]Actions{
+			{
+				Built:  []string{"img1:1", "img2:1"},
+				Tested: []string{"img1:1", "img2:1"},
+			},
+			{},
+			{
+				Synced: []string{"img1:1"},
+			},
+			{
+				Built:  []string{"img2:2"},
+				Tested: []string{"img2:2"},
+			},
+			{
+				Deployed: []string{"img2:2", "img1:1"},
+			},
+		}, testBench.Actions())
+	})
+}
+
+func TestDevLoopBuildArtifacts(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		testBench := &TestBench{}
+		loop := newTestDevLoop(t, testBench)
+		loop.SetAutoBuild(false)
+		loop.SetAutoDeploy(false)
+
+		err := loop.onChange()
+		t.CheckNoError(err)
+
+		err = loop.BuildArtifacts([]string{"unknown"})
+		t.CheckErrorContains("unknown artifact unknown", err)
+
+		testBench.enterNewCycle()
+		err = loop.BuildArtifacts([]string{"img2"})
+		t.CheckNoError(err)
+		t.CheckDeepEqual(&proto.PendingChanges{
+			AutoSync: true,
+			Redeploy: true,
+		}, loop.PendingChanges())
+
+		testBench.enterNewCycle()
+		loop.Redeploy()
+		t.CheckDeepEqual(&proto.PendingChanges{
+			AutoSync: true,
+		}, loop.PendingChanges())
+
+		t.CheckDeepEqual([]Actions{
+			{
+				Built:  []string{"img1:1", "img2:1"},
+				Tested: []string{"img1:1", "img2:1"},
+			},
+			{
+				Synced: []string{"img1:1"},
+			},
+			{
+				Built:  []string{"img2:2"},
+				Tested: []string{"img2:2"},
+			},
+			{
+				Deployed: []string{"img2:2", "img1:1"},
+			},
+		}, testBench.Actions())
+	})
+}
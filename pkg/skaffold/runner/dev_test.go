@@ -320,6 +320,44 @@ func TestDevSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "post-sync failure triggers a rebuild",
+			testBench: &TestBench{syncErrors: []error{
+				sync.PostSyncError{Command: []string{"kill", "-HUP", "1"}, Rebuild: true},
+			}},
+			watchEvents: []watch.Events{
+				{Modified: []string{"file1"}},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{
+					Built:    []string{"img1:2"},
+					Tested:   []string{"img1:2"},
+					Deployed: []string{"img1:2", "img2:1"},
+				},
+			},
+		},
+		{
+			description: "post-sync failure without rebuild",
+			testBench: &TestBench{syncErrors: []error{
+				sync.PostSyncError{Command: []string{"kill", "-HUP", "1"}},
+			}},
+			watchEvents: []watch.Events{
+				{Modified: []string{"file1"}},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{},
+			},
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
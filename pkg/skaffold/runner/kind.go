@@ -30,7 +30,7 @@ import (
 )
 
 // loadImagesInKindNodes loads a list of artifact images into every node of kind cluster.
-func (r *SkaffoldRunner) loadImagesInKindNodes(ctx context.Context, out io.Writer, artifacts []build.Artifact) error {
+func (r *SkaffoldRunner) loadImagesInKindNodes(ctx context.Context, out io.Writer, kubeContext string, artifacts []build.Artifact) error {
 	start := time.Now()
 	color.Default.Fprintln(out, "Loading images into kind cluster nodes...")
 
@@ -47,7 +47,7 @@ func (r *SkaffoldRunner) loadImagesInKindNodes(ctx context.Context, out io.Write
 		// Only `kind load` the images that are unknown to the node
 		if knownImages == nil {
 			var err error
-			if knownImages, err = findKnownImages(ctx); err != nil {
+			if knownImages, err = findKnownImages(ctx, kubeContext); err != nil {
 				return errors.Wrapf(err, "unable to retrieve node's images")
 			}
 		}
@@ -69,8 +69,8 @@ func (r *SkaffoldRunner) loadImagesInKindNodes(ctx context.Context, out io.Write
 	return nil
 }
 
-func findKnownImages(ctx context.Context) ([]string, error) {
-	cmdNodeGet := exec.CommandContext(ctx, "kubectl", "get", "nodes", `-ojsonpath='{@.items[*].status.images[*].names[*]}'`)
+func findKnownImages(ctx context.Context, kubeContext string) ([]string, error) {
+	cmdNodeGet := exec.CommandContext(ctx, "kubectl", "--context", kubeContext, "get", "nodes", `-ojsonpath='{@.items[*].status.images[*].names[*]}'`)
 	nodeGetOut, err := util.RunCmdOut(cmdNodeGet)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to inspect the nodes")
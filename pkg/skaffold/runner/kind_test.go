@@ -41,7 +41,7 @@ func TestLoadImagesInKindNodes(t *testing.T) {
 			built:       []build.Artifact{{Tag: "tag1"}},
 			deployed:    []build.Artifact{{Tag: "tag1"}},
 			command: testutil.NewFakeCmd(t).
-				WithRunOut("kubectl get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "").
+				WithRunOut("kubectl --context kubernetes-admin@kind get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "").
 				WithRun("kind load docker-image tag1"),
 		},
 		{
@@ -49,7 +49,7 @@ func TestLoadImagesInKindNodes(t *testing.T) {
 			built:       []build.Artifact{{Tag: "tag1"}, {Tag: "tag2"}},
 			deployed:    []build.Artifact{{Tag: "tag1"}, {Tag: "tag2"}},
 			command: testutil.NewFakeCmd(t).
-				WithRunOut("kubectl get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "tag1").
+				WithRunOut("kubectl --context kubernetes-admin@kind get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "tag1").
 				WithRun("kind load docker-image tag2"),
 		},
 		{
@@ -57,7 +57,7 @@ func TestLoadImagesInKindNodes(t *testing.T) {
 			built:       []build.Artifact{{Tag: "tag"}},
 			deployed:    []build.Artifact{{Tag: "tag"}},
 			command: testutil.NewFakeCmd(t).
-				WithRunOutErr("kubectl get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "", errors.New("BUG")),
+				WithRunOutErr("kubectl --context kubernetes-admin@kind get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "", errors.New("BUG")),
 			shouldErr:     true,
 			expectedError: "unable to inspect",
 		},
@@ -66,7 +66,7 @@ func TestLoadImagesInKindNodes(t *testing.T) {
 			built:       []build.Artifact{{Tag: "tag"}},
 			deployed:    []build.Artifact{{Tag: "tag"}},
 			command: testutil.NewFakeCmd(t).
-				WithRunOut("kubectl get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "").
+				WithRunOut("kubectl --context kubernetes-admin@kind get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "").
 				WithRunErr("kind load docker-image tag", errors.New("BUG")),
 			shouldErr:     true,
 			expectedError: "unable to load",
@@ -76,7 +76,7 @@ func TestLoadImagesInKindNodes(t *testing.T) {
 			built:       []build.Artifact{{Tag: "built"}},
 			deployed:    []build.Artifact{{Tag: "built"}, {Tag: "busybox"}},
 			command: testutil.NewFakeCmd(t).
-				WithRunOut("kubectl get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "").
+				WithRunOut("kubectl --context kubernetes-admin@kind get nodes -ojsonpath='{@.items[*].status.images[*].names[*]}'", "").
 				WithRun("kind load docker-image built"),
 		},
 		{
@@ -99,7 +99,7 @@ func TestLoadImagesInKindNodes(t *testing.T) {
 			r := &SkaffoldRunner{
 				builds: test.built,
 			}
-			err := r.loadImagesInKindNodes(context.Background(), ioutil.Discard, test.deployed)
+			err := r.loadImagesInKindNodes(context.Background(), ioutil.Discard, "kubernetes-admin@kind", test.deployed)
 
 			if test.shouldErr {
 				t.CheckErrorContains(test.expectedError, err)
@@ -17,13 +17,24 @@ limitations under the License.
 package runner
 
 import (
+	"context"
 	"io"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 )
 
-func (r *SkaffoldRunner) newLogger(out io.Writer, artifacts []*latest.Artifact) *kubernetes.LogAggregator {
+// logger tails the logs of the deployed containers.
+type logger interface {
+	Start(context.Context) error
+	Stop()
+	Mute()
+	Unmute()
+}
+
+func (r *SkaffoldRunner) newLogger(out io.Writer, artifacts []*latest.Artifact) logger {
 	var imageNames []string
 	for _, artifact := range artifacts {
 		imageNames = append(imageNames, artifact.ImageName)
@@ -31,6 +42,9 @@ func (r *SkaffoldRunner) newLogger(out io.Writer, artifacts []*latest.Artifact)
 	return r.newLoggerForImages(out, imageNames)
 }
 
-func (r *SkaffoldRunner) newLoggerForImages(out io.Writer, images []string) *kubernetes.LogAggregator {
-	return kubernetes.NewLogAggregator(out, images, r.imageList, r.runCtx.Namespaces)
+func (r *SkaffoldRunner) newLoggerForImages(out io.Writer, images []string) logger {
+	if r.localDocker != nil {
+		return docker.NewLogAggregator(out, r.localDocker, images, deploy.DockerContainerLabels(), r.logPrinter, r.logArchive)
+	}
+	return kubernetes.NewLogAggregator(out, images, r.tailSelector, kubeContextNamespaces(r.targets), r.logPrinter, r.logArchive, r.podDiagnoser)
 }
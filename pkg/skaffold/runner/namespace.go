@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	runnerutil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// For testing
+var (
+	createNamespace = kubernetes.CreateNamespace
+	deleteNamespace = kubernetes.DeleteNamespace
+)
+
+// Cleanup deletes what was deployed. The ephemeral namespace, and everything in it,
+// is only deleted at the end of the run that deployed to it, or when explicitly asked.
+func (r *SkaffoldRunner) Cleanup(ctx context.Context, out io.Writer) error {
+	err := r.Deployer.Cleanup(ctx, out)
+
+	if r.hasEphemeralNamespaces || r.runCtx.Opts.DeleteNamespace {
+		if err := r.deleteEphemeralNamespaces(out); err != nil {
+			logrus.Warnln("deleting ephemeral namespace:", err)
+		}
+	}
+
+	return err
+}
+
+// createEphemeralNamespaces makes sure the ephemeral namespace exists in each kubectl context.
+func (r *SkaffoldRunner) createEphemeralNamespaces(out io.Writer) error {
+	namespace := r.runCtx.EphemeralNamespace
+	if namespace == "" || r.hasEphemeralNamespaces {
+		return nil
+	}
+
+	labels := map[string]string{
+		constants.Labels.EphemeralNamespace: "true",
+		constants.Labels.Owner:              namespaceOwner(),
+	}
+	for k, v := range r.defaultLabeller.Labels() {
+		labels[k] = v
+	}
+
+	for _, target := range r.targets {
+		created, err := createNamespace(target.KubeContext, namespace, labels)
+		if err != nil {
+			return errors.Wrapf(err, "creating namespace %s in context %s", namespace, target.KubeContext)
+		}
+		if created {
+			color.Default.Fprintf(out, "Created namespace %s in context %s\n", namespace, target.KubeContext)
+		}
+	}
+
+	r.hasEphemeralNamespaces = true
+	return nil
+}
+
+// deleteEphemeralNamespaces deletes the ephemeral namespace from each kubectl context,
+// provided it was created by skaffold for the current user.
+func (r *SkaffoldRunner) deleteEphemeralNamespaces(out io.Writer) error {
+	namespace := r.runCtx.EphemeralNamespace
+	if namespace == "" {
+		return nil
+	}
+
+	labels := map[string]string{
+		constants.Labels.EphemeralNamespace: "true",
+		constants.Labels.Owner:              namespaceOwner(),
+	}
+
+	for _, target := range r.targets {
+		deleted, err := deleteNamespace(target.KubeContext, namespace, labels)
+		if err != nil {
+			return errors.Wrapf(err, "deleting namespace %s in context %s", namespace, target.KubeContext)
+		}
+		if deleted {
+			color.Default.Fprintf(out, "Deleted namespace %s in context %s\n", namespace, target.KubeContext)
+		}
+	}
+
+	r.hasEphemeralNamespaces = false
+	return nil
+}
+
+func namespaceOwner() string {
+	return runnerutil.SanitizeNamespace(runnerutil.CurrentUser())
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestEphemeralNamespaces(t *testing.T) {
+	tests := []struct {
+		description      string
+		namespace        string
+		deploy           bool
+		deleteNamespace  bool
+		expectedCreated  []string
+		expectedDeleted  []string
+		expectedLabels   map[string]string
+		expectedSelector map[string]string
+	}{
+		{
+			description: "no ephemeral namespace",
+		},
+		{
+			description:     "ephemeral namespace in each context",
+			namespace:       "jane-feature",
+			deploy:          true,
+			expectedCreated: []string{"cluster1/jane-feature", "cluster2/jane-feature"},
+			expectedDeleted: []string{"cluster1/jane-feature", "cluster2/jane-feature"},
+			expectedLabels: map[string]string{
+				"app.kubernetes.io/managed-by":     "skaffold-test",
+				"skaffold.dev/ephemeral-namespace": "true",
+				"skaffold.dev/owner":               namespaceOwner(),
+			},
+			expectedSelector: map[string]string{
+				"skaffold.dev/ephemeral-namespace": "true",
+				"skaffold.dev/owner":               namespaceOwner(),
+			},
+		},
+		{
+			description: "delete keeps the namespace",
+			namespace:   "jane-feature",
+		},
+		{
+			description:     "delete the namespace explicitly",
+			namespace:       "jane-feature",
+			deleteNamespace: true,
+			expectedDeleted: []string{"cluster1/jane-feature", "cluster2/jane-feature"},
+			expectedSelector: map[string]string{
+				"skaffold.dev/ephemeral-namespace": "true",
+				"skaffold.dev/owner":               namespaceOwner(),
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var created, deleted []string
+			var labels, selector map[string]string
+			t.Override(&createNamespace, func(kubeContext, name string, l map[string]string) (bool, error) {
+				created = append(created, kubeContext+"/"+name)
+				labels = l
+				return true, nil
+			})
+			t.Override(&deleteNamespace, func(kubeContext, name string, l map[string]string) (bool, error) {
+				deleted = append(deleted, kubeContext+"/"+name)
+				selector = l
+				return true, nil
+			})
+
+			runCtx := &runcontext.RunContext{
+				EphemeralNamespace: test.namespace,
+				Opts:               &config.SkaffoldOptions{DeleteNamespace: test.deleteNamespace},
+			}
+			r := &SkaffoldRunner{
+				Deployer:        &TestBench{},
+				runCtx:          runCtx,
+				targets:         []*runcontext.RunContext{{KubeContext: "cluster1"}, {KubeContext: "cluster2"}},
+				defaultLabeller: deploy.NewLabeller("test"),
+			}
+
+			// Namespaces are created only once
+			if test.deploy {
+				t.CheckNoError(r.createEphemeralNamespaces(ioutil.Discard))
+				t.CheckNoError(r.createEphemeralNamespaces(ioutil.Discard))
+			}
+			t.CheckNoError(r.Cleanup(context.Background(), ioutil.Discard))
+
+			t.CheckDeepEqual(test.expectedCreated, created)
+			t.CheckDeepEqual(test.expectedDeleted, deleted)
+			t.CheckDeepEqual(test.expectedLabels, labels)
+			t.CheckDeepEqual(test.expectedSelector, selector)
+		})
+	}
+}
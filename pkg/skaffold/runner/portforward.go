@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/portforward"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/sirupsen/logrus"
+)
+
+// newForwarderManager port-forwards the user defined resources and the
+// deployed services, in each kubectl context.
+func (r *SkaffoldRunner) newForwarderManager(out io.Writer) *portforward.ForwarderManager {
+	// Containers run on the local Docker daemon publish their ports themselves.
+	namespaces := kubeContextNamespaces(r.targets)
+	if r.localDocker != nil {
+		namespaces = nil
+	}
+
+	userDefined := userDefinedPortForwards(r.targets, r.runCtx.Cfg.PortForward)
+
+	return portforward.NewForwarderManager(out, r.imageList, namespaces, r.defaultLabeller.K8sManagedByLabelKeyValueString(), r.runCtx.Opts.PortForward, userDefined)
+}
+
+// userDefinedPortForwards returns, for each kubectl context, the resources to port-forward.
+// Each resource is forwarded in a single context, so that its local port is only taken once.
+// Resources without a namespace are looked for in the namespace Skaffold deploys to.
+func userDefinedPortForwards(targets []*runcontext.RunContext, resources []*latest.PortForwardResource) map[string][]*latest.PortForwardResource {
+	userDefined := map[string][]*latest.PortForwardResource{}
+	if len(targets) == 0 {
+		return userDefined
+	}
+
+	for _, resource := range resources {
+		target := targets[0]
+		if resource.KubeContext != "" {
+			target = findTarget(targets, resource.KubeContext)
+		}
+		if target == nil {
+			logrus.Warnf("Not port forwarding %s/%s: Skaffold doesn't deploy to kubectl context %q", resource.Type, resource.Name, resource.KubeContext)
+			continue
+		}
+
+		r := *resource
+		if r.Namespace == "" {
+			r.Namespace = target.Opts.Namespace
+		}
+		if r.Namespace == "" {
+			r.Namespace = "default"
+		}
+		userDefined[target.KubeContext] = append(userDefined[target.KubeContext], &r)
+	}
+
+	return userDefined
+}
+
+func findTarget(targets []*runcontext.RunContext, kubeContext string) *runcontext.RunContext {
+	for _, target := range targets {
+		if target.KubeContext == kubeContext {
+			return target
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestUserDefinedPortForwards(t *testing.T) {
+	targets := []*runcontext.RunContext{
+		{KubeContext: "cluster1", Opts: &config.SkaffoldOptions{}},
+		{KubeContext: "cluster2", Opts: &config.SkaffoldOptions{Namespace: "ns2"}},
+	}
+	resources := []*latest.PortForwardResource{
+		{Type: "deployment", Name: "web", Port: 8080},
+		{Type: "service", Name: "db", Namespace: "data", Port: 5432, LocalPort: 15432, Address: "0.0.0.0"},
+		{Type: "service", Name: "cache", Port: 6379, KubeContext: "cluster2"},
+		{Type: "service", Name: "queue", Port: 5672, KubeContext: "unknown"},
+	}
+
+	userDefined := userDefinedPortForwards(targets, resources)
+
+	testutil.CheckDeepEqual(t, map[string][]*latest.PortForwardResource{
+		"cluster1": {
+			{Type: "deployment", Name: "web", Namespace: "default", Port: 8080},
+			{Type: "service", Name: "db", Namespace: "data", Port: 5432, LocalPort: 15432, Address: "0.0.0.0"},
+		},
+		"cluster2": {
+			{Type: "service", Name: "cache", Namespace: "ns2", Port: 6379, KubeContext: "cluster2"},
+		},
+	}, userDefined)
+	testutil.CheckDeepEqual(t, "", resources[0].Namespace)
+}
@@ -32,18 +32,23 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runlog"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	dockersync "github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/native"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // Runner is responsible for running the skaffold build, test and deploy config.
@@ -68,15 +73,23 @@ type SkaffoldRunner struct {
 	sync.Syncer
 	watch.Watcher
 
-	cache             *cache.Cache
-	runCtx            *runcontext.RunContext
-	labellers         []deploy.Labeller
-	defaultLabeller   *deploy.DefaultLabeller
-	builds            []build.Artifact
-	hasBuilt          bool
-	hasDeployed       bool
-	imageList         *kubernetes.ImageList
-	RPCServerShutdown func() error
+	cache           *cache.Cache
+	runCtx          *runcontext.RunContext
+	targets         []*runcontext.RunContext
+	labellers       []deploy.Labeller
+	defaultLabeller *deploy.DefaultLabeller
+	builds          []build.Artifact
+	hasBuilt        bool
+	hasDeployed     bool
+
+	hasEphemeralNamespaces bool
+	localDocker            docker.LocalDaemon
+	imageList              *kubernetes.ImageList
+	tailSelector           *kubernetes.TailSelector
+	logPrinter             *kubernetes.LogPrinter
+	logArchive             *runlog.Archive
+	podDiagnoser           *kubernetes.PodDiagnoser
+	RPCServerShutdown      func() error
 }
 
 // NewForConfig returns a new SkaffoldRunner for a SkaffoldConfig
@@ -86,6 +99,11 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldConfig) (*Sk
 		return nil, errors.Wrap(err, "getting run context")
 	}
 
+	targets, err := runCtx.Targets()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting kubectl contexts")
+	}
+
 	tagger, err := getTagger(cfg.Build.TagPolicy, opts.CustomTag)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing tag config")
@@ -99,13 +117,41 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldConfig) (*Sk
 
 	tester := getTester(runCtx)
 
-	deployer, err := getDeployer(runCtx)
+	// The docker deployer, its syncer and its logger share the same client.
+	var localDocker docker.LocalDaemon
+	if runCtx.Cfg.Deploy.DockerDeploy != nil {
+		localDocker, err = docker.NewAPIClient(opts.Prune(), runCtx.InsecureRegistries)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting docker client")
+		}
+	}
+
+	deployer, err := getDeployers(targets, localDocker)
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing deploy config")
 	}
 
+	runIDLabeller, err := deploy.NewRunIDLabeller()
+	if err != nil {
+		return nil, errors.Wrap(err, "generating run id")
+	}
+
+	// The run id only labels the deployed objects, not their pods, so that
+	// redeploying doesn't roll out pods that didn't change.
 	defaultLabeller := deploy.NewLabeller("")
-	labellers := []deploy.Labeller{opts, builder, deployer, tagger, defaultLabeller}
+	runSelectors := []labels.Selector{labels.SelectorFromSet(defaultLabeller.Labels())}
+	var syncer sync.Syncer = native.NewSyncer(defaultLabeller.Labels(), kubeContextNamespaces(targets))
+	switch {
+	case runCtx.Cfg.Deploy.DockerDeploy != nil:
+		syncer = dockersync.NewSyncer(localDocker, deploy.DockerContainerLabels())
+
+	case runCtx.Cfg.Deploy.HelmDeploy != nil:
+		// Helm labels the released resources, but not their pods.
+		syncer = kubectl.NewSyncer(kubeContextNamespaces(targets))
+		runSelectors = append(runSelectors, deploy.HelmReleaseSelectors(runCtx.Cfg.Deploy.HelmDeploy.Releases)...)
+	}
+
+	labellers := []deploy.Labeller{runCtx.Opts, builder, deployer, tagger, defaultLabeller, runIDLabeller}
 
 	builder, tester, deployer = WithTimings(builder, tester, deployer, opts.CacheArtifacts)
 	if opts.Notification {
@@ -117,25 +163,56 @@ func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldConfig) (*Sk
 		return nil, errors.Wrap(err, "creating watch trigger")
 	}
 
+	ignorer, err := watch.NewIgnorer(runCtx.WorkingDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading ignore files")
+	}
+
+	imageList := kubernetes.NewImageList()
+	tailSelector, err := kubernetes.NewTailSelector(imageList, opts.Logs, runSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing log selectors")
+	}
+
+	logPrinter, err := kubernetes.NewLogPrinter(opts.Logs)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing log options")
+	}
+
 	shutdown, err := server.Initialize(opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing skaffold server")
 	}
+	server.RegisterLogFilterer(logPrinter)
 	event.InitializeState(runCtx)
 	event.LogSkaffoldMetadata(version.Get())
 
+	logArchive, err := runlog.New(opts.Logs.Dir, runIDLabeller.RunID(), int64(opts.Logs.MaxSize)*1024*1024, opts.Logs.MaxFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating log archive")
+	}
+	if err := logArchive.RecordEvents(); err != nil {
+		return nil, errors.Wrap(err, "recording events")
+	}
+
 	return &SkaffoldRunner{
 		Builder:           builder,
 		Tester:            tester,
 		Deployer:          deployer,
 		Tagger:            tagger,
-		Syncer:            kubectl.NewSyncer(runCtx.Namespaces),
-		Watcher:           watch.NewWatcher(trigger),
+		Syncer:            syncer,
+		Watcher:           watch.NewWatcher(trigger, ignorer),
 		labellers:         labellers,
 		defaultLabeller:   defaultLabeller,
-		imageList:         kubernetes.NewImageList(),
+		imageList:         imageList,
+		tailSelector:      tailSelector,
+		logPrinter:        logPrinter,
+		logArchive:        logArchive,
+		podDiagnoser:      kubernetes.NewPodDiagnoser(runSelectors),
 		cache:             artifactCache,
 		runCtx:            runCtx,
+		targets:           targets,
+		localDocker:       localDocker,
 		RPCServerShutdown: shutdown,
 	}, nil
 }
@@ -163,7 +240,25 @@ func getTester(runCtx *runcontext.RunContext) test.Tester {
 	return test.NewTester(runCtx)
 }
 
-func getDeployer(runCtx *runcontext.RunContext) (deploy.Deployer, error) {
+// getDeployers returns a deployer for each kubectl context to deploy to.
+// localDocker is the client of the docker deployer.
+func getDeployers(targets []*runcontext.RunContext, localDocker docker.LocalDaemon) (deploy.Deployer, error) {
+	var deployers deploy.DeployerMux
+	for _, target := range targets {
+		deployer, err := getDeployer(target, localDocker)
+		if err != nil {
+			return nil, err
+		}
+		deployers = append(deployers, deployer)
+	}
+
+	if len(deployers) == 1 {
+		return deployers[0], nil
+	}
+	return deployers, nil
+}
+
+func getDeployer(runCtx *runcontext.RunContext, localDocker docker.LocalDaemon) (deploy.Deployer, error) {
 	switch {
 	case runCtx.Cfg.Deploy.HelmDeploy != nil:
 		return deploy.NewHelmDeployer(runCtx), nil
@@ -174,6 +269,12 @@ func getDeployer(runCtx *runcontext.RunContext) (deploy.Deployer, error) {
 	case runCtx.Cfg.Deploy.KustomizeDeploy != nil:
 		return deploy.NewKustomizeDeployer(runCtx), nil
 
+	case runCtx.Cfg.Deploy.DockerDeploy != nil:
+		return deploy.NewDockerDeployer(runCtx, localDocker), nil
+
+	case runCtx.Cfg.Deploy.RenderDeploy != nil:
+		return deploy.NewRenderDeployer(runCtx), nil
+
 	default:
 		return nil, fmt.Errorf("unknown deployer for config %+v", runCtx.Cfg.Deploy)
 	}
@@ -204,18 +305,33 @@ func getTagger(t latest.TagPolicy, customTag string) (tag.Tagger, error) {
 }
 
 func (r *SkaffoldRunner) Deploy(ctx context.Context, out io.Writer, artifacts []build.Artifact) error {
-	if cfg.IsKindCluster(r.runCtx.KubeContext) {
-		// With `kind`, docker images have to be loaded with the `kind` CLI.
-		if err := r.loadImagesInKindNodes(ctx, out, artifacts); err != nil {
-			return errors.Wrapf(err, "loading images into kind nodes")
+	for _, target := range r.targets {
+		if cfg.IsKindCluster(target.KubeContext) {
+			// With `kind`, docker images have to be loaded with the `kind` CLI.
+			if err := r.loadImagesInKindNodes(ctx, out, target.KubeContext, artifacts); err != nil {
+				return errors.Wrapf(err, "loading images into kind nodes")
+			}
 		}
 	}
 
+	if err := r.createEphemeralNamespaces(out); err != nil {
+		return errors.Wrap(err, "creating ephemeral namespace")
+	}
+
 	err := r.Deployer.Deploy(ctx, out, artifacts, r.labellers)
 	r.hasDeployed = true
 	return err
 }
 
+// kubeContextNamespaces lists, for each kubectl context to deploy to, the namespaces to watch for pods.
+func kubeContextNamespaces(targets []*runcontext.RunContext) map[string][]string {
+	namespaces := make(map[string][]string, len(targets))
+	for _, target := range targets {
+		namespaces[target.KubeContext] = target.Namespaces
+	}
+	return namespaces
+}
+
 // HasDeployed returns true if this runner has deployed something.
 func (r *SkaffoldRunner) HasDeployed() bool {
 	return r.hasDeployed
@@ -277,5 +393,11 @@ func (r *SkaffoldRunner) imageTags(ctx context.Context, out io.Writer, artifacts
 
 // Stop stops the runner.
 func (r *SkaffoldRunner) Stop() error {
+	if syncer, ok := r.Syncer.(interface{ Stop() }); ok {
+		syncer.Stop()
+	}
+	if err := r.logArchive.Close(); err != nil {
+		logrus.Warnln("closing log archive:", err)
+	}
 	return r.RPCServerShutdown()
 }
@@ -93,7 +93,7 @@ func (t *TestBench) Build(ctx context.Context, w io.Writer, tags tag.ImageTags,
 	return builds, nil
 }
 
-func (t *TestBench) Sync(ctx context.Context, item *sync.Item) error {
+func (t *TestBench) Sync(ctx context.Context, out io.Writer, item *sync.Item) error {
 	if len(t.syncErrors) > 0 {
 		err := t.syncErrors[0]
 		t.syncErrors = t.syncErrors[1:]
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxNamespaceLength is the maximum length of a DNS-1123 label.
+const maxNamespaceLength = 63
+
+var (
+	invalidNamespaceChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+	// For testing
+	currentUser = userName
+)
+
+// EphemeralNamespace computes the name of a per-developer namespace from a template
+// that can use environment variables, `USER`, `GIT_BRANCH` and `GIT_COMMIT`.
+func EphemeralNamespace(template string, workingDir string) (string, error) {
+	tmpl, err := util.ParseEnvTemplate(template)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing namespace template %s", template)
+	}
+
+	name, err := util.ExecuteEnvTemplate(tmpl, map[string]string{
+		"USER":       CurrentUser(),
+		"GIT_BRANCH": gitOutput(workingDir, "rev-parse", "--abbrev-ref", "HEAD"),
+		"GIT_COMMIT": gitOutput(workingDir, "rev-parse", "--short", "HEAD"),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "executing namespace template %s", template)
+	}
+
+	namespace := SanitizeNamespace(name)
+	if namespace == "" {
+		return "", fmt.Errorf("namespace template %s produced an empty name", template)
+	}
+	return namespace, nil
+}
+
+// CurrentUser returns the name of the user running skaffold.
+func CurrentUser() string {
+	return currentUser()
+}
+
+// SanitizeNamespace turns a string into a valid namespace name: lowercase alphanumeric
+// characters separated by `-`, at most 63 characters long.
+func SanitizeNamespace(name string) string {
+	namespace := invalidNamespaceChars.ReplaceAllString(strings.ToLower(name), "-")
+	namespace = strings.Trim(namespace, "-")
+	if len(namespace) > maxNamespaceLength {
+		namespace = strings.TrimRight(namespace[:maxNamespaceLength], "-")
+	}
+	return namespace
+}
+
+func userName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		// On Windows, the user name is prefixed with the domain.
+		parts := strings.Split(u.Username, `\`)
+		return parts[len(parts)-1]
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+func gitOutput(workingDir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workingDir
+
+	out, err := util.RunCmdOut(cmd)
+	if err != nil {
+		logrus.Warnf("Unable to run git %s in %s: %s", strings.Join(args, " "), workingDir, err)
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
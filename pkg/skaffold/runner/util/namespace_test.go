@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestEphemeralNamespace(t *testing.T) {
+	tests := []struct {
+		description string
+		template    string
+		command     util.Command
+		env         []string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "user and branch",
+			template:    "{{.USER}}-{{.GIT_BRANCH}}",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("git rev-parse --abbrev-ref HEAD", "feature/Login_Page\n").
+				WithRunOut("git rev-parse --short HEAD", "abc1234\n"),
+			expected: "jane-doe-feature-login-page",
+		},
+		{
+			description: "commit and environment variable",
+			template:    "{{.TEAM}}-{{.GIT_COMMIT}}",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("git rev-parse --abbrev-ref HEAD", "master").
+				WithRunOut("git rev-parse --short HEAD", "abc1234"),
+			env:      []string{"TEAM=payments"},
+			expected: "payments-abc1234",
+		},
+		{
+			description: "not a git repository",
+			template:    "{{.USER}}-{{.GIT_BRANCH}}",
+			command: testutil.NewFakeCmd(t).
+				WithRunOutErr("git rev-parse --abbrev-ref HEAD", "", errors.New("not a git repository")).
+				WithRunOutErr("git rev-parse --short HEAD", "", errors.New("not a git repository")),
+			expected: "jane-doe",
+		},
+		{
+			description: "empty name",
+			template:    "{{.GIT_BRANCH}}",
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("git rev-parse --abbrev-ref HEAD", "___").
+				WithRunOut("git rev-parse --short HEAD", "abc1234"),
+			shouldErr: true,
+		},
+		{
+			description: "invalid template",
+			template:    "{{.USER",
+			command:     testutil.NewFakeCmd(t),
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&util.DefaultExecCommand, test.command)
+			t.Override(&util.OSEnviron, func() []string { return test.env })
+			t.Override(&currentUser, func() string { return "Jane.Doe" })
+
+			namespace, err := EphemeralNamespace(test.template, ".")
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, namespace)
+		})
+	}
+}
+
+func TestSanitizeNamespace(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		expected    string
+	}{
+		{
+			description: "valid name",
+			name:        "dev-123",
+			expected:    "dev-123",
+		},
+		{
+			description: "invalid characters",
+			name:        "--John.Smith@Feature/ABC--",
+			expected:    "john-smith-feature-abc",
+		},
+		{
+			description: "too long",
+			name:        "a-very-long-branch-name-that-goes-on-and-on-and-on-without-an-end-in-sight",
+			expected:    "a-very-long-branch-name-that-goes-on-and-on-and-on-without-an-e",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.CheckDeepEqual(test.expected, SanitizeNamespace(test.name))
+		})
+	}
+}
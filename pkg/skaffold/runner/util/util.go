@@ -21,7 +21,9 @@ import (
 	"github.com/pkg/errors"
 )
 
-func GetAllPodNamespaces(configNamespace string) ([]string, error) {
+// GetAllPodNamespaces lists the namespaces to watch for pods in a kubectl context.
+// An empty context name selects the current context.
+func GetAllPodNamespaces(kubeContext, configNamespace string) ([]string, error) {
 	// We also get the default namespace.
 	nsMap := make(map[string]bool)
 	if configNamespace == "" {
@@ -29,7 +31,10 @@ func GetAllPodNamespaces(configNamespace string) ([]string, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "getting k8s configuration")
 		}
-		context, ok := config.Contexts[config.CurrentContext]
+		if kubeContext == "" {
+			kubeContext = config.CurrentContext
+		}
+		context, ok := config.Contexts[kubeContext]
 		if ok {
 			nsMap[context.Namespace] = true
 		} else {
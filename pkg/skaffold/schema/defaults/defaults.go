@@ -35,6 +35,8 @@ func Set(c *latest.SkaffoldConfig) error {
 	setDefaultTagger(c)
 	setDefaultKustomizePath(c)
 	setDefaultKubectlManifests(c)
+	setDefaultEphemeralNamespaceTemplate(c)
+	setDefaultDockerNetwork(c)
 
 	withCloudBuildConfig(c,
 		SetDefaultCloudBuildDockerImage,
@@ -119,13 +121,31 @@ func setDefaultTagger(c *latest.SkaffoldConfig) {
 
 func setDefaultKustomizePath(c *latest.SkaffoldConfig) {
 	kustomize := c.Deploy.KustomizeDeploy
-	if kustomize == nil {
+	if kustomize == nil || len(kustomize.KustomizePaths) > 0 {
 		return
 	}
 
 	kustomize.KustomizePath = valueOrDefault(kustomize.KustomizePath, constants.DefaultKustomizationPath)
 }
 
+func setDefaultEphemeralNamespaceTemplate(c *latest.SkaffoldConfig) {
+	namespace := c.Deploy.EphemeralNamespace
+	if namespace == nil {
+		return
+	}
+
+	namespace.Template = valueOrDefault(namespace.Template, constants.DefaultEphemeralNamespaceTemplate)
+}
+
+func setDefaultDockerNetwork(c *latest.SkaffoldConfig) {
+	docker := c.Deploy.DockerDeploy
+	if docker == nil {
+		return
+	}
+
+	docker.Network = valueOrDefault(docker.Network, constants.DefaultDockerNetwork)
+}
+
 func setDefaultKubectlManifests(c *latest.SkaffoldConfig) {
 	if c.Deploy.KubectlDeploy != nil && len(c.Deploy.KubectlDeploy.Manifests) == 0 {
 		c.Deploy.KubectlDeploy.Manifests = constants.DefaultKubectlManifests
@@ -112,3 +112,18 @@ func TestSetDefaultsOnCloudBuild(t *testing.T) {
 	testutil.CheckDeepEqual(t, constants.DefaultCloudBuildMavenImage, cfg.Build.GoogleCloudBuild.MavenImage)
 	testutil.CheckDeepEqual(t, constants.DefaultCloudBuildGradleImage, cfg.Build.GoogleCloudBuild.GradleImage)
 }
+
+func TestSetDefaultsOnEphemeralNamespace(t *testing.T) {
+	cfg := &latest.SkaffoldConfig{
+		Pipeline: latest.Pipeline{
+			Deploy: latest.DeployConfig{
+				EphemeralNamespace: &latest.EphemeralNamespace{},
+			},
+		},
+	}
+
+	err := Set(cfg)
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, constants.DefaultEphemeralNamespaceTemplate, cfg.Deploy.EphemeralNamespace.Template)
+}
@@ -52,6 +52,9 @@ type Pipeline struct {
 
 	// Deploy describes how images are deployed.
 	Deploy DeployConfig `yaml:"deploy,omitempty"`
+
+	// PortForward describes user defined resources to port-forward.
+	PortForward []*PortForwardResource `yaml:"portForward,omitempty"`
 }
 
 func (c *SkaffoldConfig) GetVersion() string {
@@ -65,19 +68,27 @@ type ResourceType string
 type PortForwardResource struct {
 	// Type is the Kubernetes type that should be port forwarded.
 	// Acceptable resource types include: `Service`, `Pod` and Controller resource type that has a pod spec: `ReplicaSet`, `ReplicationController`, `Deployment`, `StatefulSet`, `DaemonSet`, `Job`, `CronJob`.
-	Type ResourceType `yaml:"resourceType,omitempty"`
+	Type ResourceType `yaml:"resourceType,omitempty" yamltags:"required"`
 
 	// Name is the name of the Kubernetes resource to port forward.
-	Name string `yaml:"resourceName,omitempty"`
+	Name string `yaml:"resourceName,omitempty" yamltags:"required"`
 
 	// Namespace is the namespace of the resource to port forward.
+	// Defaults to the namespace Skaffold deploys to.
 	Namespace string `yaml:"namespace,omitempty"`
 
 	// Port is the resource port that will be forwarded.
-	Port int32 `yaml:"port,omitempty"`
+	Port int32 `yaml:"port,omitempty" yamltags:"required"`
+
+	// Address is the local address to bind to. Defaults to the loopback address `127.0.0.1`.
+	Address string `yaml:"address,omitempty"`
 
 	// LocalPort is the local port to forward to. If the port is unavailable, Skaffold will choose a random open port to forward to. *Optional*.
 	LocalPort int32 `yaml:"localPort,omitempty"`
+
+	// KubeContext is the kubectl context of the resource, among the contexts Skaffold deploys to.
+	// Defaults to the first of these contexts.
+	KubeContext string `yaml:"kubeContext,omitempty"`
 }
 
 // BuildConfig contains all the configuration for the build steps.
@@ -319,6 +330,75 @@ type TestCase struct {
 // DeployConfig contains all the configuration needed by the deploy steps.
 type DeployConfig struct {
 	DeployType `yaml:",inline"`
+
+	// ImageFields lists fields of Kubernetes resources, including custom resources,
+	// that hold image names to be replaced with the tags of the built images.
+	// Fields named `image` are always replaced.
+	ImageFields []ImageField `yaml:"imageFields,omitempty"`
+
+	// KubeContexts lists the kubectl contexts to deploy to.
+	// Defaults to the current kubectl context.
+	KubeContexts []KubeContext `yaml:"kubeContexts,omitempty"`
+
+	// EphemeralNamespace deploys to a per-developer namespace, created before
+	// deploying if missing. Ignored when `--namespace` is set.
+	// Can't be combined with namespaces set in `kubeContexts`.
+	EphemeralNamespace *EphemeralNamespace `yaml:"ephemeralNamespace,omitempty"`
+
+	// Validation validates the manifests against the Kubernetes API schemas
+	// before anything is deployed.
+	Validation *ManifestValidation `yaml:"validation,omitempty"`
+}
+
+// ManifestValidation configures the offline validation of manifests.
+type ManifestValidation struct {
+	// KubernetesVersion is the version of the Kubernetes API the manifests are validated against.
+	// Only the version bundled with Skaffold, which is also the default, is supported.
+	KubernetesVersion string `yaml:"kubernetesVersion,omitempty"`
+
+	// CRDs lists files holding the CustomResourceDefinitions used to validate custom resources.
+	// For example: `["crds/*.yaml"]`.
+	CRDs []string `yaml:"crds,omitempty"`
+}
+
+// EphemeralNamespace describes a per-developer namespace.
+type EphemeralNamespace struct {
+	// Template is used to produce the name of the namespace.
+	// Besides environment variables, the template can use
+	// `USER`, `GIT_BRANCH` and `GIT_COMMIT` (abbreviated commit hash).
+	// The name is lowercased and characters not allowed in a namespace name are replaced with `-`.
+	// Defaults to `{{.USER}}-{{.GIT_BRANCH}}`.
+	Template string `yaml:"template,omitempty"`
+}
+
+// KubeContext describes a kubectl context to deploy to.
+type KubeContext struct {
+	// Name is the name of the kubectl context.
+	Name string `yaml:"name,omitempty" yamltags:"required"`
+
+	// Namespace is the namespace to deploy to in that context.
+	// Defaults to the namespace of the context. Overridden by `--namespace`.
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// ImageField describes fields of Kubernetes resources that hold image names.
+type ImageField struct {
+	// Kind is the kind of the resources, eg. `Rollout` or `Task`.
+	// Use `*` to match resources of any kind.
+	Kind string `yaml:"kind,omitempty" yamltags:"required"`
+
+	// Group restricts the resources to an API group, eg. `serving.knative.dev`.
+	// Defaults to any group.
+	Group string `yaml:"group,omitempty"`
+
+	// Keys are names of fields, at any depth in the resources, that hold image names.
+	// For example: `[containerImage]`.
+	Keys []string `yaml:"keys,omitempty"`
+
+	// Paths are paths to fields that hold image names, with `.` separating field names
+	// and `*` matching any field name or list index.
+	// For example: `[spec.steps.*.image]`.
+	Paths []string `yaml:"paths,omitempty"`
 }
 
 // DeployType contains the specific implementation and parameters needed
@@ -333,6 +413,69 @@ type DeployType struct {
 
 	// KustomizeDeploy *beta* uses the `kustomize` CLI to "patch" a deployment for a target environment.
 	KustomizeDeploy *KustomizeDeploy `yaml:"kustomize,omitempty" yamltags:"oneOf=deploy"`
+
+	// DockerDeploy *alpha* runs the artifacts as containers on the local Docker daemon, without Kubernetes.
+	DockerDeploy *DockerDeploy `yaml:"docker,omitempty" yamltags:"oneOf=deploy"`
+
+	// RenderDeploy *alpha* runs a command that renders the manifests, for example with
+	// jsonnet, kpt or ytt, and deploys them with `kubectl apply`.
+	RenderDeploy *RenderDeploy `yaml:"render,omitempty" yamltags:"oneOf=deploy"`
+}
+
+// RenderDeploy *alpha* runs a command that renders the manifests, for example with
+// jsonnet, kpt or ytt, and deploys them with `kubectl apply`.
+type RenderDeploy struct {
+	// Command is the command that writes the manifests, as a YAML stream, to its standard output.
+	// It's run with `sh -c` from the directory of the Skaffold configuration, so it can use pipes.
+	// For example: `jsonnet -y k8s/app.jsonnet`.
+	Command string `yaml:"command,omitempty" yamltags:"required"`
+
+	// Dependencies lists the files used to render the manifests.
+	// `skaffold dev` redeploys when they change.
+	// For example: `["k8s/*.jsonnet", "k8s/*.libsonnet"]`.
+	Dependencies []string `yaml:"dependencies,omitempty"`
+
+	// Flags are additional flags passed to `kubectl`.
+	Flags KubectlFlags `yaml:"flags,omitempty"`
+
+	// Sops configures the keys used to decrypt manifests encrypted with SOPS.
+	Sops *SopsConfig `yaml:"sops,omitempty"`
+}
+
+// DockerDeploy *alpha* runs the artifacts as containers on the local Docker daemon, without Kubernetes.
+type DockerDeploy struct {
+	// Containers lists the containers to run.
+	Containers []DockerContainer `yaml:"containers,omitempty" yamltags:"required"`
+
+	// Network is the Docker network shared by the containers,
+	// so that they can reach each other by name.
+	// Defaults to `skaffold-network`.
+	Network string `yaml:"network,omitempty"`
+}
+
+// DockerContainer describes a container run by the Docker deployer.
+type DockerContainer struct {
+	// Name is the name of the container.
+	Name string `yaml:"name,omitempty" yamltags:"required"`
+
+	// Image is the image to run. The name of an artifact is
+	// replaced with the tag of the built image.
+	Image string `yaml:"image,omitempty" yamltags:"required"`
+
+	// Args overrides the default command of the image.
+	Args []string `yaml:"args,omitempty"`
+
+	// Ports lists the ports to publish, using the `docker run -p` syntax.
+	// For example: `["8080:8080", "127.0.0.1:9000:9000/udp"]`.
+	Ports []string `yaml:"ports,omitempty"`
+
+	// Env lists the environment variables of the container.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Volumes lists the host directories to mount, using the `docker run -v` syntax.
+	// Relative host paths are relative to the Skaffold configuration.
+	// For example: `["./data:/data", "./config:/etc/app:ro"]`.
+	Volumes []string `yaml:"volumes,omitempty"`
 }
 
 // KubectlDeploy *beta* uses a client side `kubectl apply` to deploy manifests.
@@ -347,6 +490,21 @@ type KubectlDeploy struct {
 
 	// Flags are additional flags passed to `kubectl`.
 	Flags KubectlFlags `yaml:"flags,omitempty"`
+
+	// Sops configures the keys used to decrypt manifests encrypted with SOPS.
+	Sops *SopsConfig `yaml:"sops,omitempty"`
+}
+
+// SopsConfig configures the keys used to decrypt manifests encrypted with SOPS.
+// Manifests holding SOPS metadata are always decrypted before they are deployed.
+type SopsConfig struct {
+	// AgeKeyFile is the path to the file holding the age keys.
+	// Defaults to `sops` own defaults, e.g. the `SOPS_AGE_KEY_FILE` environment variable.
+	AgeKeyFile string `yaml:"ageKeyFile,omitempty"`
+
+	// GnuPGHome is the GnuPG home directory holding the PGP keys.
+	// Defaults to `sops` own defaults, e.g. `~/.gnupg`.
+	GnuPGHome string `yaml:"gnupgHome,omitempty"`
 }
 
 // KubectlFlags are additional flags passed on the command
@@ -389,11 +547,27 @@ type HelmDeployFlags struct {
 // KustomizeDeploy *beta* uses the `kustomize` CLI to "patch" a deployment for a target environment.
 type KustomizeDeploy struct {
 	// KustomizePath is the path to Kustomization files.
-	// Defaults to `.`.
+	// Defaults to `.` unless `paths` is set.
 	KustomizePath string `yaml:"path,omitempty"`
 
+	// KustomizePaths are the paths to additional Kustomization files,
+	// rendered and applied together with `path`.
+	// For example: `["components/api", "components/web"]`.
+	KustomizePaths []string `yaml:"paths,omitempty"`
+
+	// Binary is the kustomize executable.
+	// Defaults to `kustomize`.
+	Binary string `yaml:"binary,omitempty"`
+
+	// BuildArgs are additional args passed to `kustomize build`.
+	// For example: `["--load_restrictor", "none"]`.
+	BuildArgs []string `yaml:"buildArgs,omitempty"`
+
 	// Flags are additional flags passed to `kubectl`.
 	Flags KubectlFlags `yaml:"flags,omitempty"`
+
+	// Sops configures the keys used to decrypt manifests encrypted with SOPS.
+	Sops *SopsConfig `yaml:"sops,omitempty"`
 }
 
 // HelmRelease describes a helm release to be deployed.
@@ -431,9 +605,15 @@ type HelmRelease struct {
 	Wait bool `yaml:"wait,omitempty"`
 
 	// RecreatePods if `true`, Skaffold will send `--recreate-pods` flag to Helm CLI.
+	// Ignored with Helm 3, which no longer supports this flag.
 	// Defaults to `false`.
 	RecreatePods bool `yaml:"recreatePods,omitempty"`
 
+	// CreateNamespace if `true`, Skaffold will send `--create-namespace` flag to Helm CLI
+	// when installing the release. Requires Helm 3.2 or later; Helm 2 always creates the namespace.
+	// Defaults to `false`.
+	CreateNamespace bool `yaml:"createNamespace,omitempty"`
+
 	// SkipBuildDependencies should build dependencies be skipped.
 	SkipBuildDependencies bool `yaml:"skipBuildDependencies,omitempty"`
 
@@ -515,6 +695,27 @@ type Artifact struct {
 type Sync struct {
 	// Manual lists manual sync rules indicating the source and destination.
 	Manual []*SyncRule `yaml:"manual,omitempty" yamltags:"oneOf=sync"`
+
+	// Infer lists the glob patterns of the files that can be synced to the destinations
+	// inferred from the `COPY` and `ADD` instructions of the Dockerfile or, for Jib artifacts,
+	// given by the Jib plugin. Other files are rebuilt.
+	// For example: `["**/*.js", "static/**"]`.
+	Infer []string `yaml:"infer,omitempty" yamltags:"oneOf=sync"`
+
+	// After lists the commands run in each container after the files are synced,
+	// for example to make the application reload them.
+	After []PostSyncCommand `yaml:"after,omitempty"`
+}
+
+// PostSyncCommand is a command run in a container after files are synced to it.
+type PostSyncCommand struct {
+	// Command is the command to run and its arguments.
+	// For example: `["kill", "-HUP", "1"]`.
+	Command []string `yaml:"command,omitempty" yamltags:"required"`
+
+	// RebuildOnFailure rebuilds and redeploys the artifact when the command fails,
+	// instead of only reporting the failure.
+	RebuildOnFailure bool `yaml:"rebuildOnFailure,omitempty"`
 }
 
 // SyncRule specifies which local files to sync to remote folders.
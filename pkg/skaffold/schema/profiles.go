@@ -135,9 +135,10 @@ func applyProfile(config *latest.SkaffoldConfig, profile latest.Profile) error {
 		APIVersion: config.APIVersion,
 		Kind:       config.Kind,
 		Pipeline: latest.Pipeline{
-			Build:  overlayProfileField(config.Build, profile.Build).(latest.BuildConfig),
-			Deploy: overlayProfileField(config.Deploy, profile.Deploy).(latest.DeployConfig),
-			Test:   overlayProfileField(config.Test, profile.Test).([]*latest.TestCase),
+			Build:       overlayProfileField(config.Build, profile.Build).(latest.BuildConfig),
+			Deploy:      overlayProfileField(config.Deploy, profile.Deploy).(latest.DeployConfig),
+			Test:        overlayProfileField(config.Test, profile.Test).([]*latest.TestCase),
+			PortForward: overlayProfileField(config.PortForward, profile.PortForward).([]*latest.PortForwardResource),
 		},
 	}
 
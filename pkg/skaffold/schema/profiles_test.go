@@ -234,6 +234,32 @@ func TestApplyProfiles(t *testing.T) {
 				withHelmDeploy(),
 			),
 		},
+		{
+			description: "port forward",
+			profile:     "profile",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withPortForward(&latest.PortForwardResource{Type: "service", Name: "web", Port: 8080}),
+				withProfiles(latest.Profile{
+					Name: "profile",
+					Pipeline: latest.Pipeline{
+						PortForward: []*latest.PortForwardResource{
+							{Type: "service", Name: "web", Port: 8080, Address: "0.0.0.0"},
+						},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withPortForward(&latest.PortForwardResource{Type: "service", Name: "web", Port: 8080, Address: "0.0.0.0"}),
+			),
+		},
 		{
 			description: "patch Dockerfile",
 			profile:     "profile",
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yamltags"
 )
 
@@ -36,6 +37,9 @@ func Process(config *latest.SkaffoldConfig) error {
 	errs = append(errs, validateDockerNetworkMode(config.Build.Artifacts)...)
 	errs = append(errs, validateCustomDependencies(config.Build.Artifacts)...)
 	errs = append(errs, validateSyncRules(config.Build.Artifacts)...)
+	errs = append(errs, validateKubeContexts(config.Deploy.KubeContexts)...)
+	errs = append(errs, validateEphemeralNamespace(config.Deploy)...)
+	errs = append(errs, validatePortForwardResources(config.PortForward)...)
 
 	if len(errs) == 0 {
 		return nil
@@ -79,6 +83,45 @@ func validateCustomDependencies(artifacts []*latest.Artifact) (errs []error) {
 	return
 }
 
+// validateKubeContexts makes sure that each kubectl context is deployed to only once.
+func validateKubeContexts(kubeContexts []latest.KubeContext) (errs []error) {
+	seen := map[string]bool{}
+	for _, c := range kubeContexts {
+		if seen[c.Name] {
+			errs = append(errs, fmt.Errorf("kubectl context %s is listed more than once in deploy.kubeContexts", c.Name))
+		}
+		seen[c.Name] = true
+	}
+	return
+}
+
+// validateEphemeralNamespace makes sure that an ephemeral namespace isn't combined
+// with namespaces set on the kubectl contexts.
+func validateEphemeralNamespace(deploy latest.DeployConfig) (errs []error) {
+	if deploy.EphemeralNamespace == nil {
+		return
+	}
+	for _, c := range deploy.KubeContexts {
+		if c.Namespace != "" {
+			errs = append(errs, fmt.Errorf("deploy.ephemeralNamespace can't be used with namespace %s of kubectl context %s", c.Namespace, c.Name))
+		}
+	}
+	return
+}
+
+// validatePortForwardResources makes sure that the type of the resources to port-forward
+// is a Service, a Pod or a controller with a pod spec.
+func validatePortForwardResources(resources []*latest.PortForwardResource) (errs []error) {
+	validTypes := []string{"pod", "service", "deployment", "replicaset", "replicationcontroller", "statefulset", "daemonset", "job", "cronjob"}
+
+	for _, r := range resources {
+		if r.Type != "" && !util.StrSliceContains(validTypes, strings.ToLower(string(r.Type))) {
+			errs = append(errs, fmt.Errorf("resource type '%s' of %s can't be port-forwarded. Supported types are: %s", r.Type, r.Name, strings.Join(validTypes, ", ")))
+		}
+	}
+	return
+}
+
 // visitStructs recursively visits all fields in the config and collects errors found by the visitor
 func visitStructs(s interface{}, visitor func(interface{}) error) []error {
 	v := reflect.ValueOf(s)
@@ -127,6 +170,7 @@ func visitStructs(s interface{}, visitor func(interface{}) error) []error {
 }
 
 // validateSyncRules checks that all manual sync rules have a valid strip prefix
+// and that sync is inferred only for docker and jib artifacts.
 func validateSyncRules(artifacts []*latest.Artifact) []error {
 	var errs []error
 	for _, a := range artifacts {
@@ -137,6 +181,11 @@ func validateSyncRules(artifacts []*latest.Artifact) []error {
 					errs = append(errs, err)
 				}
 			}
+
+			if len(a.Sync.Infer) > 0 && (a.BazelArtifact != nil || a.CustomArtifact != nil || a.KanikoArtifact != nil) {
+				err := fmt.Errorf("inferred sync is only supported for docker and jib artifacts, not for %s", a.ImageName)
+				errs = append(errs, err)
+			}
 		}
 	}
 	return errs
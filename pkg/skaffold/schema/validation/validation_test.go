@@ -452,6 +452,40 @@ func TestValidateSyncRules(t *testing.T) {
 				},
 			}},
 		},
+		{
+			description: "inferred sync for docker artifact",
+			artifacts: []*latest.Artifact{{
+				ArtifactType: latest.ArtifactType{
+					DockerArtifact: &latest.DockerArtifact{},
+				},
+				Sync: &latest.Sync{
+					Infer: []string{"**/*.js"},
+				},
+			}},
+		},
+		{
+			description: "inferred sync for jib artifact",
+			artifacts: []*latest.Artifact{{
+				ArtifactType: latest.ArtifactType{
+					JibMavenArtifact: &latest.JibMavenArtifact{},
+				},
+				Sync: &latest.Sync{
+					Infer: []string{"src/main/**"},
+				},
+			}},
+		},
+		{
+			description: "inferred sync for bazel artifact",
+			artifacts: []*latest.Artifact{{
+				ArtifactType: latest.ArtifactType{
+					BazelArtifact: &latest.BazelArtifact{},
+				},
+				Sync: &latest.Sync{
+					Infer: []string{"**/*.js"},
+				},
+			}},
+			shouldErr: true,
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -518,3 +552,116 @@ func TestValidateCustomDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateKubeContexts(t *testing.T) {
+	tests := []struct {
+		description  string
+		kubeContexts []latest.KubeContext
+		shouldErr    bool
+	}{
+		{
+			description: "no kube contexts",
+		},
+		{
+			description:  "distinct kube contexts",
+			kubeContexts: []latest.KubeContext{{Name: "cluster1"}, {Name: "cluster2", Namespace: "ns"}},
+		},
+		{
+			description:  "duplicate kube context",
+			kubeContexts: []latest.KubeContext{{Name: "cluster1"}, {Name: "cluster1", Namespace: "ns"}},
+			shouldErr:    true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+
+			err := Process(
+				&latest.SkaffoldConfig{
+					Pipeline: latest.Pipeline{
+						Deploy: latest.DeployConfig{
+							KubeContexts: test.kubeContexts,
+						},
+					},
+				})
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestValidateEphemeralNamespace(t *testing.T) {
+	tests := []struct {
+		description        string
+		kubeContexts       []latest.KubeContext
+		ephemeralNamespace *latest.EphemeralNamespace
+		shouldErr          bool
+	}{
+		{
+			description:  "per-context namespace",
+			kubeContexts: []latest.KubeContext{{Name: "cluster1", Namespace: "ns"}},
+		},
+		{
+			description:        "ephemeral namespace",
+			kubeContexts:       []latest.KubeContext{{Name: "cluster1"}, {Name: "cluster2"}},
+			ephemeralNamespace: &latest.EphemeralNamespace{},
+		},
+		{
+			description:        "ephemeral namespace and per-context namespace",
+			kubeContexts:       []latest.KubeContext{{Name: "cluster1"}, {Name: "cluster2", Namespace: "ns"}},
+			ephemeralNamespace: &latest.EphemeralNamespace{},
+			shouldErr:          true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+
+			err := Process(
+				&latest.SkaffoldConfig{
+					Pipeline: latest.Pipeline{
+						Deploy: latest.DeployConfig{
+							KubeContexts:       test.kubeContexts,
+							EphemeralNamespace: test.ephemeralNamespace,
+						},
+					},
+				})
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestValidatePortForwardResources(t *testing.T) {
+	tests := []struct {
+		description  string
+		resourceType latest.ResourceType
+		shouldErr    bool
+	}{
+		{description: "service", resourceType: "service"},
+		{description: "capitalized deployment", resourceType: "Deployment"},
+		{description: "statefulset", resourceType: "statefulset"},
+		{description: "unsupported type", resourceType: "configmap", shouldErr: true},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+
+			err := Process(
+				&latest.SkaffoldConfig{
+					Pipeline: latest.Pipeline{
+						PortForward: []*latest.PortForwardResource{{
+							Type: test.resourceType,
+							Name: "leeroy-web",
+							Port: 8080,
+						}},
+					},
+				})
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
@@ -379,6 +379,12 @@ func withTests(testCases ...*latest.TestCase) func(*latest.SkaffoldConfig) {
 	}
 }
 
+func withPortForward(resources ...*latest.PortForwardResource) func(*latest.SkaffoldConfig) {
+	return func(cfg *latest.SkaffoldConfig) {
+		cfg.PortForward = resources
+	}
+}
+
 func TestUpgradeToNextVersion(t *testing.T) {
 	for i, schemaVersion := range SchemaVersions[0 : len(SchemaVersions)-2] {
 		from := schemaVersion
@@ -22,6 +22,8 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (s *server) GetState(context.Context, *empty.Empty) (*proto.State, error) {
@@ -41,3 +43,76 @@ func (s *server) Build(context.Context, *empty.Empty) (*empty.Empty, error) {
 	s.trigger <- true
 	return &empty.Empty{}, nil
 }
+
+func (s *server) AutoBuild(ctx context.Context, request *proto.TriggerRequest) (*empty.Empty, error) {
+	loop, err := registeredDevLoop()
+	if err != nil {
+		return nil, err
+	}
+
+	loop.SetAutoBuild(request.Enabled)
+	return &empty.Empty{}, nil
+}
+
+func (s *server) AutoSync(ctx context.Context, request *proto.TriggerRequest) (*empty.Empty, error) {
+	loop, err := registeredDevLoop()
+	if err != nil {
+		return nil, err
+	}
+
+	loop.SetAutoSync(request.Enabled)
+	return &empty.Empty{}, nil
+}
+
+func (s *server) AutoDeploy(ctx context.Context, request *proto.TriggerRequest) (*empty.Empty, error) {
+	loop, err := registeredDevLoop()
+	if err != nil {
+		return nil, err
+	}
+
+	loop.SetAutoDeploy(request.Enabled)
+	return &empty.Empty{}, nil
+}
+
+func (s *server) BuildArtifacts(ctx context.Context, request *proto.BuildRequest) (*empty.Empty, error) {
+	loop, err := registeredDevLoop()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loop.BuildArtifacts(request.Artifacts); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
+
+func (s *server) Redeploy(context.Context, *empty.Empty) (*empty.Empty, error) {
+	loop, err := registeredDevLoop()
+	if err != nil {
+		return nil, err
+	}
+
+	loop.Redeploy()
+	return &empty.Empty{}, nil
+}
+
+func (s *server) GetPendingChanges(context.Context, *empty.Empty) (*proto.PendingChanges, error) {
+	loop, err := registeredDevLoop()
+	if err != nil {
+		return nil, err
+	}
+
+	return loop.PendingChanges(), nil
+}
+
+func (s *server) SetLogFilter(ctx context.Context, filter *proto.LogFilter) (*empty.Empty, error) {
+	filterer, err := registeredLogFilterer()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := filterer.SetLogFilter(filter); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &empty.Empty{}, nil
+}
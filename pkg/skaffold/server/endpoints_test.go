@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/server/proto"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeDevLoop struct {
+	autoBuild  bool
+	autoSync   bool
+	autoDeploy bool
+	built      []string
+	redeployed bool
+}
+
+func (l *fakeDevLoop) SetAutoBuild(enabled bool)  { l.autoBuild = enabled }
+func (l *fakeDevLoop) SetAutoSync(enabled bool)   { l.autoSync = enabled }
+func (l *fakeDevLoop) SetAutoDeploy(enabled bool) { l.autoDeploy = enabled }
+func (l *fakeDevLoop) Redeploy()                  { l.redeployed = true }
+
+func (l *fakeDevLoop) BuildArtifacts(imageNames []string) error {
+	for _, imageName := range imageNames {
+		if imageName != "img" {
+			return errors.New("unknown artifact " + imageName)
+		}
+	}
+	l.built = imageNames
+	return nil
+}
+
+func (l *fakeDevLoop) PendingChanges() *proto.PendingChanges {
+	return &proto.PendingChanges{
+		AutoBuild:  l.autoBuild,
+		AutoSync:   l.autoSync,
+		AutoDeploy: l.autoDeploy,
+		Redeploy:   l.redeployed,
+	}
+}
+
+type fakeLogFilterer struct {
+	filter *proto.LogFilter
+}
+
+func (f *fakeLogFilterer) SetLogFilter(filter *proto.LogFilter) error {
+	if filter.Level == "unknown" {
+		return errors.New("unknown log level")
+	}
+	f.filter = filter
+	return nil
+}
+
+func TestNoDevLoop(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		RegisterDevLoop(nil)
+
+		_, err := (&server{}).GetPendingChanges(context.Background(), &empty.Empty{})
+
+		t.CheckDeepEqual(codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestDevLoopEndpoints(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		loop := &fakeDevLoop{}
+		RegisterDevLoop(loop)
+		defer RegisterDevLoop(nil)
+
+		ctx := context.Background()
+		s := &server{}
+
+		_, err := s.AutoBuild(ctx, &proto.TriggerRequest{Enabled: true})
+		t.CheckNoError(err)
+		_, err = s.AutoDeploy(ctx, &proto.TriggerRequest{Enabled: true})
+		t.CheckNoError(err)
+		_, err = s.AutoSync(ctx, &proto.TriggerRequest{Enabled: false})
+		t.CheckNoError(err)
+		_, err = s.Redeploy(ctx, &empty.Empty{})
+		t.CheckNoError(err)
+		_, err = s.BuildArtifacts(ctx, &proto.BuildRequest{Artifacts: []string{"img"}})
+		t.CheckNoError(err)
+
+		_, err = s.BuildArtifacts(ctx, &proto.BuildRequest{Artifacts: []string{"unknown"}})
+		t.CheckDeepEqual(codes.InvalidArgument, status.Code(err))
+
+		pending, err := s.GetPendingChanges(ctx, &empty.Empty{})
+		t.CheckNoError(err)
+		t.CheckDeepEqual(&proto.PendingChanges{
+			AutoBuild:  true,
+			AutoDeploy: true,
+			Redeploy:   true,
+		}, pending)
+		t.CheckDeepEqual([]string{"img"}, loop.built)
+	})
+}
+
+func TestSetLogFilter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		ctx := context.Background()
+		s := &server{}
+
+		RegisterLogFilterer(nil)
+		_, err := s.SetLogFilter(ctx, &proto.LogFilter{})
+		t.CheckDeepEqual(codes.FailedPrecondition, status.Code(err))
+
+		filterer := &fakeLogFilterer{}
+		RegisterLogFilterer(filterer)
+		defer RegisterLogFilterer(nil)
+
+		_, err = s.SetLogFilter(ctx, &proto.LogFilter{Containers: []string{"web"}})
+		t.CheckNoError(err)
+		_, err = s.SetLogFilter(ctx, &proto.LogFilter{Level: "unknown"})
+		t.CheckDeepEqual(codes.InvalidArgument, status.Code(err))
+
+		t.CheckDeepEqual(&proto.LogFilter{Containers: []string{"web"}}, filterer.filter)
+	})
+}
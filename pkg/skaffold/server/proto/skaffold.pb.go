@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	empty "github.com/golang/protobuf/ptypes/empty"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
@@ -25,6 +26,85 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// PhaseStatus is the typed status of a phase of the pipeline
+type PhaseStatus int32
+
+const (
+	PhaseStatus_UNKNOWN_STATUS PhaseStatus = 0
+	PhaseStatus_NOT_STARTED    PhaseStatus = 1
+	PhaseStatus_IN_PROGRESS    PhaseStatus = 2
+	PhaseStatus_SUCCEEDED      PhaseStatus = 3
+	PhaseStatus_FAILED         PhaseStatus = 4
+)
+
+var PhaseStatus_name = map[int32]string{
+	0: "UNKNOWN_STATUS",
+	1: "NOT_STARTED",
+	2: "IN_PROGRESS",
+	3: "SUCCEEDED",
+	4: "FAILED",
+}
+
+var PhaseStatus_value = map[string]int32{
+	"UNKNOWN_STATUS": 0,
+	"NOT_STARTED":    1,
+	"IN_PROGRESS":    2,
+	"SUCCEEDED":      3,
+	"FAILED":         4,
+}
+
+func (x PhaseStatus) String() string {
+	return proto.EnumName(PhaseStatus_name, int32(x))
+}
+
+func (PhaseStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{0}
+}
+
+// ErrorCode tells why a phase of the pipeline failed
+type ErrorCode int32
+
+const (
+	ErrorCode_OK                  ErrorCode = 0
+	ErrorCode_UNKNOWN_ERROR       ErrorCode = 1
+	ErrorCode_BUILD_FAILED        ErrorCode = 2
+	ErrorCode_TEST_FAILED         ErrorCode = 3
+	ErrorCode_DEPLOY_FAILED       ErrorCode = 4
+	ErrorCode_SYNC_FAILED         ErrorCode = 5
+	ErrorCode_STATUS_CHECK_FAILED ErrorCode = 6
+	ErrorCode_DEV_LOOP_FAILED     ErrorCode = 7
+)
+
+var ErrorCode_name = map[int32]string{
+	0: "OK",
+	1: "UNKNOWN_ERROR",
+	2: "BUILD_FAILED",
+	3: "TEST_FAILED",
+	4: "DEPLOY_FAILED",
+	5: "SYNC_FAILED",
+	6: "STATUS_CHECK_FAILED",
+	7: "DEV_LOOP_FAILED",
+}
+
+var ErrorCode_value = map[string]int32{
+	"OK":                  0,
+	"UNKNOWN_ERROR":       1,
+	"BUILD_FAILED":        2,
+	"TEST_FAILED":         3,
+	"DEPLOY_FAILED":       4,
+	"SYNC_FAILED":         5,
+	"STATUS_CHECK_FAILED": 6,
+	"DEV_LOOP_FAILED":     7,
+}
+
+func (x ErrorCode) String() string {
+	return proto.EnumName(ErrorCode_name, int32(x))
+}
+
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{1}
+}
+
 type StateResponse struct {
 	State                *State   `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -143,12 +223,17 @@ func (m *Request) GetName() string {
 }
 
 type State struct {
-	BuildState           *BuildState           `protobuf:"bytes,1,opt,name=buildState,proto3" json:"buildState,omitempty"`
-	DeployState          *DeployState          `protobuf:"bytes,2,opt,name=deployState,proto3" json:"deployState,omitempty"`
-	ForwardedPorts       map[string]*PortEvent `protobuf:"bytes,3,rep,name=forwardedPorts,proto3" json:"forwardedPorts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	BuildState           *BuildState                     `protobuf:"bytes,1,opt,name=buildState,proto3" json:"buildState,omitempty"`
+	DeployState          *DeployState                    `protobuf:"bytes,2,opt,name=deployState,proto3" json:"deployState,omitempty"`
+	ForwardedPorts       map[string]*PortEvent           `protobuf:"bytes,3,rep,name=forwardedPorts,proto3" json:"forwardedPorts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	TestState            *TestState                      `protobuf:"bytes,4,opt,name=testState,proto3" json:"testState,omitempty"`
+	SyncState            *SyncState                      `protobuf:"bytes,5,opt,name=syncState,proto3" json:"syncState,omitempty"`
+	StatusCheckState     *StatusCheckState               `protobuf:"bytes,6,opt,name=statusCheckState,proto3" json:"statusCheckState,omitempty"`
+	DevLoopState         *DevLoopState                   `protobuf:"bytes,7,opt,name=devLoopState,proto3" json:"devLoopState,omitempty"`
+	Resources            map[string]*ResourceStatusEvent `protobuf:"bytes,8,rep,name=resources,proto3" json:"resources,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
 }
 
 func (m *State) Reset()         { *m = State{} }
@@ -197,6 +282,98 @@ func (m *State) GetForwardedPorts() map[string]*PortEvent {
 	return nil
 }
 
+func (m *State) GetTestState() *TestState {
+	if m != nil {
+		return m.TestState
+	}
+	return nil
+}
+
+func (m *State) GetSyncState() *SyncState {
+	if m != nil {
+		return m.SyncState
+	}
+	return nil
+}
+
+func (m *State) GetStatusCheckState() *StatusCheckState {
+	if m != nil {
+		return m.StatusCheckState
+	}
+	return nil
+}
+
+func (m *State) GetDevLoopState() *DevLoopState {
+	if m != nil {
+		return m.DevLoopState
+	}
+	return nil
+}
+
+func (m *State) GetResources() map[string]*ResourceStatusEvent {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+// Timing tells when a phase started and, once it's over,
+// when it ended and how long it took
+type Timing struct {
+	StartTime            *timestamp.Timestamp `protobuf:"bytes,1,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              *timestamp.Timestamp `protobuf:"bytes,2,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	Duration             *duration.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Timing) Reset()         { *m = Timing{} }
+func (m *Timing) String() string { return proto.CompactTextString(m) }
+func (*Timing) ProtoMessage()    {}
+func (*Timing) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{4}
+}
+
+func (m *Timing) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Timing.Unmarshal(m, b)
+}
+func (m *Timing) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Timing.Marshal(b, m, deterministic)
+}
+func (m *Timing) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Timing.Merge(m, src)
+}
+func (m *Timing) XXX_Size() int {
+	return xxx_messageInfo_Timing.Size(m)
+}
+func (m *Timing) XXX_DiscardUnknown() {
+	xxx_messageInfo_Timing.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Timing proto.InternalMessageInfo
+
+func (m *Timing) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *Timing) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *Timing) GetDuration() *duration.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
 // BuildState contains a map of all skaffold artifacts to their current build
 // states
 type BuildState struct {
@@ -210,7 +387,7 @@ func (m *BuildState) Reset()         { *m = BuildState{} }
 func (m *BuildState) String() string { return proto.CompactTextString(m) }
 func (*BuildState) ProtoMessage()    {}
 func (*BuildState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{4}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{5}
 }
 
 func (m *BuildState) XXX_Unmarshal(b []byte) error {
@@ -238,19 +415,189 @@ func (m *BuildState) GetArtifacts() map[string]string {
 	return nil
 }
 
-// DeployState contains the status of the current deploy
+// TestState contains the status of the last tests
+type TestState struct {
+	Status               PhaseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *TestState) Reset()         { *m = TestState{} }
+func (m *TestState) String() string { return proto.CompactTextString(m) }
+func (*TestState) ProtoMessage()    {}
+func (*TestState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{6}
+}
+
+func (m *TestState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestState.Unmarshal(m, b)
+}
+func (m *TestState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestState.Marshal(b, m, deterministic)
+}
+func (m *TestState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestState.Merge(m, src)
+}
+func (m *TestState) XXX_Size() int {
+	return xxx_messageInfo_TestState.Size(m)
+}
+func (m *TestState) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestState proto.InternalMessageInfo
+
+func (m *TestState) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+// SyncState contains the status of the last file sync, for each artifact
+type SyncState struct {
+	Artifacts            map[string]*SyncEvent `protobuf:"bytes,1,rep,name=artifacts,proto3" json:"artifacts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *SyncState) Reset()         { *m = SyncState{} }
+func (m *SyncState) String() string { return proto.CompactTextString(m) }
+func (*SyncState) ProtoMessage()    {}
+func (*SyncState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{7}
+}
+
+func (m *SyncState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncState.Unmarshal(m, b)
+}
+func (m *SyncState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncState.Marshal(b, m, deterministic)
+}
+func (m *SyncState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncState.Merge(m, src)
+}
+func (m *SyncState) XXX_Size() int {
+	return xxx_messageInfo_SyncState.Size(m)
+}
+func (m *SyncState) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncState proto.InternalMessageInfo
+
+func (m *SyncState) GetArtifacts() map[string]*SyncEvent {
+	if m != nil {
+		return m.Artifacts
+	}
+	return nil
+}
+
+// StatusCheckState contains the status of the last status check
+type StatusCheckState struct {
+	Status               PhaseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *StatusCheckState) Reset()         { *m = StatusCheckState{} }
+func (m *StatusCheckState) String() string { return proto.CompactTextString(m) }
+func (*StatusCheckState) ProtoMessage()    {}
+func (*StatusCheckState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{8}
+}
+
+func (m *StatusCheckState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusCheckState.Unmarshal(m, b)
+}
+func (m *StatusCheckState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusCheckState.Marshal(b, m, deterministic)
+}
+func (m *StatusCheckState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusCheckState.Merge(m, src)
+}
+func (m *StatusCheckState) XXX_Size() int {
+	return xxx_messageInfo_StatusCheckState.Size(m)
+}
+func (m *StatusCheckState) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusCheckState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusCheckState proto.InternalMessageInfo
+
+func (m *StatusCheckState) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+// DevLoopState contains the current iteration of the dev loop and its status
+type DevLoopState struct {
+	Iteration            int32       `protobuf:"varint,1,opt,name=iteration,proto3" json:"iteration,omitempty"`
+	Status               PhaseStatus `protobuf:"varint,2,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *DevLoopState) Reset()         { *m = DevLoopState{} }
+func (m *DevLoopState) String() string { return proto.CompactTextString(m) }
+func (*DevLoopState) ProtoMessage()    {}
+func (*DevLoopState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{9}
+}
+
+func (m *DevLoopState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DevLoopState.Unmarshal(m, b)
+}
+func (m *DevLoopState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DevLoopState.Marshal(b, m, deterministic)
+}
+func (m *DevLoopState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DevLoopState.Merge(m, src)
+}
+func (m *DevLoopState) XXX_Size() int {
+	return xxx_messageInfo_DevLoopState.Size(m)
+}
+func (m *DevLoopState) XXX_DiscardUnknown() {
+	xxx_messageInfo_DevLoopState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DevLoopState proto.InternalMessageInfo
+
+func (m *DevLoopState) GetIteration() int32 {
+	if m != nil {
+		return m.Iteration
+	}
+	return 0
+}
+
+func (m *DevLoopState) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+// DeployState contains the status of the current deploy, overall and
+// for each kubectl context
 type DeployState struct {
-	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status               string            `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	KubeContexts         map[string]string `protobuf:"bytes,2,rep,name=kubeContexts,proto3" json:"kubeContexts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *DeployState) Reset()         { *m = DeployState{} }
 func (m *DeployState) String() string { return proto.CompactTextString(m) }
 func (*DeployState) ProtoMessage()    {}
 func (*DeployState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{5}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{10}
 }
 
 func (m *DeployState) XXX_Unmarshal(b []byte) error {
@@ -278,12 +625,26 @@ func (m *DeployState) GetStatus() string {
 	return ""
 }
 
+func (m *DeployState) GetKubeContexts() map[string]string {
+	if m != nil {
+		return m.KubeContexts
+	}
+	return nil
+}
+
 type Event struct {
 	// Types that are valid to be assigned to EventType:
 	//	*Event_MetaEvent
 	//	*Event_BuildEvent
 	//	*Event_DeployEvent
 	//	*Event_PortEvent
+	//	*Event_PodDiagnosticEvent
+	//	*Event_FileChangeEvent
+	//	*Event_SyncEvent
+	//	*Event_TestEvent
+	//	*Event_StatusCheckEvent
+	//	*Event_DevLoopEvent
+	//	*Event_ResourceStatusEvent
 	EventType            isEvent_EventType `protobuf_oneof:"event_type"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -294,7 +655,7 @@ func (m *Event) Reset()         { *m = Event{} }
 func (m *Event) String() string { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()    {}
 func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{6}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{11}
 }
 
 func (m *Event) XXX_Unmarshal(b []byte) error {
@@ -335,6 +696,34 @@ type Event_PortEvent struct {
 	PortEvent *PortEvent `protobuf:"bytes,4,opt,name=portEvent,proto3,oneof"`
 }
 
+type Event_PodDiagnosticEvent struct {
+	PodDiagnosticEvent *PodDiagnosticEvent `protobuf:"bytes,5,opt,name=podDiagnosticEvent,proto3,oneof"`
+}
+
+type Event_FileChangeEvent struct {
+	FileChangeEvent *FileChangeEvent `protobuf:"bytes,6,opt,name=fileChangeEvent,proto3,oneof"`
+}
+
+type Event_SyncEvent struct {
+	SyncEvent *SyncEvent `protobuf:"bytes,7,opt,name=syncEvent,proto3,oneof"`
+}
+
+type Event_TestEvent struct {
+	TestEvent *TestEvent `protobuf:"bytes,8,opt,name=testEvent,proto3,oneof"`
+}
+
+type Event_StatusCheckEvent struct {
+	StatusCheckEvent *StatusCheckEvent `protobuf:"bytes,9,opt,name=statusCheckEvent,proto3,oneof"`
+}
+
+type Event_DevLoopEvent struct {
+	DevLoopEvent *DevLoopEvent `protobuf:"bytes,10,opt,name=devLoopEvent,proto3,oneof"`
+}
+
+type Event_ResourceStatusEvent struct {
+	ResourceStatusEvent *ResourceStatusEvent `protobuf:"bytes,11,opt,name=resourceStatusEvent,proto3,oneof"`
+}
+
 func (*Event_MetaEvent) isEvent_EventType() {}
 
 func (*Event_BuildEvent) isEvent_EventType() {}
@@ -343,6 +732,20 @@ func (*Event_DeployEvent) isEvent_EventType() {}
 
 func (*Event_PortEvent) isEvent_EventType() {}
 
+func (*Event_PodDiagnosticEvent) isEvent_EventType() {}
+
+func (*Event_FileChangeEvent) isEvent_EventType() {}
+
+func (*Event_SyncEvent) isEvent_EventType() {}
+
+func (*Event_TestEvent) isEvent_EventType() {}
+
+func (*Event_StatusCheckEvent) isEvent_EventType() {}
+
+func (*Event_DevLoopEvent) isEvent_EventType() {}
+
+func (*Event_ResourceStatusEvent) isEvent_EventType() {}
+
 func (m *Event) GetEventType() isEvent_EventType {
 	if m != nil {
 		return m.EventType
@@ -378,6 +781,55 @@ func (m *Event) GetPortEvent() *PortEvent {
 	return nil
 }
 
+func (m *Event) GetPodDiagnosticEvent() *PodDiagnosticEvent {
+	if x, ok := m.GetEventType().(*Event_PodDiagnosticEvent); ok {
+		return x.PodDiagnosticEvent
+	}
+	return nil
+}
+
+func (m *Event) GetFileChangeEvent() *FileChangeEvent {
+	if x, ok := m.GetEventType().(*Event_FileChangeEvent); ok {
+		return x.FileChangeEvent
+	}
+	return nil
+}
+
+func (m *Event) GetSyncEvent() *SyncEvent {
+	if x, ok := m.GetEventType().(*Event_SyncEvent); ok {
+		return x.SyncEvent
+	}
+	return nil
+}
+
+func (m *Event) GetTestEvent() *TestEvent {
+	if x, ok := m.GetEventType().(*Event_TestEvent); ok {
+		return x.TestEvent
+	}
+	return nil
+}
+
+func (m *Event) GetStatusCheckEvent() *StatusCheckEvent {
+	if x, ok := m.GetEventType().(*Event_StatusCheckEvent); ok {
+		return x.StatusCheckEvent
+	}
+	return nil
+}
+
+func (m *Event) GetDevLoopEvent() *DevLoopEvent {
+	if x, ok := m.GetEventType().(*Event_DevLoopEvent); ok {
+		return x.DevLoopEvent
+	}
+	return nil
+}
+
+func (m *Event) GetResourceStatusEvent() *ResourceStatusEvent {
+	if x, ok := m.GetEventType().(*Event_ResourceStatusEvent); ok {
+		return x.ResourceStatusEvent
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Event) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -385,6 +837,13 @@ func (*Event) XXX_OneofWrappers() []interface{} {
 		(*Event_BuildEvent)(nil),
 		(*Event_DeployEvent)(nil),
 		(*Event_PortEvent)(nil),
+		(*Event_PodDiagnosticEvent)(nil),
+		(*Event_FileChangeEvent)(nil),
+		(*Event_SyncEvent)(nil),
+		(*Event_TestEvent)(nil),
+		(*Event_StatusCheckEvent)(nil),
+		(*Event_DevLoopEvent)(nil),
+		(*Event_ResourceStatusEvent)(nil),
 	}
 }
 
@@ -399,7 +858,7 @@ func (m *MetaEvent) Reset()         { *m = MetaEvent{} }
 func (m *MetaEvent) String() string { return proto.CompactTextString(m) }
 func (*MetaEvent) ProtoMessage()    {}
 func (*MetaEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{7}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{12}
 }
 
 func (m *MetaEvent) XXX_Unmarshal(b []byte) error {
@@ -427,20 +886,25 @@ func (m *MetaEvent) GetEntry() string {
 	return ""
 }
 
+// BuildEvent describes the build of an artifact. status is kept for
+// compatibility, phaseStatus is its typed equivalent
 type BuildEvent struct {
-	Artifact             string   `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
-	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Err                  string   `protobuf:"bytes,3,opt,name=err,proto3" json:"err,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Artifact             string      `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	Status               string      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Err                  string      `protobuf:"bytes,3,opt,name=err,proto3" json:"err,omitempty"`
+	PhaseStatus          PhaseStatus `protobuf:"varint,4,opt,name=phaseStatus,proto3,enum=proto.PhaseStatus" json:"phaseStatus,omitempty"`
+	Timing               *Timing     `protobuf:"bytes,5,opt,name=timing,proto3" json:"timing,omitempty"`
+	ErrCode              ErrorCode   `protobuf:"varint,6,opt,name=errCode,proto3,enum=proto.ErrorCode" json:"errCode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *BuildEvent) Reset()         { *m = BuildEvent{} }
 func (m *BuildEvent) String() string { return proto.CompactTextString(m) }
 func (*BuildEvent) ProtoMessage()    {}
 func (*BuildEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{8}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{13}
 }
 
 func (m *BuildEvent) XXX_Unmarshal(b []byte) error {
@@ -482,19 +946,46 @@ func (m *BuildEvent) GetErr() string {
 	return ""
 }
 
+func (m *BuildEvent) GetPhaseStatus() PhaseStatus {
+	if m != nil {
+		return m.PhaseStatus
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+func (m *BuildEvent) GetTiming() *Timing {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+func (m *BuildEvent) GetErrCode() ErrorCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrorCode_OK
+}
+
+// DeployEvent describes the deployment to a kubectl context. status is kept
+// for compatibility, phaseStatus is its typed equivalent
 type DeployEvent struct {
-	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Err                  string   `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status               string      `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Err                  string      `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	KubeContext          string      `protobuf:"bytes,3,opt,name=kubeContext,proto3" json:"kubeContext,omitempty"`
+	PhaseStatus          PhaseStatus `protobuf:"varint,4,opt,name=phaseStatus,proto3,enum=proto.PhaseStatus" json:"phaseStatus,omitempty"`
+	Timing               *Timing     `protobuf:"bytes,5,opt,name=timing,proto3" json:"timing,omitempty"`
+	ErrCode              ErrorCode   `protobuf:"varint,6,opt,name=errCode,proto3,enum=proto.ErrorCode" json:"errCode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *DeployEvent) Reset()         { *m = DeployEvent{} }
 func (m *DeployEvent) String() string { return proto.CompactTextString(m) }
 func (*DeployEvent) ProtoMessage()    {}
 func (*DeployEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{9}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{14}
 }
 
 func (m *DeployEvent) XXX_Unmarshal(b []byte) error {
@@ -529,15 +1020,491 @@ func (m *DeployEvent) GetErr() string {
 	return ""
 }
 
-type PortEvent struct {
-	LocalPort            int32    `protobuf:"varint,1,opt,name=localPort,proto3" json:"localPort,omitempty"`
-	RemotePort           int32    `protobuf:"varint,2,opt,name=remotePort,proto3" json:"remotePort,omitempty"`
-	PodName              string   `protobuf:"bytes,3,opt,name=podName,proto3" json:"podName,omitempty"`
-	ContainerName        string   `protobuf:"bytes,4,opt,name=containerName,proto3" json:"containerName,omitempty"`
-	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	PortName             string   `protobuf:"bytes,6,opt,name=portName,proto3" json:"portName,omitempty"`
-	ResourceType         string   `protobuf:"bytes,7,opt,name=resourceType,proto3" json:"resourceType,omitempty"`
+func (m *DeployEvent) GetKubeContext() string {
+	if m != nil {
+		return m.KubeContext
+	}
+	return ""
+}
+
+func (m *DeployEvent) GetPhaseStatus() PhaseStatus {
+	if m != nil {
+		return m.PhaseStatus
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+func (m *DeployEvent) GetTiming() *Timing {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+func (m *DeployEvent) GetErrCode() ErrorCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrorCode_OK
+}
+
+// FileChangeEvent lists the files changed in a component watched by
+// the dev loop: an artifact, the tests, the deploy or the configuration
+type FileChangeEvent struct {
+	Component            string   `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	Artifact             string   `protobuf:"bytes,2,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	Added                []string `protobuf:"bytes,3,rep,name=added,proto3" json:"added,omitempty"`
+	Modified             []string `protobuf:"bytes,4,rep,name=modified,proto3" json:"modified,omitempty"`
+	Deleted              []string `protobuf:"bytes,5,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileChangeEvent) Reset()         { *m = FileChangeEvent{} }
+func (m *FileChangeEvent) String() string { return proto.CompactTextString(m) }
+func (*FileChangeEvent) ProtoMessage()    {}
+func (*FileChangeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{15}
+}
+
+func (m *FileChangeEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileChangeEvent.Unmarshal(m, b)
+}
+func (m *FileChangeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileChangeEvent.Marshal(b, m, deterministic)
+}
+func (m *FileChangeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileChangeEvent.Merge(m, src)
+}
+func (m *FileChangeEvent) XXX_Size() int {
+	return xxx_messageInfo_FileChangeEvent.Size(m)
+}
+func (m *FileChangeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileChangeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileChangeEvent proto.InternalMessageInfo
+
+func (m *FileChangeEvent) GetComponent() string {
+	if m != nil {
+		return m.Component
+	}
+	return ""
+}
+
+func (m *FileChangeEvent) GetArtifact() string {
+	if m != nil {
+		return m.Artifact
+	}
+	return ""
+}
+
+func (m *FileChangeEvent) GetAdded() []string {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
+func (m *FileChangeEvent) GetModified() []string {
+	if m != nil {
+		return m.Modified
+	}
+	return nil
+}
+
+func (m *FileChangeEvent) GetDeleted() []string {
+	if m != nil {
+		return m.Deleted
+	}
+	return nil
+}
+
+// SyncEvent describes the sync of a batch of files to the containers
+// running an artifact
+type SyncEvent struct {
+	Artifact             string      `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	Status               PhaseStatus `protobuf:"varint,2,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	Copied               []string    `protobuf:"bytes,3,rep,name=copied,proto3" json:"copied,omitempty"`
+	Deleted              []string    `protobuf:"bytes,4,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	Timing               *Timing     `protobuf:"bytes,5,opt,name=timing,proto3" json:"timing,omitempty"`
+	ErrCode              ErrorCode   `protobuf:"varint,6,opt,name=errCode,proto3,enum=proto.ErrorCode" json:"errCode,omitempty"`
+	Err                  string      `protobuf:"bytes,7,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SyncEvent) Reset()         { *m = SyncEvent{} }
+func (m *SyncEvent) String() string { return proto.CompactTextString(m) }
+func (*SyncEvent) ProtoMessage()    {}
+func (*SyncEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{16}
+}
+
+func (m *SyncEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncEvent.Unmarshal(m, b)
+}
+func (m *SyncEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncEvent.Marshal(b, m, deterministic)
+}
+func (m *SyncEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncEvent.Merge(m, src)
+}
+func (m *SyncEvent) XXX_Size() int {
+	return xxx_messageInfo_SyncEvent.Size(m)
+}
+func (m *SyncEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncEvent proto.InternalMessageInfo
+
+func (m *SyncEvent) GetArtifact() string {
+	if m != nil {
+		return m.Artifact
+	}
+	return ""
+}
+
+func (m *SyncEvent) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+func (m *SyncEvent) GetCopied() []string {
+	if m != nil {
+		return m.Copied
+	}
+	return nil
+}
+
+func (m *SyncEvent) GetDeleted() []string {
+	if m != nil {
+		return m.Deleted
+	}
+	return nil
+}
+
+func (m *SyncEvent) GetTiming() *Timing {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+func (m *SyncEvent) GetErrCode() ErrorCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrorCode_OK
+}
+
+func (m *SyncEvent) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+// TestEvent describes the tests of the built artifacts
+type TestEvent struct {
+	Status               PhaseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	Timing               *Timing     `protobuf:"bytes,2,opt,name=timing,proto3" json:"timing,omitempty"`
+	ErrCode              ErrorCode   `protobuf:"varint,3,opt,name=errCode,proto3,enum=proto.ErrorCode" json:"errCode,omitempty"`
+	Err                  string      `protobuf:"bytes,4,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *TestEvent) Reset()         { *m = TestEvent{} }
+func (m *TestEvent) String() string { return proto.CompactTextString(m) }
+func (*TestEvent) ProtoMessage()    {}
+func (*TestEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{17}
+}
+
+func (m *TestEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestEvent.Unmarshal(m, b)
+}
+func (m *TestEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestEvent.Marshal(b, m, deterministic)
+}
+func (m *TestEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestEvent.Merge(m, src)
+}
+func (m *TestEvent) XXX_Size() int {
+	return xxx_messageInfo_TestEvent.Size(m)
+}
+func (m *TestEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestEvent proto.InternalMessageInfo
+
+func (m *TestEvent) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+func (m *TestEvent) GetTiming() *Timing {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+func (m *TestEvent) GetErrCode() ErrorCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrorCode_OK
+}
+
+func (m *TestEvent) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+// StatusCheckEvent describes the check that the deployed resources are healthy
+type StatusCheckEvent struct {
+	Status               PhaseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	Timing               *Timing     `protobuf:"bytes,2,opt,name=timing,proto3" json:"timing,omitempty"`
+	ErrCode              ErrorCode   `protobuf:"varint,3,opt,name=errCode,proto3,enum=proto.ErrorCode" json:"errCode,omitempty"`
+	Err                  string      `protobuf:"bytes,4,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *StatusCheckEvent) Reset()         { *m = StatusCheckEvent{} }
+func (m *StatusCheckEvent) String() string { return proto.CompactTextString(m) }
+func (*StatusCheckEvent) ProtoMessage()    {}
+func (*StatusCheckEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{18}
+}
+
+func (m *StatusCheckEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusCheckEvent.Unmarshal(m, b)
+}
+func (m *StatusCheckEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusCheckEvent.Marshal(b, m, deterministic)
+}
+func (m *StatusCheckEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusCheckEvent.Merge(m, src)
+}
+func (m *StatusCheckEvent) XXX_Size() int {
+	return xxx_messageInfo_StatusCheckEvent.Size(m)
+}
+func (m *StatusCheckEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusCheckEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusCheckEvent proto.InternalMessageInfo
+
+func (m *StatusCheckEvent) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+func (m *StatusCheckEvent) GetTiming() *Timing {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+func (m *StatusCheckEvent) GetErrCode() ErrorCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrorCode_OK
+}
+
+func (m *StatusCheckEvent) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+// DevLoopEvent describes an iteration of the dev loop. The first iteration,
+// numbered 0, builds and deploys everything
+type DevLoopEvent struct {
+	Iteration            int32       `protobuf:"varint,1,opt,name=iteration,proto3" json:"iteration,omitempty"`
+	Status               PhaseStatus `protobuf:"varint,2,opt,name=status,proto3,enum=proto.PhaseStatus" json:"status,omitempty"`
+	Timing               *Timing     `protobuf:"bytes,3,opt,name=timing,proto3" json:"timing,omitempty"`
+	ErrCode              ErrorCode   `protobuf:"varint,4,opt,name=errCode,proto3,enum=proto.ErrorCode" json:"errCode,omitempty"`
+	Err                  string      `protobuf:"bytes,5,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *DevLoopEvent) Reset()         { *m = DevLoopEvent{} }
+func (m *DevLoopEvent) String() string { return proto.CompactTextString(m) }
+func (*DevLoopEvent) ProtoMessage()    {}
+func (*DevLoopEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{19}
+}
+
+func (m *DevLoopEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DevLoopEvent.Unmarshal(m, b)
+}
+func (m *DevLoopEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DevLoopEvent.Marshal(b, m, deterministic)
+}
+func (m *DevLoopEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DevLoopEvent.Merge(m, src)
+}
+func (m *DevLoopEvent) XXX_Size() int {
+	return xxx_messageInfo_DevLoopEvent.Size(m)
+}
+func (m *DevLoopEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_DevLoopEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DevLoopEvent proto.InternalMessageInfo
+
+func (m *DevLoopEvent) GetIteration() int32 {
+	if m != nil {
+		return m.Iteration
+	}
+	return 0
+}
+
+func (m *DevLoopEvent) GetStatus() PhaseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PhaseStatus_UNKNOWN_STATUS
+}
+
+func (m *DevLoopEvent) GetTiming() *Timing {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+func (m *DevLoopEvent) GetErrCode() ErrorCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrorCode_OK
+}
+
+func (m *DevLoopEvent) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+// ResourceStatusEvent describes the status of a deployed resource, like
+// `pod/web-6d4b7c9f8-x2x7q`
+type ResourceStatusEvent struct {
+	Resource             string   `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	KubeContext          string   `protobuf:"bytes,3,opt,name=kubeContext,proto3" json:"kubeContext,omitempty"`
+	Status               string   `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Ready                bool     `protobuf:"varint,5,opt,name=ready,proto3" json:"ready,omitempty"`
+	Message              string   `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResourceStatusEvent) Reset()         { *m = ResourceStatusEvent{} }
+func (m *ResourceStatusEvent) String() string { return proto.CompactTextString(m) }
+func (*ResourceStatusEvent) ProtoMessage()    {}
+func (*ResourceStatusEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{20}
+}
+
+func (m *ResourceStatusEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceStatusEvent.Unmarshal(m, b)
+}
+func (m *ResourceStatusEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceStatusEvent.Marshal(b, m, deterministic)
+}
+func (m *ResourceStatusEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceStatusEvent.Merge(m, src)
+}
+func (m *ResourceStatusEvent) XXX_Size() int {
+	return xxx_messageInfo_ResourceStatusEvent.Size(m)
+}
+func (m *ResourceStatusEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceStatusEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceStatusEvent proto.InternalMessageInfo
+
+func (m *ResourceStatusEvent) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *ResourceStatusEvent) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ResourceStatusEvent) GetKubeContext() string {
+	if m != nil {
+		return m.KubeContext
+	}
+	return ""
+}
+
+func (m *ResourceStatusEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ResourceStatusEvent) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *ResourceStatusEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// PortEvent describes a forwarded port. Its status tells if the port
+// was forwarded, lost its pod or was re-established to a new pod
+type PortEvent struct {
+	LocalPort            int32    `protobuf:"varint,1,opt,name=localPort,proto3" json:"localPort,omitempty"`
+	RemotePort           int32    `protobuf:"varint,2,opt,name=remotePort,proto3" json:"remotePort,omitempty"`
+	PodName              string   `protobuf:"bytes,3,opt,name=podName,proto3" json:"podName,omitempty"`
+	ContainerName        string   `protobuf:"bytes,4,opt,name=containerName,proto3" json:"containerName,omitempty"`
+	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	PortName             string   `protobuf:"bytes,6,opt,name=portName,proto3" json:"portName,omitempty"`
+	ResourceType         string   `protobuf:"bytes,7,opt,name=resourceType,proto3" json:"resourceType,omitempty"`
 	ResourceName         string   `protobuf:"bytes,8,opt,name=resourceName,proto3" json:"resourceName,omitempty"`
+	KubeContext          string   `protobuf:"bytes,9,opt,name=kubeContext,proto3" json:"kubeContext,omitempty"`
+	Status               string   `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -547,81 +1514,413 @@ func (m *PortEvent) Reset()         { *m = PortEvent{} }
 func (m *PortEvent) String() string { return proto.CompactTextString(m) }
 func (*PortEvent) ProtoMessage()    {}
 func (*PortEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{10}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{21}
+}
+
+func (m *PortEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PortEvent.Unmarshal(m, b)
+}
+func (m *PortEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PortEvent.Marshal(b, m, deterministic)
+}
+func (m *PortEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PortEvent.Merge(m, src)
+}
+func (m *PortEvent) XXX_Size() int {
+	return xxx_messageInfo_PortEvent.Size(m)
+}
+func (m *PortEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_PortEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PortEvent proto.InternalMessageInfo
+
+func (m *PortEvent) GetLocalPort() int32 {
+	if m != nil {
+		return m.LocalPort
+	}
+	return 0
+}
+
+func (m *PortEvent) GetRemotePort() int32 {
+	if m != nil {
+		return m.RemotePort
+	}
+	return 0
+}
+
+func (m *PortEvent) GetPodName() string {
+	if m != nil {
+		return m.PodName
+	}
+	return ""
+}
+
+func (m *PortEvent) GetContainerName() string {
+	if m != nil {
+		return m.ContainerName
+	}
+	return ""
+}
+
+func (m *PortEvent) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PortEvent) GetPortName() string {
+	if m != nil {
+		return m.PortName
+	}
+	return ""
+}
+
+func (m *PortEvent) GetResourceType() string {
+	if m != nil {
+		return m.ResourceType
+	}
+	return ""
+}
+
+func (m *PortEvent) GetResourceName() string {
+	if m != nil {
+		return m.ResourceName
+	}
+	return ""
+}
+
+func (m *PortEvent) GetKubeContext() string {
+	if m != nil {
+		return m.KubeContext
+	}
+	return ""
+}
+
+func (m *PortEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+// PodDiagnosticEvent explains why a container of the run crashed or doesn't start.
+// Its reason is one of CrashLoopBackOff, OOMKilled, ImagePullBackOff,
+// FailedScheduling or Unready
+type PodDiagnosticEvent struct {
+	PodName              string   `protobuf:"bytes,1,opt,name=podName,proto3" json:"podName,omitempty"`
+	ContainerName        string   `protobuf:"bytes,2,opt,name=containerName,proto3" json:"containerName,omitempty"`
+	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	KubeContext          string   `protobuf:"bytes,4,opt,name=kubeContext,proto3" json:"kubeContext,omitempty"`
+	Reason               string   `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message              string   `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	Logs                 []string `protobuf:"bytes,7,rep,name=logs,proto3" json:"logs,omitempty"`
+	Events               []string `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PodDiagnosticEvent) Reset()         { *m = PodDiagnosticEvent{} }
+func (m *PodDiagnosticEvent) String() string { return proto.CompactTextString(m) }
+func (*PodDiagnosticEvent) ProtoMessage()    {}
+func (*PodDiagnosticEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{22}
+}
+
+func (m *PodDiagnosticEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodDiagnosticEvent.Unmarshal(m, b)
+}
+func (m *PodDiagnosticEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodDiagnosticEvent.Marshal(b, m, deterministic)
+}
+func (m *PodDiagnosticEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodDiagnosticEvent.Merge(m, src)
+}
+func (m *PodDiagnosticEvent) XXX_Size() int {
+	return xxx_messageInfo_PodDiagnosticEvent.Size(m)
+}
+func (m *PodDiagnosticEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodDiagnosticEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodDiagnosticEvent proto.InternalMessageInfo
+
+func (m *PodDiagnosticEvent) GetPodName() string {
+	if m != nil {
+		return m.PodName
+	}
+	return ""
+}
+
+func (m *PodDiagnosticEvent) GetContainerName() string {
+	if m != nil {
+		return m.ContainerName
+	}
+	return ""
+}
+
+func (m *PodDiagnosticEvent) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PodDiagnosticEvent) GetKubeContext() string {
+	if m != nil {
+		return m.KubeContext
+	}
+	return ""
+}
+
+func (m *PodDiagnosticEvent) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *PodDiagnosticEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *PodDiagnosticEvent) GetLogs() []string {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+func (m *PodDiagnosticEvent) GetEvents() []string {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+// TriggerRequest enables or disables an automatic action of the dev loop
+type TriggerRequest struct {
+	Enabled              bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TriggerRequest) Reset()         { *m = TriggerRequest{} }
+func (m *TriggerRequest) String() string { return proto.CompactTextString(m) }
+func (*TriggerRequest) ProtoMessage()    {}
+func (*TriggerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{23}
+}
+
+func (m *TriggerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TriggerRequest.Unmarshal(m, b)
+}
+func (m *TriggerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TriggerRequest.Marshal(b, m, deterministic)
+}
+func (m *TriggerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerRequest.Merge(m, src)
+}
+func (m *TriggerRequest) XXX_Size() int {
+	return xxx_messageInfo_TriggerRequest.Size(m)
+}
+func (m *TriggerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerRequest proto.InternalMessageInfo
+
+func (m *TriggerRequest) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+// BuildRequest lists the artifacts to build. All the artifacts are built
+// if it's empty
+type BuildRequest struct {
+	Artifacts            []string `protobuf:"bytes,1,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildRequest) Reset()         { *m = BuildRequest{} }
+func (m *BuildRequest) String() string { return proto.CompactTextString(m) }
+func (*BuildRequest) ProtoMessage()    {}
+func (*BuildRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{24}
+}
+
+func (m *BuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildRequest.Unmarshal(m, b)
+}
+func (m *BuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildRequest.Marshal(b, m, deterministic)
+}
+func (m *BuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildRequest.Merge(m, src)
+}
+func (m *BuildRequest) XXX_Size() int {
+	return xxx_messageInfo_BuildRequest.Size(m)
+}
+func (m *BuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildRequest proto.InternalMessageInfo
+
+func (m *BuildRequest) GetArtifacts() []string {
+	if m != nil {
+		return m.Artifacts
+	}
+	return nil
+}
+
+// PendingSync lists the files waiting to be synced to the containers
+// running an artifact
+type PendingSync struct {
+	Artifact             string   `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	Copied               []string `protobuf:"bytes,2,rep,name=copied,proto3" json:"copied,omitempty"`
+	Deleted              []string `protobuf:"bytes,3,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingSync) Reset()         { *m = PendingSync{} }
+func (m *PendingSync) String() string { return proto.CompactTextString(m) }
+func (*PendingSync) ProtoMessage()    {}
+func (*PendingSync) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{25}
 }
 
-func (m *PortEvent) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PortEvent.Unmarshal(m, b)
+func (m *PendingSync) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingSync.Unmarshal(m, b)
 }
-func (m *PortEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PortEvent.Marshal(b, m, deterministic)
+func (m *PendingSync) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingSync.Marshal(b, m, deterministic)
 }
-func (m *PortEvent) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PortEvent.Merge(m, src)
+func (m *PendingSync) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingSync.Merge(m, src)
 }
-func (m *PortEvent) XXX_Size() int {
-	return xxx_messageInfo_PortEvent.Size(m)
+func (m *PendingSync) XXX_Size() int {
+	return xxx_messageInfo_PendingSync.Size(m)
 }
-func (m *PortEvent) XXX_DiscardUnknown() {
-	xxx_messageInfo_PortEvent.DiscardUnknown(m)
+func (m *PendingSync) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingSync.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PortEvent proto.InternalMessageInfo
+var xxx_messageInfo_PendingSync proto.InternalMessageInfo
 
-func (m *PortEvent) GetLocalPort() int32 {
+func (m *PendingSync) GetArtifact() string {
 	if m != nil {
-		return m.LocalPort
+		return m.Artifact
 	}
-	return 0
+	return ""
 }
 
-func (m *PortEvent) GetRemotePort() int32 {
+func (m *PendingSync) GetCopied() []string {
 	if m != nil {
-		return m.RemotePort
+		return m.Copied
 	}
-	return 0
+	return nil
 }
 
-func (m *PortEvent) GetPodName() string {
+func (m *PendingSync) GetDeleted() []string {
 	if m != nil {
-		return m.PodName
+		return m.Deleted
 	}
-	return ""
+	return nil
 }
 
-func (m *PortEvent) GetContainerName() string {
+// PendingChanges describes the changes collected by the dev loop that
+// were not applied yet, because the automatic action that would apply
+// them is disabled
+type PendingChanges struct {
+	AutoBuild            bool           `protobuf:"varint,1,opt,name=autoBuild,proto3" json:"autoBuild,omitempty"`
+	AutoSync             bool           `protobuf:"varint,2,opt,name=autoSync,proto3" json:"autoSync,omitempty"`
+	AutoDeploy           bool           `protobuf:"varint,3,opt,name=autoDeploy,proto3" json:"autoDeploy,omitempty"`
+	Rebuild              []string       `protobuf:"bytes,4,rep,name=rebuild,proto3" json:"rebuild,omitempty"`
+	Sync                 []*PendingSync `protobuf:"bytes,5,rep,name=sync,proto3" json:"sync,omitempty"`
+	Redeploy             bool           `protobuf:"varint,6,opt,name=redeploy,proto3" json:"redeploy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PendingChanges) Reset()         { *m = PendingChanges{} }
+func (m *PendingChanges) String() string { return proto.CompactTextString(m) }
+func (*PendingChanges) ProtoMessage()    {}
+func (*PendingChanges) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{26}
+}
+
+func (m *PendingChanges) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingChanges.Unmarshal(m, b)
+}
+func (m *PendingChanges) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingChanges.Marshal(b, m, deterministic)
+}
+func (m *PendingChanges) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingChanges.Merge(m, src)
+}
+func (m *PendingChanges) XXX_Size() int {
+	return xxx_messageInfo_PendingChanges.Size(m)
+}
+func (m *PendingChanges) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingChanges.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingChanges proto.InternalMessageInfo
+
+func (m *PendingChanges) GetAutoBuild() bool {
 	if m != nil {
-		return m.ContainerName
+		return m.AutoBuild
 	}
-	return ""
+	return false
 }
 
-func (m *PortEvent) GetNamespace() string {
+func (m *PendingChanges) GetAutoSync() bool {
 	if m != nil {
-		return m.Namespace
+		return m.AutoSync
 	}
-	return ""
+	return false
 }
 
-func (m *PortEvent) GetPortName() string {
+func (m *PendingChanges) GetAutoDeploy() bool {
 	if m != nil {
-		return m.PortName
+		return m.AutoDeploy
 	}
-	return ""
+	return false
 }
 
-func (m *PortEvent) GetResourceType() string {
+func (m *PendingChanges) GetRebuild() []string {
 	if m != nil {
-		return m.ResourceType
+		return m.Rebuild
 	}
-	return ""
+	return nil
 }
 
-func (m *PortEvent) GetResourceName() string {
+func (m *PendingChanges) GetSync() []*PendingSync {
 	if m != nil {
-		return m.ResourceName
+		return m.Sync
 	}
-	return ""
+	return nil
+}
+
+func (m *PendingChanges) GetRedeploy() bool {
+	if m != nil {
+		return m.Redeploy
+	}
+	return false
 }
 
 type LogEntry struct {
@@ -637,7 +1936,7 @@ func (m *LogEntry) Reset()         { *m = LogEntry{} }
 func (m *LogEntry) String() string { return proto.CompactTextString(m) }
 func (*LogEntry) ProtoMessage()    {}
 func (*LogEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{11}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{27}
 }
 
 func (m *LogEntry) XXX_Unmarshal(b []byte) error {
@@ -679,76 +1978,260 @@ func (m *LogEntry) GetEntry() string {
 	return ""
 }
 
+// LogFilter selects the lines of logs printed by Skaffold. It replaces
+// the filter given on the command line
+type LogFilter struct {
+	Images               []string `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	Containers           []string `protobuf:"bytes,2,rep,name=containers,proto3" json:"containers,omitempty"`
+	Selector             string   `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
+	Include              string   `protobuf:"bytes,4,opt,name=include,proto3" json:"include,omitempty"`
+	Exclude              string   `protobuf:"bytes,5,opt,name=exclude,proto3" json:"exclude,omitempty"`
+	Level                string   `protobuf:"bytes,6,opt,name=level,proto3" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogFilter) Reset()         { *m = LogFilter{} }
+func (m *LogFilter) String() string { return proto.CompactTextString(m) }
+func (*LogFilter) ProtoMessage()    {}
+func (*LogFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{28}
+}
+
+func (m *LogFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogFilter.Unmarshal(m, b)
+}
+func (m *LogFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogFilter.Marshal(b, m, deterministic)
+}
+func (m *LogFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogFilter.Merge(m, src)
+}
+func (m *LogFilter) XXX_Size() int {
+	return xxx_messageInfo_LogFilter.Size(m)
+}
+func (m *LogFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogFilter proto.InternalMessageInfo
+
+func (m *LogFilter) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+func (m *LogFilter) GetContainers() []string {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+func (m *LogFilter) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *LogFilter) GetInclude() string {
+	if m != nil {
+		return m.Include
+	}
+	return ""
+}
+
+func (m *LogFilter) GetExclude() string {
+	if m != nil {
+		return m.Exclude
+	}
+	return ""
+}
+
+func (m *LogFilter) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
 func init() {
+	proto.RegisterEnum("proto.PhaseStatus", PhaseStatus_name, PhaseStatus_value)
+	proto.RegisterEnum("proto.ErrorCode", ErrorCode_name, ErrorCode_value)
 	proto.RegisterType((*StateResponse)(nil), "proto.StateResponse")
 	proto.RegisterType((*Response)(nil), "proto.Response")
 	proto.RegisterType((*Request)(nil), "proto.Request")
 	proto.RegisterType((*State)(nil), "proto.State")
 	proto.RegisterMapType((map[string]*PortEvent)(nil), "proto.State.ForwardedPortsEntry")
+	proto.RegisterMapType((map[string]*ResourceStatusEvent)(nil), "proto.State.ResourcesEntry")
+	proto.RegisterType((*Timing)(nil), "proto.Timing")
 	proto.RegisterType((*BuildState)(nil), "proto.BuildState")
 	proto.RegisterMapType((map[string]string)(nil), "proto.BuildState.ArtifactsEntry")
+	proto.RegisterType((*TestState)(nil), "proto.TestState")
+	proto.RegisterType((*SyncState)(nil), "proto.SyncState")
+	proto.RegisterMapType((map[string]*SyncEvent)(nil), "proto.SyncState.ArtifactsEntry")
+	proto.RegisterType((*StatusCheckState)(nil), "proto.StatusCheckState")
+	proto.RegisterType((*DevLoopState)(nil), "proto.DevLoopState")
 	proto.RegisterType((*DeployState)(nil), "proto.DeployState")
+	proto.RegisterMapType((map[string]string)(nil), "proto.DeployState.KubeContextsEntry")
 	proto.RegisterType((*Event)(nil), "proto.Event")
 	proto.RegisterType((*MetaEvent)(nil), "proto.MetaEvent")
 	proto.RegisterType((*BuildEvent)(nil), "proto.BuildEvent")
 	proto.RegisterType((*DeployEvent)(nil), "proto.DeployEvent")
+	proto.RegisterType((*FileChangeEvent)(nil), "proto.FileChangeEvent")
+	proto.RegisterType((*SyncEvent)(nil), "proto.SyncEvent")
+	proto.RegisterType((*TestEvent)(nil), "proto.TestEvent")
+	proto.RegisterType((*StatusCheckEvent)(nil), "proto.StatusCheckEvent")
+	proto.RegisterType((*DevLoopEvent)(nil), "proto.DevLoopEvent")
+	proto.RegisterType((*ResourceStatusEvent)(nil), "proto.ResourceStatusEvent")
 	proto.RegisterType((*PortEvent)(nil), "proto.PortEvent")
+	proto.RegisterType((*PodDiagnosticEvent)(nil), "proto.PodDiagnosticEvent")
+	proto.RegisterType((*TriggerRequest)(nil), "proto.TriggerRequest")
+	proto.RegisterType((*BuildRequest)(nil), "proto.BuildRequest")
+	proto.RegisterType((*PendingSync)(nil), "proto.PendingSync")
+	proto.RegisterType((*PendingChanges)(nil), "proto.PendingChanges")
 	proto.RegisterType((*LogEntry)(nil), "proto.LogEntry")
+	proto.RegisterType((*LogFilter)(nil), "proto.LogFilter")
 }
 
 func init() { proto.RegisterFile("skaffold.proto", fileDescriptor_4f2d38e344f9dbf5) }
 
 var fileDescriptor_4f2d38e344f9dbf5 = []byte{
-	// 781 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0x2e, 0x29, 0x53, 0x16, 0x47, 0xb2, 0x6c, 0x6f, 0x0d, 0x43, 0x60, 0xd5, 0xd6, 0x5d, 0xb4,
-	0x85, 0xe1, 0x83, 0xe4, 0x9f, 0xa2, 0x35, 0x8c, 0xa2, 0x40, 0x1d, 0x3b, 0xf1, 0xc1, 0x0e, 0x02,
-	0xca, 0xf7, 0x60, 0x2d, 0x8d, 0x14, 0xc1, 0x14, 0x97, 0x21, 0x57, 0x0a, 0x74, 0xc9, 0x21, 0xc7,
-	0xe4, 0x90, 0x43, 0x9e, 0x26, 0xcf, 0x91, 0x57, 0x08, 0xf2, 0x1c, 0xc1, 0xfe, 0x89, 0xa4, 0x2d,
-	0x9d, 0xc8, 0x9d, 0xef, 0xfb, 0x66, 0xe7, 0x67, 0x67, 0xa0, 0x99, 0xdd, 0xb3, 0xe1, 0x90, 0x47,
-	0x83, 0x4e, 0x92, 0x72, 0xc1, 0x89, 0xa7, 0x3e, 0x41, 0x7b, 0xc4, 0xf9, 0x28, 0xc2, 0x2e, 0x4b,
-	0xc6, 0x5d, 0x16, 0xc7, 0x5c, 0x30, 0x31, 0xe6, 0x71, 0xa6, 0x49, 0xc1, 0xaf, 0x06, 0x55, 0xa7,
-	0xbb, 0xe9, 0xb0, 0x2b, 0xc6, 0x13, 0xcc, 0x04, 0x9b, 0x24, 0x86, 0xf0, 0xd3, 0x43, 0x02, 0x4e,
-	0x12, 0x31, 0xd7, 0x20, 0x3d, 0x81, 0x8d, 0x9e, 0x60, 0x02, 0x43, 0xcc, 0x12, 0x1e, 0x67, 0x48,
-	0x28, 0x78, 0x99, 0x34, 0xb4, 0x9c, 0x3d, 0x67, 0xbf, 0x7e, 0xdc, 0xd0, 0xbc, 0x8e, 0x26, 0x69,
-	0x88, 0xb6, 0xa1, 0xb6, 0xe0, 0x6f, 0x41, 0x65, 0x92, 0x8d, 0x14, 0xdb, 0x0f, 0xe5, 0x2f, 0xfd,
-	0x19, 0xd6, 0x43, 0x7c, 0x3d, 0xc5, 0x4c, 0x10, 0x02, 0x6b, 0x31, 0x9b, 0xa0, 0x41, 0xd5, 0x3f,
-	0xfd, 0xe8, 0x82, 0xa7, 0xbc, 0x91, 0x23, 0x80, 0xbb, 0xe9, 0x38, 0x1a, 0xf4, 0x0a, 0xf7, 0x6d,
-	0x9b, 0xfb, 0xce, 0x17, 0x40, 0x58, 0x20, 0x91, 0xbf, 0xa0, 0x3e, 0xc0, 0x24, 0xe2, 0x73, 0xad,
-	0x71, 0x95, 0x86, 0x18, 0xcd, 0x45, 0x8e, 0x84, 0x45, 0x1a, 0xb9, 0x82, 0xe6, 0x90, 0xa7, 0x6f,
-	0x58, 0x3a, 0xc0, 0xc1, 0x0b, 0x9e, 0x8a, 0xac, 0x55, 0xd9, 0xab, 0xec, 0xd7, 0x8f, 0xf7, 0x8a,
-	0xc9, 0x75, 0x9e, 0x96, 0x28, 0x97, 0xb1, 0x48, 0xe7, 0xe1, 0x03, 0x5d, 0xd0, 0x83, 0x1f, 0x97,
-	0xd0, 0x64, 0x11, 0xee, 0x71, 0x6e, 0x8b, 0x70, 0x8f, 0x73, 0xf2, 0x27, 0x78, 0x33, 0x16, 0x4d,
-	0x6d, 0x88, 0x5b, 0xe6, 0x26, 0xa9, 0xb9, 0x9c, 0x61, 0x2c, 0x42, 0x0d, 0x9f, 0xb9, 0xa7, 0x0e,
-	0x7d, 0xef, 0x00, 0xe4, 0xf9, 0x92, 0xff, 0xc0, 0x67, 0xa9, 0x18, 0x0f, 0x59, 0x5f, 0x64, 0x2d,
-	0xa7, 0x14, 0x68, 0xce, 0xea, 0xfc, 0x6f, 0x29, 0x3a, 0xd0, 0x5c, 0x12, 0xfc, 0x0b, 0xcd, 0x32,
-	0xb8, 0x24, 0xbc, 0x9d, 0x62, 0x78, 0x7e, 0x31, 0x98, 0x3f, 0xa0, 0x5e, 0xa8, 0x23, 0xd9, 0x85,
-	0xaa, 0xec, 0xf9, 0x34, 0x33, 0x6a, 0x73, 0xa2, 0xdf, 0x1c, 0xf0, 0x54, 0x22, 0xe4, 0x10, 0xfc,
-	0x09, 0x0a, 0xa6, 0x0e, 0xa6, 0x89, 0x36, 0xdb, 0x1b, 0x6b, 0xbf, 0xfa, 0x21, 0xcc, 0x49, 0xe4,
-	0xc4, 0xf4, 0x5d, 0x4b, 0xdc, 0xc7, 0x7d, 0xb7, 0x9a, 0x02, 0x8d, 0xfc, 0x6d, 0x3b, 0xaf, 0x55,
-	0x95, 0x25, 0x9d, 0xb7, 0xb2, 0x22, 0x51, 0x86, 0x97, 0xd8, 0xa2, 0xb7, 0xd6, 0x96, 0x37, 0x43,
-	0x86, 0xb7, 0x20, 0x9d, 0x37, 0x00, 0x50, 0xfe, 0xbc, 0x14, 0xf3, 0x04, 0xe9, 0x6f, 0xe0, 0x2f,
-	0xd2, 0x90, 0x65, 0x43, 0x59, 0x51, 0x53, 0x0c, 0x7d, 0xa0, 0xa1, 0x69, 0x9f, 0xe6, 0x04, 0x50,
-	0xb3, 0xbd, 0x30, 0xb4, 0xc5, 0xb9, 0x50, 0x4d, 0xb7, 0x58, 0x4d, 0xd9, 0x20, 0x4c, 0x53, 0x95,
-	0x94, 0x1f, 0xca, 0x5f, 0xfa, 0x8f, 0x6d, 0x83, 0x76, 0xba, 0xa2, 0x0d, 0x56, 0xe8, 0xe6, 0xc2,
-	0x0f, 0x2e, 0xf8, 0x8b, 0xc4, 0x48, 0x1b, 0xfc, 0x88, 0xf7, 0x59, 0x24, 0x2d, 0x4a, 0xea, 0x85,
-	0xb9, 0x81, 0xfc, 0x02, 0x90, 0xe2, 0x84, 0x0b, 0x54, 0xb0, 0xab, 0xe0, 0x82, 0x85, 0xb4, 0x60,
-	0x3d, 0xe1, 0x83, 0xe7, 0x72, 0x82, 0x75, 0x68, 0xf6, 0x48, 0x7e, 0x87, 0x8d, 0x3e, 0x8f, 0x05,
-	0x1b, 0xc7, 0x98, 0x2a, 0x7c, 0x4d, 0xe1, 0x65, 0xa3, 0xbc, 0x5d, 0x8e, 0x7c, 0x96, 0xb0, 0x3e,
-	0xb6, 0x3c, 0xc5, 0xc8, 0x0d, 0xb2, 0x50, 0xb2, 0xe8, 0x4a, 0x5e, 0xd5, 0x85, 0xb2, 0x67, 0x42,
-	0xa1, 0x91, 0x62, 0xc6, 0xa7, 0x69, 0x1f, 0x6f, 0xe7, 0x09, 0xb6, 0xd6, 0x15, 0x5e, 0xb2, 0x15,
-	0x39, 0xca, 0x47, 0xad, 0xcc, 0x91, 0x36, 0xfa, 0x16, 0x6a, 0xd7, 0x7c, 0xa4, 0xa7, 0xe0, 0x14,
-	0xfc, 0xc5, 0x6a, 0x34, 0x0f, 0x35, 0xe8, 0xe8, 0xdd, 0xd8, 0xb1, 0xbb, 0xb1, 0x73, 0x6b, 0x19,
-	0x61, 0x4e, 0x96, 0x3b, 0x11, 0x0b, 0x6f, 0xd5, 0xee, 0x44, 0x33, 0xc8, 0x58, 0x7e, 0x1a, 0x95,
-	0xc2, 0xd3, 0x38, 0xfe, 0xec, 0xc2, 0x66, 0xcf, 0x2c, 0xf5, 0x1e, 0xa6, 0xb3, 0x71, 0x1f, 0xc9,
-	0x13, 0xa8, 0x3d, 0x43, 0x61, 0xc6, 0xeb, 0x51, 0x00, 0x97, 0x72, 0x39, 0x07, 0xa5, 0xb5, 0x4b,
-	0xb7, 0xdf, 0x7d, 0xf9, 0xfa, 0xc9, 0xad, 0x13, 0xbf, 0x3b, 0x3b, 0xea, 0xaa, 0x15, 0x4c, 0x2e,
-	0xa0, 0xa6, 0xae, 0xbf, 0xe6, 0x23, 0xb2, 0x69, 0xc8, 0x36, 0xd3, 0xe0, 0xa1, 0x81, 0x12, 0xe5,
-	0xa0, 0x41, 0x40, 0x3a, 0x50, 0xf1, 0x66, 0xfb, 0xce, 0xa1, 0x43, 0xae, 0xa1, 0x7a, 0xc5, 0xe2,
-	0x41, 0x84, 0xa4, 0x94, 0x53, 0xb0, 0x22, 0x2c, 0xda, 0x56, 0x7e, 0x76, 0xe9, 0x76, 0xee, 0xa7,
-	0xfb, 0x4a, 0x39, 0x38, 0x73, 0x0e, 0xc8, 0x0d, 0x78, 0x6a, 0x0e, 0x56, 0x66, 0xb5, 0xca, 0xed,
-	0x8e, 0x72, 0xdb, 0xa4, 0x2a, 0x3f, 0x35, 0xf3, 0x67, 0xce, 0xc1, 0x5d, 0x55, 0xb1, 0x4e, 0xbe,
-	0x07, 0x00, 0x00, 0xff, 0xff, 0x65, 0xb6, 0xba, 0xdd, 0x16, 0x07, 0x00, 0x00,
+	// 2070 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xcd, 0x6f, 0x23, 0x59,
+	0x11, 0x4f, 0xfb, 0x23, 0x76, 0x97, 0x1d, 0xc7, 0x79, 0xce, 0xcc, 0x7a, 0xbc, 0x61, 0x76, 0x68,
+	0x2d, 0xab, 0x51, 0x84, 0x9c, 0xd9, 0x99, 0x85, 0x9d, 0x1d, 0xc1, 0xa2, 0x8c, 0xed, 0x99, 0x8c,
+	0xe2, 0x75, 0xc2, 0xb3, 0xc3, 0xee, 0x0a, 0x41, 0xd4, 0x71, 0xbf, 0x78, 0x5a, 0x69, 0xf7, 0x33,
+	0xdd, 0xed, 0xb0, 0xbe, 0x70, 0xd8, 0x23, 0x57, 0x0e, 0x48, 0x80, 0xc4, 0x81, 0x13, 0x07, 0x04,
+	0x57, 0x0e, 0x1c, 0x91, 0xb8, 0x73, 0xe4, 0x86, 0x38, 0x71, 0xe4, 0x2f, 0x40, 0xef, 0xb3, 0xbb,
+	0xfd, 0xb1, 0x4e, 0x24, 0x90, 0xe6, 0x64, 0x57, 0xd5, 0xaf, 0x7e, 0x5d, 0xaf, 0x5e, 0xbd, 0xaa,
+	0xd7, 0x0d, 0x95, 0xf0, 0xca, 0xbe, 0xbc, 0xa4, 0x9e, 0xd3, 0x9c, 0x04, 0x34, 0xa2, 0x28, 0xcf,
+	0x7f, 0x1a, 0x7b, 0x23, 0x4a, 0x47, 0x1e, 0x39, 0xb0, 0x27, 0xee, 0x81, 0xed, 0xfb, 0x34, 0xb2,
+	0x23, 0x97, 0xfa, 0xa1, 0x00, 0x35, 0xde, 0x91, 0x56, 0x2e, 0x5d, 0x4c, 0x2f, 0x0f, 0x22, 0x77,
+	0x4c, 0xc2, 0xc8, 0x1e, 0x4f, 0x24, 0xe0, 0xfe, 0x3c, 0xc0, 0x99, 0x06, 0x9c, 0x41, 0xda, 0xdf,
+	0x9e, 0xb7, 0x93, 0xf1, 0x24, 0x9a, 0x09, 0xa3, 0xf5, 0x04, 0xb6, 0xfa, 0x91, 0x1d, 0x11, 0x4c,
+	0xc2, 0x09, 0xf5, 0x43, 0x82, 0x2c, 0xc8, 0x87, 0x4c, 0x51, 0x37, 0x1e, 0x18, 0x0f, 0x4b, 0x8f,
+	0xcb, 0x02, 0xd7, 0x14, 0x20, 0x61, 0xb2, 0xf6, 0xa0, 0xa8, 0xf1, 0x55, 0xc8, 0x8e, 0xc3, 0x11,
+	0x47, 0x9b, 0x98, 0xfd, 0xb5, 0xbe, 0x06, 0x05, 0x4c, 0x7e, 0x32, 0x25, 0x61, 0x84, 0x10, 0xe4,
+	0x7c, 0x7b, 0x4c, 0xa4, 0x95, 0xff, 0xb7, 0xbe, 0xcc, 0x43, 0x9e, 0xb3, 0xa1, 0xf7, 0x01, 0x2e,
+	0xa6, 0xae, 0xe7, 0xf4, 0x13, 0xcf, 0xdb, 0x91, 0xcf, 0x7b, 0xae, 0x0d, 0x38, 0x01, 0x42, 0x1f,
+	0x40, 0xc9, 0x21, 0x13, 0x8f, 0xce, 0x84, 0x4f, 0x86, 0xfb, 0x20, 0xe9, 0xd3, 0x8e, 0x2d, 0x38,
+	0x09, 0x43, 0x47, 0x50, 0xb9, 0xa4, 0xc1, 0x4f, 0xed, 0xc0, 0x21, 0xce, 0x29, 0x0d, 0xa2, 0xb0,
+	0x9e, 0x7d, 0x90, 0x7d, 0x58, 0x7a, 0xfc, 0x20, 0xb9, 0xb8, 0xe6, 0x8b, 0x14, 0xa4, 0xe3, 0x47,
+	0xc1, 0x0c, 0xcf, 0xf9, 0xa1, 0x26, 0x98, 0x11, 0x09, 0x23, 0xf1, 0xf4, 0x1c, 0x7f, 0x7a, 0x55,
+	0x92, 0x0c, 0x94, 0x1e, 0xc7, 0x10, 0x86, 0x0f, 0x67, 0xfe, 0x50, 0xe0, 0xf3, 0x29, 0x7c, 0x5f,
+	0xe9, 0x71, 0x0c, 0x41, 0x2d, 0xa8, 0xb2, 0x14, 0x4f, 0xc3, 0xd6, 0x6b, 0x32, 0xbc, 0x12, 0x6e,
+	0x9b, 0xdc, 0xed, 0xad, 0x44, 0xac, 0x49, 0x33, 0x5e, 0x70, 0x40, 0x1f, 0x42, 0xd9, 0x21, 0xd7,
+	0x5d, 0x4a, 0x27, 0x82, 0xa0, 0xc0, 0x09, 0x6a, 0x3a, 0x4b, 0xb1, 0x09, 0xa7, 0x80, 0xe8, 0x23,
+	0x30, 0x03, 0x12, 0xd2, 0x69, 0x30, 0x24, 0x61, 0xbd, 0xc8, 0x53, 0xf4, 0x76, 0x2a, 0x45, 0x58,
+	0x59, 0x45, 0x76, 0x62, 0x74, 0xa3, 0x0f, 0xb5, 0x25, 0xf9, 0x63, 0xd5, 0x71, 0x45, 0x66, 0xaa,
+	0x3a, 0xae, 0xc8, 0x0c, 0xbd, 0x07, 0xf9, 0x6b, 0xdb, 0x9b, 0xaa, 0xbd, 0x53, 0xd9, 0x60, 0x3e,
+	0x9d, 0x6b, 0xe2, 0x47, 0x58, 0x98, 0x9f, 0x65, 0x9e, 0x1a, 0x8d, 0xcf, 0xa0, 0x92, 0x7e, 0xe2,
+	0x12, 0xbe, 0x47, 0x69, 0xbe, 0x86, 0xe4, 0x53, 0x7e, 0x22, 0x5d, 0xf3, 0xcc, 0xd6, 0x1f, 0x0d,
+	0xd8, 0x1c, 0xb8, 0x63, 0xd7, 0x1f, 0xa1, 0xa7, 0x60, 0x86, 0x91, 0x1d, 0x44, 0x03, 0x77, 0xac,
+	0x8a, 0xb0, 0xd1, 0x14, 0x47, 0xa6, 0xa9, 0x8e, 0x4c, 0x73, 0xa0, 0xce, 0x1c, 0x8e, 0xc1, 0xe8,
+	0x03, 0x28, 0x10, 0xdf, 0xe1, 0x7e, 0x99, 0xb5, 0x7e, 0x0a, 0x8a, 0xbe, 0x05, 0x45, 0x75, 0x40,
+	0xeb, 0x59, 0xee, 0x76, 0x6f, 0xc1, 0xad, 0x2d, 0x01, 0x58, 0x43, 0xad, 0x9f, 0x1b, 0x00, 0xf1,
+	0xa1, 0x40, 0x1f, 0x83, 0x69, 0x07, 0x91, 0x7b, 0x69, 0x0f, 0xa3, 0xb0, 0x6e, 0xa4, 0xaa, 0x39,
+	0x46, 0x35, 0x0f, 0x15, 0x44, 0xee, 0x97, 0x76, 0x69, 0x7c, 0x07, 0x2a, 0x69, 0xe3, 0x92, 0xd4,
+	0xee, 0x26, 0x53, 0x6b, 0x26, 0xd3, 0xf7, 0x21, 0x98, 0xba, 0xdc, 0xd1, 0x3e, 0x6c, 0x8a, 0x12,
+	0xe4, 0xbe, 0x15, 0x7d, 0x1c, 0x4f, 0x5f, 0xdb, 0xa1, 0xcc, 0x3f, 0x96, 0x08, 0xeb, 0x57, 0x06,
+	0x98, 0xba, 0xf0, 0xd1, 0x77, 0x17, 0x17, 0xf1, 0xce, 0xfc, 0xe9, 0xf8, 0x8a, 0x35, 0xf4, 0x6e,
+	0xb0, 0x86, 0x15, 0xe5, 0xc6, 0xe8, 0x17, 0x8a, 0xe2, 0x63, 0xa8, 0xce, 0x9f, 0xae, 0x5b, 0x2d,
+	0xee, 0x33, 0x28, 0x27, 0x0f, 0x17, 0xda, 0x03, 0xd3, 0x8d, 0x88, 0xdc, 0x6a, 0xe6, 0x9e, 0xc7,
+	0xb1, 0x22, 0xc1, 0x9c, 0x59, 0xcb, 0xfc, 0x27, 0x03, 0x4a, 0x89, 0xee, 0x86, 0xee, 0xa6, 0xa2,
+	0x32, 0x15, 0x0e, 0x1d, 0x41, 0xf9, 0x6a, 0x7a, 0x41, 0x5a, 0xd4, 0x8f, 0xc8, 0x17, 0x11, 0x63,
+	0x66, 0x39, 0x7d, 0x77, 0xb1, 0x3f, 0x36, 0x8f, 0x13, 0x30, 0x91, 0xd8, 0x94, 0x67, 0xe3, 0x7b,
+	0xb0, 0xb3, 0x00, 0xb9, 0x55, 0x89, 0xfc, 0x21, 0x0f, 0x79, 0x9e, 0x61, 0xf4, 0x08, 0xcc, 0x31,
+	0x89, 0x6c, 0x2e, 0xd4, 0x8d, 0xd4, 0x36, 0x7c, 0xa2, 0xf4, 0x47, 0x1b, 0x38, 0x06, 0xa1, 0x27,
+	0x72, 0x30, 0x08, 0x97, 0xcc, 0xe2, 0x60, 0x50, 0x3e, 0x09, 0x18, 0xfa, 0xb6, 0x1a, 0x0d, 0xc2,
+	0x2b, 0xbb, 0x64, 0x34, 0x28, 0xb7, 0x24, 0x90, 0x85, 0x37, 0x51, 0xcd, 0xa7, 0x9e, 0x5b, 0xde,
+	0x94, 0x58, 0x78, 0x1a, 0x84, 0x8e, 0x01, 0x4d, 0xa8, 0xd3, 0x76, 0xed, 0x91, 0x4f, 0xc3, 0xc8,
+	0x15, 0x85, 0x24, 0xbb, 0xfb, 0x3d, 0xed, 0x3a, 0x0f, 0x38, 0xda, 0xc0, 0x4b, 0xdc, 0xd0, 0x73,
+	0xd8, 0xbe, 0x74, 0x3d, 0xd2, 0x7a, 0x6d, 0xfb, 0x23, 0x22, 0x98, 0x44, 0xc3, 0xbf, 0x2b, 0x99,
+	0x5e, 0xa4, 0xad, 0x47, 0x1b, 0x78, 0xde, 0x81, 0x2d, 0x21, 0x54, 0x05, 0x5d, 0x2f, 0xa4, 0x96,
+	0xa0, 0x0b, 0x9d, 0x2d, 0x41, 0x83, 0x98, 0x07, 0x1b, 0x52, 0xc2, 0xa3, 0xb8, 0x30, 0xc7, 0xb4,
+	0x87, 0x06, 0xa1, 0x4e, 0x6a, 0x32, 0x09, 0x47, 0x73, 0xd5, 0x64, 0x52, 0xfe, 0x0b, 0x2e, 0xe8,
+	0x23, 0x3d, 0x9b, 0x04, 0x05, 0x2c, 0x9b, 0x4d, 0xca, 0x3d, 0x05, 0x45, 0x3d, 0xa8, 0x05, 0x8b,
+	0x5d, 0xbd, 0x5e, 0x5a, 0xd7, 0xf7, 0x8f, 0x36, 0xf0, 0x32, 0xc7, 0xe7, 0x65, 0x00, 0xc2, 0xfe,
+	0x9c, 0x47, 0xb3, 0x09, 0xb1, 0xbe, 0x0e, 0xa6, 0xae, 0x46, 0x56, 0xd6, 0x84, 0x55, 0xbc, 0x2c,
+	0x75, 0x21, 0x58, 0xff, 0x50, 0x2d, 0x58, 0x80, 0x1a, 0x50, 0x54, 0xbd, 0x48, 0xe2, 0xb4, 0x9c,
+	0x38, 0xa0, 0x99, 0xd4, 0x01, 0xad, 0x42, 0x96, 0x04, 0x01, 0x2f, 0x4e, 0x13, 0xb3, 0xbf, 0xec,
+	0x46, 0x33, 0x89, 0x4f, 0x7c, 0x3d, 0xb7, 0xb2, 0x17, 0x24, 0x61, 0xe8, 0x1b, 0xb0, 0x19, 0xf1,
+	0xf1, 0x25, 0xcb, 0x6e, 0x4b, 0x6d, 0x1e, 0x57, 0x62, 0x69, 0x44, 0xfb, 0x50, 0x20, 0x41, 0xd0,
+	0xa2, 0x8e, 0xb8, 0x45, 0x54, 0xf4, 0x26, 0x77, 0x82, 0x80, 0x72, 0x3d, 0x56, 0x00, 0xeb, 0x9f,
+	0xba, 0xc7, 0x88, 0xe5, 0xad, 0xea, 0x31, 0x72, 0x09, 0x99, 0x78, 0x09, 0x0f, 0xa0, 0x94, 0xe8,
+	0x1d, 0x72, 0x71, 0x49, 0xd5, 0x9b, 0xb3, 0xc8, 0x5f, 0x1a, 0xb0, 0x3d, 0x77, 0xa0, 0x58, 0x9b,
+	0x1e, 0xd2, 0xf1, 0x84, 0xfa, 0xaa, 0x3f, 0x99, 0x38, 0x56, 0xa4, 0x76, 0x39, 0x33, 0xb7, 0xcb,
+	0xbb, 0x90, 0xb7, 0x1d, 0x87, 0x38, 0xfc, 0x3a, 0x69, 0x62, 0x21, 0x30, 0x8f, 0x31, 0x75, 0xdc,
+	0x4b, 0x97, 0x38, 0xf5, 0x1c, 0x37, 0x68, 0x19, 0xd5, 0xa1, 0xe0, 0x10, 0x8f, 0x44, 0xc4, 0xa9,
+	0xe7, 0xb9, 0x49, 0x89, 0xd6, 0xbf, 0xe5, 0x64, 0x5c, 0x5f, 0x5b, 0xb7, 0x18, 0x1c, 0x6c, 0x13,
+	0x87, 0x74, 0xe2, 0xea, 0x10, 0xa5, 0x94, 0x8c, 0x23, 0x97, 0x8a, 0xe3, 0xff, 0x90, 0x74, 0x55,
+	0x31, 0x05, 0x5d, 0x31, 0xd6, 0x6f, 0x0c, 0x71, 0x81, 0x10, 0x8b, 0xbd, 0xc5, 0x8c, 0x4d, 0x84,
+	0x97, 0xb9, 0x61, 0x78, 0xd9, 0x1b, 0x86, 0x97, 0x8b, 0xc3, 0xfb, 0x9d, 0x91, 0xba, 0x09, 0xbc,
+	0xa1, 0x51, 0xfe, 0xc5, 0xd0, 0xf7, 0x0d, 0x5d, 0xc8, 0xff, 0x9b, 0xfb, 0x46, 0x22, 0xfe, 0xec,
+	0x0d, 0xe3, 0xcf, 0xdd, 0x30, 0xfe, 0x7c, 0x1c, 0xff, 0x9f, 0x0d, 0xa8, 0x2d, 0x69, 0xd7, 0xac,
+	0xf6, 0x55, 0xbb, 0x56, 0xb5, 0xaf, 0x64, 0xb6, 0x44, 0xf6, 0x12, 0x19, 0x4e, 0xec, 0xa1, 0xba,
+	0x73, 0xc4, 0x8a, 0x1b, 0x34, 0xa2, 0xb8, 0xa9, 0xe5, 0x52, 0x4d, 0x6d, 0x17, 0xf2, 0x01, 0xb1,
+	0x9d, 0x19, 0x8f, 0xaf, 0x88, 0x85, 0xc0, 0x4e, 0xc9, 0x98, 0x84, 0xa1, 0x3d, 0x12, 0x45, 0x6e,
+	0x62, 0x25, 0x5a, 0x7f, 0xcd, 0x80, 0xa9, 0x6f, 0x07, 0x2c, 0x2a, 0x8f, 0x0e, 0x6d, 0x8f, 0x69,
+	0x54, 0xe2, 0xb5, 0x02, 0xdd, 0x07, 0x08, 0xc8, 0x98, 0x46, 0x84, 0x9b, 0x33, 0xdc, 0x9c, 0xd0,
+	0xb0, 0xa7, 0x4c, 0xa8, 0xd3, 0x63, 0xef, 0xc9, 0x22, 0x62, 0x25, 0xa2, 0x77, 0x61, 0x6b, 0x48,
+	0xfd, 0xc8, 0x76, 0x7d, 0x12, 0x70, 0xbb, 0x08, 0x3a, 0xad, 0x4c, 0xe7, 0x24, 0x3f, 0x9f, 0x93,
+	0x06, 0x14, 0xd9, 0xcd, 0x85, 0xbb, 0x8b, 0x45, 0x68, 0x19, 0x59, 0x50, 0x56, 0x99, 0x1d, 0xcc,
+	0x26, 0x44, 0x9e, 0xd0, 0x94, 0x2e, 0x89, 0xe1, 0x1c, 0xc5, 0x34, 0x86, 0xf3, 0xcc, 0xe5, 0xdd,
+	0xfc, 0xaa, 0xbc, 0x43, 0x32, 0xef, 0xd6, 0x7f, 0x0c, 0x40, 0x8b, 0x57, 0xa5, 0x64, 0x4a, 0x8c,
+	0x35, 0x29, 0xc9, 0xac, 0x4d, 0x49, 0x76, 0x4d, 0x99, 0xe4, 0x96, 0x86, 0x1b, 0x10, 0x3b, 0xa4,
+	0xbe, 0xcc, 0xa7, 0x94, 0x56, 0x17, 0x04, 0xfb, 0xd2, 0xe1, 0xd1, 0x51, 0x58, 0x2f, 0xf0, 0x6e,
+	0xca, 0xff, 0x33, 0x16, 0x7e, 0xc1, 0x10, 0xef, 0xd2, 0x26, 0x96, 0x92, 0xb5, 0x0f, 0x95, 0x41,
+	0xe0, 0x8e, 0x46, 0x24, 0x50, 0xdf, 0x49, 0xea, 0xec, 0x4d, 0xd2, 0xbe, 0xf0, 0x88, 0xc3, 0xd7,
+	0x5b, 0xc4, 0x4a, 0xb4, 0xbe, 0x09, 0x65, 0x7e, 0xe5, 0x50, 0xc8, 0xbd, 0xf9, 0x57, 0x26, 0x33,
+	0xf1, 0x46, 0x64, 0xfd, 0x10, 0x4a, 0xa7, 0xc4, 0x77, 0x5c, 0x7f, 0xc4, 0x46, 0xc9, 0xba, 0x1b,
+	0x8a, 0x9c, 0x0c, 0x99, 0x55, 0x93, 0x21, 0x9b, 0x9e, 0x50, 0x7f, 0x33, 0xa0, 0x22, 0xd9, 0xc5,
+	0xf8, 0x0c, 0x79, 0x34, 0xd3, 0x88, 0xf2, 0x08, 0x65, 0xe4, 0xb1, 0x82, 0x3f, 0x7e, 0x1a, 0x51,
+	0x16, 0x0a, 0xdf, 0xa6, 0x22, 0xd6, 0x32, 0x3b, 0x14, 0xec, 0xbf, 0xb8, 0x70, 0xf0, 0x2d, 0x2a,
+	0xe2, 0x84, 0x86, 0x85, 0x11, 0x10, 0x7e, 0xbb, 0x57, 0x03, 0x4a, 0x8a, 0xe8, 0x3d, 0xc8, 0xb1,
+	0x7b, 0x2c, 0x9f, 0x9f, 0xf1, 0x05, 0x3f, 0xb1, 0x6c, 0xcc, 0xed, 0xa2, 0x8d, 0x88, 0x8b, 0x3e,
+	0xdf, 0xac, 0x22, 0xd6, 0xb2, 0xf5, 0x33, 0x28, 0x76, 0xe9, 0x48, 0xbc, 0xd4, 0x3c, 0x05, 0x53,
+	0x7f, 0x51, 0xbb, 0xc9, 0xfb, 0xbf, 0x06, 0xb3, 0x4f, 0x65, 0x24, 0xf1, 0x86, 0xa2, 0x3e, 0x95,
+	0xc9, 0xf7, 0x4a, 0x92, 0xbe, 0x49, 0x66, 0x93, 0x37, 0xc9, 0xdf, 0x1b, 0x60, 0x76, 0xe9, 0xe8,
+	0x85, 0xeb, 0x45, 0x24, 0x60, 0x5b, 0xe1, 0x8e, 0xed, 0x11, 0x51, 0x1b, 0x2a, 0x25, 0x96, 0x23,
+	0x5d, 0xd6, 0xa1, 0xdc, 0xa6, 0x84, 0x86, 0xad, 0x30, 0x24, 0x1e, 0x19, 0x46, 0x54, 0xdd, 0x28,
+	0xb5, 0xcc, 0xf2, 0xe7, 0xfa, 0x43, 0x6f, 0xea, 0xa8, 0xa6, 0xa1, 0x44, 0x66, 0x21, 0x5f, 0x08,
+	0x8b, 0x28, 0x6e, 0x25, 0xb2, 0x58, 0x3d, 0x72, 0x4d, 0x3c, 0x59, 0xdb, 0x42, 0xd8, 0xff, 0x31,
+	0x94, 0x12, 0x23, 0x02, 0x21, 0xa8, 0x9c, 0xf5, 0x8e, 0x7b, 0x27, 0x9f, 0xf6, 0xce, 0xfb, 0x83,
+	0xc3, 0xc1, 0x59, 0xbf, 0xba, 0x81, 0xb6, 0xa1, 0xd4, 0x3b, 0x19, 0x30, 0x19, 0x0f, 0x3a, 0xed,
+	0xaa, 0xc1, 0x14, 0xaf, 0x7a, 0xe7, 0xa7, 0xf8, 0xe4, 0x25, 0xee, 0xf4, 0xfb, 0xd5, 0x0c, 0xda,
+	0x02, 0xb3, 0x7f, 0xd6, 0x6a, 0x75, 0x3a, 0xed, 0x4e, 0xbb, 0x9a, 0x45, 0x00, 0x9b, 0x2f, 0x0e,
+	0x5f, 0x75, 0x3b, 0xed, 0x6a, 0x6e, 0xff, 0xd7, 0x06, 0x98, 0x7a, 0x5e, 0xa0, 0x4d, 0xc8, 0x9c,
+	0x1c, 0x57, 0x37, 0xd0, 0x0e, 0x6c, 0xa9, 0xc7, 0x74, 0x30, 0x3e, 0xc1, 0x55, 0x03, 0x55, 0xa1,
+	0xfc, 0xfc, 0xec, 0x55, 0xb7, 0x7d, 0x2e, 0x5d, 0x33, 0xec, 0x31, 0x83, 0x4e, 0x7f, 0xa0, 0x14,
+	0x59, 0xe6, 0xd5, 0xee, 0x9c, 0x76, 0x4f, 0x3e, 0x57, 0xaa, 0x1c, 0xc3, 0xf4, 0x3f, 0xef, 0xb5,
+	0x94, 0x22, 0x8f, 0xde, 0x82, 0x9a, 0x08, 0xfc, 0xbc, 0x75, 0xd4, 0x69, 0x1d, 0x2b, 0xc3, 0x26,
+	0xaa, 0xc1, 0x76, 0xbb, 0xf3, 0x83, 0xf3, 0xee, 0xc9, 0xc9, 0xa9, 0x52, 0x16, 0x1e, 0xff, 0xb6,
+	0x00, 0xdb, 0x7d, 0xf9, 0xd5, 0xb6, 0x4f, 0x82, 0x6b, 0x77, 0xc8, 0x3e, 0xd2, 0x15, 0x5f, 0x12,
+	0xf9, 0xf1, 0xe3, 0xee, 0x42, 0xa9, 0x74, 0xd8, 0xd7, 0xd5, 0x46, 0xea, 0xbb, 0xa9, 0xb5, 0xf3,
+	0xe5, 0xdf, 0xff, 0xf5, 0x8b, 0x4c, 0x09, 0x99, 0x07, 0xd7, 0xef, 0x1f, 0xf0, 0x6f, 0xa8, 0xa8,
+	0x0d, 0x45, 0x5e, 0x28, 0x5d, 0x3a, 0x42, 0xdb, 0x12, 0xac, 0x6a, 0xb2, 0x31, 0xaf, 0xb0, 0x10,
+	0x27, 0x28, 0x23, 0x60, 0x04, 0xa2, 0x8d, 0x3c, 0x34, 0x1e, 0x19, 0xa8, 0x0b, 0x9b, 0x47, 0xb6,
+	0xef, 0x78, 0x04, 0xa5, 0xaa, 0xaf, 0xb1, 0x22, 0x2c, 0x6b, 0x8f, 0xf3, 0xdc, 0xb5, 0x76, 0x62,
+	0x9e, 0x83, 0xd7, 0x9c, 0xe0, 0x99, 0xb1, 0x8f, 0x3e, 0x81, 0xbc, 0x38, 0xb9, 0xab, 0x56, 0xb5,
+	0x8a, 0x76, 0x97, 0xd3, 0x56, 0x2c, 0xbe, 0x3e, 0x7e, 0x4c, 0x19, 0xdd, 0x19, 0x98, 0x87, 0xba,
+	0x19, 0xdc, 0x51, 0x57, 0x88, 0x54, 0xe7, 0x5b, 0xc9, 0x78, 0x8f, 0x33, 0xd6, 0x1a, 0x15, 0xcd,
+	0x78, 0xc0, 0xba, 0x03, 0xa3, 0xed, 0x43, 0xf1, 0x50, 0xb5, 0x91, 0x5b, 0xb2, 0xd6, 0x39, 0x2b,
+	0x6a, 0x6c, 0xf1, 0x7d, 0x98, 0xf9, 0x43, 0x4d, 0xfa, 0x29, 0xc0, 0x61, 0xdc, 0x7d, 0x6e, 0x49,
+	0xdb, 0xe0, 0xb4, 0xbb, 0x8d, 0x6d, 0x46, 0x2b, 0x5a, 0x8c, 0x26, 0xfe, 0x11, 0x54, 0x78, 0x02,
+	0xf4, 0x97, 0x2a, 0x54, 0x4b, 0x7e, 0xc9, 0x58, 0x47, 0x7d, 0x9f, 0x53, 0xd7, 0xad, 0x5a, 0x22,
+	0x0f, 0x8a, 0x89, 0xd1, 0x7f, 0x9f, 0x7d, 0x8a, 0x17, 0x8f, 0xbc, 0xf5, 0xae, 0xdd, 0xe1, 0xdc,
+	0xdb, 0x16, 0xc4, 0x61, 0x8b, 0x54, 0xec, 0xbc, 0x24, 0xd1, 0x5c, 0xa7, 0x5f, 0xc5, 0x7d, 0x27,
+	0xdd, 0x7f, 0x25, 0xdc, 0xaa, 0x71, 0xea, 0x2d, 0x54, 0x62, 0xd4, 0x43, 0xc9, 0x31, 0x80, 0x72,
+	0x9f, 0x44, 0x71, 0xdf, 0xab, 0xc6, 0x55, 0x2e, 0x34, 0xeb, 0x12, 0xfc, 0xcc, 0xd8, 0x17, 0x39,
+	0x66, 0xe3, 0xf5, 0xe0, 0x92, 0xfb, 0x5c, 0x6c, 0x72, 0xec, 0x93, 0xff, 0x0e, 0x00, 0x13, 0x56,
+	0xcf, 0xf8, 0x5d, 0x19, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -767,6 +2250,13 @@ type SkaffoldServiceClient interface {
 	EventLog(ctx context.Context, opts ...grpc.CallOption) (SkaffoldService_EventLogClient, error)
 	Handle(ctx context.Context, in *Event, opts ...grpc.CallOption) (*empty.Empty, error)
 	Build(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	AutoBuild(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	AutoSync(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	AutoDeploy(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	BuildArtifacts(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Redeploy(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetPendingChanges(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PendingChanges, error)
+	SetLogFilter(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type skaffoldServiceClient struct {
@@ -835,12 +2325,82 @@ func (c *skaffoldServiceClient) Build(ctx context.Context, in *empty.Empty, opts
 	return out, nil
 }
 
+func (c *skaffoldServiceClient) AutoBuild(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/AutoBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skaffoldServiceClient) AutoSync(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/AutoSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skaffoldServiceClient) AutoDeploy(ctx context.Context, in *TriggerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/AutoDeploy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skaffoldServiceClient) BuildArtifacts(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/BuildArtifacts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skaffoldServiceClient) Redeploy(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/Redeploy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skaffoldServiceClient) GetPendingChanges(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*PendingChanges, error) {
+	out := new(PendingChanges)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/GetPendingChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *skaffoldServiceClient) SetLogFilter(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.SkaffoldService/SetLogFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SkaffoldServiceServer is the server API for SkaffoldService service.
 type SkaffoldServiceServer interface {
 	GetState(context.Context, *empty.Empty) (*State, error)
 	EventLog(SkaffoldService_EventLogServer) error
 	Handle(context.Context, *Event) (*empty.Empty, error)
 	Build(context.Context, *empty.Empty) (*empty.Empty, error)
+	AutoBuild(context.Context, *TriggerRequest) (*empty.Empty, error)
+	AutoSync(context.Context, *TriggerRequest) (*empty.Empty, error)
+	AutoDeploy(context.Context, *TriggerRequest) (*empty.Empty, error)
+	BuildArtifacts(context.Context, *BuildRequest) (*empty.Empty, error)
+	Redeploy(context.Context, *empty.Empty) (*empty.Empty, error)
+	GetPendingChanges(context.Context, *empty.Empty) (*PendingChanges, error)
+	SetLogFilter(context.Context, *LogFilter) (*empty.Empty, error)
 }
 
 func RegisterSkaffoldServiceServer(s *grpc.Server, srv SkaffoldServiceServer) {
@@ -927,6 +2487,132 @@ func _SkaffoldService_Build_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SkaffoldService_AutoBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).AutoBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/AutoBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).AutoBuild(ctx, req.(*TriggerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkaffoldService_AutoSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).AutoSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/AutoSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).AutoSync(ctx, req.(*TriggerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkaffoldService_AutoDeploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).AutoDeploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/AutoDeploy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).AutoDeploy(ctx, req.(*TriggerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkaffoldService_BuildArtifacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).BuildArtifacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/BuildArtifacts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).BuildArtifacts(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkaffoldService_Redeploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).Redeploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/Redeploy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).Redeploy(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkaffoldService_GetPendingChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).GetPendingChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/GetPendingChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).GetPendingChanges(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SkaffoldService_SetLogFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SkaffoldServiceServer).SetLogFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SkaffoldService/SetLogFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SkaffoldServiceServer).SetLogFilter(ctx, req.(*LogFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SkaffoldService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.SkaffoldService",
 	HandlerType: (*SkaffoldServiceServer)(nil),
@@ -943,6 +2629,34 @@ var _SkaffoldService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Build",
 			Handler:    _SkaffoldService_Build_Handler,
 		},
+		{
+			MethodName: "AutoBuild",
+			Handler:    _SkaffoldService_AutoBuild_Handler,
+		},
+		{
+			MethodName: "AutoSync",
+			Handler:    _SkaffoldService_AutoSync_Handler,
+		},
+		{
+			MethodName: "AutoDeploy",
+			Handler:    _SkaffoldService_AutoDeploy_Handler,
+		},
+		{
+			MethodName: "BuildArtifacts",
+			Handler:    _SkaffoldService_BuildArtifacts_Handler,
+		},
+		{
+			MethodName: "Redeploy",
+			Handler:    _SkaffoldService_Redeploy_Handler,
+		},
+		{
+			MethodName: "GetPendingChanges",
+			Handler:    _SkaffoldService_GetPendingChanges_Handler,
+		},
+		{
+			MethodName: "SetLogFilter",
+			Handler:    _SkaffoldService_SetLogFilter_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -31,6 +31,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -48,6 +50,66 @@ type server struct {
 	trigger chan bool
 }
 
+// LogFilterer replaces the filter of the logs printed by Skaffold.
+type LogFilterer interface {
+	SetLogFilter(filter *proto.LogFilter) error
+}
+
+var (
+	logFiltererLock sync.Mutex
+	logFilterer     LogFilterer
+)
+
+// RegisterLogFilterer makes the filter of the logs configurable through the API.
+func RegisterLogFilterer(filterer LogFilterer) {
+	logFiltererLock.Lock()
+	logFilterer = filterer
+	logFiltererLock.Unlock()
+}
+
+func registeredLogFilterer() (LogFilterer, error) {
+	logFiltererLock.Lock()
+	defer logFiltererLock.Unlock()
+
+	if logFilterer == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no logs are printed")
+	}
+	return logFilterer, nil
+}
+
+// DevLoop is the dev loop, as controlled through the API.
+type DevLoop interface {
+	SetAutoBuild(enabled bool)
+	SetAutoSync(enabled bool)
+	SetAutoDeploy(enabled bool)
+	BuildArtifacts(imageNames []string) error
+	Redeploy()
+	PendingChanges() *proto.PendingChanges
+}
+
+var (
+	devLoopLock sync.Mutex
+	devLoop     DevLoop
+)
+
+// RegisterDevLoop makes a dev loop controllable through the API.
+// Registering nil tells that no dev loop is running.
+func RegisterDevLoop(loop DevLoop) {
+	devLoopLock.Lock()
+	devLoop = loop
+	devLoopLock.Unlock()
+}
+
+func registeredDevLoop() (DevLoop, error) {
+	devLoopLock.Lock()
+	defer devLoopLock.Unlock()
+
+	if devLoop == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no dev loop is running")
+	}
+	return devLoop, nil
+}
+
 func newGRPCServer(port int) (func() error, error) {
 	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", util.Loopback, port))
 	if err != nil {
@@ -109,7 +171,7 @@ func initialize(opts *config.SkaffoldOptions) (func() error, error) {
 	if originalRPCPort == -1 {
 		return func() error { return nil }, nil
 	}
-	rpcPort := util.GetAvailablePort(originalRPCPort, &sync.Map{})
+	rpcPort := util.GetAvailablePort(util.Loopback, originalRPCPort, &sync.Map{})
 	if rpcPort != originalRPCPort && originalRPCPort != constants.DefaultRPCPort {
 		logrus.Warnf("provided port %d already in use: using %d instead", originalRPCPort, rpcPort)
 	}
@@ -121,7 +183,7 @@ func initialize(opts *config.SkaffoldOptions) (func() error, error) {
 	m.Store(rpcPort, true)
 
 	originalHTTPPort := opts.RPCHTTPPort
-	httpPort := util.GetAvailablePort(originalHTTPPort, m)
+	httpPort := util.GetAvailablePort(util.Loopback, originalHTTPPort, m)
 	if httpPort != originalHTTPPort && originalHTTPPort != constants.DefaultRPCHTTPPort {
 		logrus.Warnf("provided port %d already in use: using %d instead", originalHTTPPort, httpPort)
 	}
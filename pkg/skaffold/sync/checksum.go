@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"os"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/sirupsen/logrus"
+)
+
+// LocalChecksums returns the sha256 checksums of local files.
+// Files that can't be read are left out, so that they are always copied.
+func LocalChecksums(files map[string][]string) map[string]string {
+	checksums := map[string]string{}
+
+	for src := range files {
+		f, err := os.Open(src)
+		if err != nil {
+			continue
+		}
+
+		checksum, err := util.SHA256(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+
+		checksums[src] = checksum
+	}
+
+	return checksums
+}
+
+// ChangedFiles returns the files that don't have the same content in a container,
+// with a function that executes a command in that container and returns its standard output.
+func ChangedFiles(files map[string][]string, checksums map[string]string, run func([]string) ([]byte, error)) map[string][]string {
+	var dsts []string
+	for src, srcDsts := range files {
+		if _, found := checksums[src]; found {
+			dsts = append(dsts, srcDsts...)
+		}
+	}
+	if len(dsts) == 0 {
+		return files
+	}
+
+	remote := remoteChecksums(dsts, run)
+
+	changed := map[string][]string{}
+	for src, srcDsts := range files {
+		checksum, found := checksums[src]
+
+		for _, dst := range srcDsts {
+			if !found || remote[dst] != checksum {
+				changed[src] = append(changed[src], dst)
+			}
+		}
+	}
+
+	return changed
+}
+
+// remoteChecksums returns the sha256 checksums of files in a container.
+// Missing files are left out, and so are all the files if `sha256sum`
+// is not available in the container.
+func remoteChecksums(dsts []string, run func([]string) ([]byte, error)) map[string]string {
+	// sha256sum fails when some of the files are missing. The others are still listed.
+	stdout, err := run(append([]string{"sha256sum", "--"}, dsts...))
+	if err != nil {
+		logrus.Debugln("Computing checksums:", err)
+	}
+
+	checksums := map[string]string{}
+	for _, line := range util.NonEmptyLines(stdout) {
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) == 2 {
+			checksums[fields[1]] = fields[0]
+		}
+	}
+
+	return checksums
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+// emptyChecksum is the sha256 checksum of an empty file.
+const emptyChecksum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestChangedFiles(t *testing.T) {
+	tests := []struct {
+		description string
+		output      string
+		err         error
+		expected    map[string][]string
+	}{
+		{
+			description: "all files up to date",
+			output:      fmt.Sprintf("%s  /app/index.html\n%s  /app/style.css\n", emptyChecksum, emptyChecksum),
+			expected:    map[string][]string{},
+		},
+		{
+			description: "changed file",
+			output:      fmt.Sprintf("%s  /app/index.html\n%s  /app/style.css\n", emptyChecksum, "0123"),
+			expected:    map[string][]string{"style.css": {"/app/style.css"}},
+		},
+		{
+			description: "missing file",
+			output:      fmt.Sprintf("%s  /app/index.html\n", emptyChecksum),
+			err:         errors.New("sha256sum: /app/style.css: No such file or directory"),
+			expected:    map[string][]string{"style.css": {"/app/style.css"}},
+		},
+		{
+			description: "sha256sum not available",
+			err:         errors.New("sha256sum: not found"),
+			expected:    map[string][]string{"index.html": {"/app/index.html"}, "style.css": {"/app/style.css"}},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.NewTempDir().
+				Touch("index.html", "style.css").
+				Chdir()
+			files := map[string][]string{"index.html": {"/app/index.html"}, "style.css": {"/app/style.css"}}
+
+			changed := ChangedFiles(files, LocalChecksums(files), func(command []string) ([]byte, error) {
+				t.CheckDeepEqual("sha256sum --", strings.Join(command[:2], " "))
+				return []byte(test.output), test.err
+			})
+
+			t.CheckDeepEqual(test.expected, changed)
+		})
+	}
+}
+
+func TestChangedFilesUnreadable(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.NewTempDir().Chdir()
+		files := map[string][]string{"missing.html": {"/app/missing.html"}}
+
+		changed := ChangedFiles(files, LocalChecksums(files), func([]string) ([]byte, error) {
+			t.Errorf("no checksum should be computed in the container")
+			return nil, nil
+		})
+
+		t.CheckDeepEqual(files, changed)
+	})
+}
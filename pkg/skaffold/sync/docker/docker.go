@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"io"
+	"os/exec"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Syncer syncs files to the containers run on the local Docker daemon.
+type Syncer struct {
+	localDocker docker.LocalDaemon
+	labels      map[string]string
+}
+
+// NewSyncer returns a Syncer for the containers that have all the given labels.
+func NewSyncer(localDocker docker.LocalDaemon, labels map[string]string) *Syncer {
+	return &Syncer{
+		localDocker: localDocker,
+		labels:      labels,
+	}
+}
+
+func (s *Syncer) Sync(ctx context.Context, out io.Writer, item *sync.Item) error {
+	containers, err := s.localDocker.Containers(ctx, s.labels, false)
+	if err != nil {
+		return errors.Wrap(err, "listing containers")
+	}
+
+	checksums := sync.LocalChecksums(item.Copy)
+
+	numSynced := 0
+	for _, c := range containers {
+		if c.Image != item.Image {
+			continue
+		}
+
+		if len(item.Copy) > 0 {
+			logrus.Infoln("Copying files:", item.Copy, "to", c.ID)
+
+			files := sync.ChangedFiles(item.Copy, checksums, func(command []string) ([]byte, error) {
+				return s.runOut(execCmd(ctx, c, command))
+			})
+			if len(files) == 0 {
+				logrus.Debugln("Files are up to date in", c.ID)
+			} else if err := s.run(copyFilesCmd(ctx, c, files)); err != nil {
+				return errors.Wrap(err, "copying files")
+			}
+		}
+
+		if len(item.Delete) > 0 {
+			logrus.Infoln("Deleting files:", item.Delete, "from", c.ID)
+
+			if err := s.run(deleteFilesCmd(ctx, c, item.Delete)); err != nil {
+				return errors.Wrap(err, "deleting files")
+			}
+		}
+
+		if len(item.After) > 0 {
+			logrus.Infoln("Running post-sync commands in", c.ID)
+
+			if err := sync.RunPostSyncCommands(out, docker.ContainerName(c), item.After, func(command []string) ([]byte, error) {
+				return s.runOut(execCmd(ctx, c, command))
+			}); err != nil {
+				return err
+			}
+		}
+
+		numSynced++
+	}
+
+	if numSynced == 0 {
+		return errors.New("didn't sync any files")
+	}
+
+	return nil
+}
+
+// run runs a `docker` command against the local daemon.
+func (s *Syncer) run(cmd *exec.Cmd) error {
+	_, err := s.runOut(cmd)
+	return err
+}
+
+// runOut runs a `docker` command against the local daemon and returns its output.
+func (s *Syncer) runOut(cmd *exec.Cmd) ([]byte, error) {
+	cmd.Env = append(util.OSEnviron(), s.localDocker.ExtraEnv()...)
+	return util.RunCmdOut(cmd)
+}
+
+func execCmd(ctx context.Context, c types.Container, command []string) *exec.Cmd {
+	args := append([]string{"exec", c.ID}, command...)
+	return exec.CommandContext(ctx, "docker", args...)
+}
+
+func deleteFilesCmd(ctx context.Context, c types.Container, files map[string][]string) *exec.Cmd {
+	args := []string{"exec", c.ID, "rm", "-rf", "--"}
+	for _, dsts := range files {
+		args = append(args, dsts...)
+	}
+	return exec.CommandContext(ctx, "docker", args...)
+}
+
+func copyFilesCmd(ctx context.Context, c types.Container, files map[string][]string) *exec.Cmd {
+	// Use "m" flag to touch the files as they are copied.
+	reader, writer := io.Pipe()
+	copy := exec.CommandContext(ctx, "docker", "exec", "-i", c.ID, "tar", "xmf", "-", "-C", "/", "--no-same-owner")
+	copy.Stdin = reader
+	go func() {
+		defer writer.Close()
+
+		if err := util.CreateMappedTar(writer, "/", files); err != nil {
+			logrus.Errorln("Error creating tar archive:", err)
+		}
+	}()
+	return copy
+}
@@ -29,20 +29,21 @@ import (
 )
 
 type Syncer struct {
-	namespaces []string
+	namespaces map[string][]string
 }
 
-func NewSyncer(namespaces []string) *Syncer {
+// NewSyncer returns a Syncer for the namespaces listed for each kubectl context.
+func NewSyncer(namespaces map[string][]string) *Syncer {
 	return &Syncer{
 		namespaces: namespaces,
 	}
 }
 
-func (k *Syncer) Sync(ctx context.Context, s *sync.Item) error {
+func (k *Syncer) Sync(ctx context.Context, out io.Writer, s *sync.Item) error {
 	if len(s.Copy) > 0 {
 		logrus.Infoln("Copying files:", s.Copy, "to", s.Image)
 
-		if err := sync.Perform(ctx, s.Image, s.Copy, copyFileFn, k.namespaces); err != nil {
+		if err := sync.Perform(ctx, s.Image, s.Copy, copyFileFn(sync.LocalChecksums(s.Copy)), k.namespaces); err != nil {
 			return errors.Wrap(err, "copying files")
 		}
 	}
@@ -55,13 +56,30 @@ func (k *Syncer) Sync(ctx context.Context, s *sync.Item) error {
 		}
 	}
 
+	if len(s.After) > 0 {
+		logrus.Infoln("Running post-sync commands in", s.Image)
+
+		return sync.ForEachContainer(s.Image, k.namespaces, func(kubeContext string, pod v1.Pod, container v1.Container) error {
+			return sync.RunPostSyncCommands(out, container.Name, s.After, func(command []string) ([]byte, error) {
+				return util.RunCmdOut(execCmd(ctx, kubeContext, pod, container, command))
+			})
+		})
+	}
+
 	return nil
 }
 
-func deleteFileFn(ctx context.Context, pod v1.Pod, container v1.Container, files map[string][]string) []*exec.Cmd {
+func execCmd(ctx context.Context, kubeContext string, pod v1.Pod, container v1.Container, command []string) *exec.Cmd {
+	args := append(contextArgs(kubeContext), "exec", pod.Name, "--namespace", pod.Namespace, "-c", container.Name, "--")
+	args = append(args, command...)
+	return exec.CommandContext(ctx, "kubectl", args...)
+}
+
+func deleteFileFn(ctx context.Context, kubeContext string, pod v1.Pod, container v1.Container, files map[string][]string) []*exec.Cmd {
 	// "kubectl" is below...
 	deleteCmd := []string{"exec", pod.Name, "--namespace", pod.Namespace, "-c", container.Name, "--", "rm", "-rf", "--"}
-	args := make([]string, 0, len(deleteCmd)+len(files))
+	args := make([]string, 0, len(deleteCmd)+len(files)+2)
+	args = append(args, contextArgs(kubeContext)...)
 	args = append(args, deleteCmd...)
 	for _, dsts := range files {
 		args = append(args, dsts...)
@@ -70,18 +88,38 @@ func deleteFileFn(ctx context.Context, pod v1.Pod, container v1.Container, files
 	return []*exec.Cmd{delete}
 }
 
-func copyFileFn(ctx context.Context, pod v1.Pod, container v1.Container, files map[string][]string) []*exec.Cmd {
-	// Use "m" flag to touch the files as they are copied.
-	reader, writer := io.Pipe()
-	copy := exec.CommandContext(ctx, "kubectl", "exec", pod.Name, "--namespace", pod.Namespace, "-c", container.Name, "-i",
-		"--", "tar", "xmf", "-", "-C", "/", "--no-same-owner")
-	copy.Stdin = reader
-	go func() {
-		defer writer.Close()
-
-		if err := util.CreateMappedTar(writer, "/", files); err != nil {
-			logrus.Errorln("Error creating tar archive:", err)
+// copyFileFn returns a function that copies the files that differ from the ones
+// in a container, given the checksums of the local files.
+func copyFileFn(checksums map[string]string) func(context.Context, string, v1.Pod, v1.Container, map[string][]string) []*exec.Cmd {
+	return func(ctx context.Context, kubeContext string, pod v1.Pod, container v1.Container, files map[string][]string) []*exec.Cmd {
+		files = sync.ChangedFiles(files, checksums, func(command []string) ([]byte, error) {
+			return util.RunCmdOut(execCmd(ctx, kubeContext, pod, container, command))
+		})
+		if len(files) == 0 {
+			logrus.Debugf("Files are up to date in %s/%s", pod.Namespace, pod.Name)
+			return nil
 		}
-	}()
-	return []*exec.Cmd{copy}
+
+		// Use "m" flag to touch the files as they are copied.
+		reader, writer := io.Pipe()
+		args := append(contextArgs(kubeContext), "exec", pod.Name, "--namespace", pod.Namespace, "-c", container.Name, "-i",
+			"--", "tar", "xmf", "-", "-C", "/", "--no-same-owner")
+		copy := exec.CommandContext(ctx, "kubectl", args...)
+		copy.Stdin = reader
+		go func() {
+			defer writer.Close()
+
+			if err := util.CreateMappedTar(writer, "/", files); err != nil {
+				logrus.Errorln("Error creating tar archive:", err)
+			}
+		}()
+		return []*exec.Cmd{copy}
+	}
+}
+
+func contextArgs(kubeContext string) []string {
+	if kubeContext == "" {
+		return nil
+	}
+	return []string{"--context", kubeContext}
 }
@@ -0,0 +1,331 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package native
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	gosync "sync"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// podCache is here for testing.
+var podCache = func(kubeContext string, namespaces []string, selector map[string]string) podLister {
+	return kubernetes.NewPodCache(kubeContext, namespaces, selector)
+}
+
+type podLister interface {
+	RunningPods() ([]v1.Pod, error)
+	Stop()
+}
+
+// Syncer copies files to the containers of the current run through the
+// Kubernetes API, without the kubectl binary.
+//
+// Only the files that differ from the ones in the containers are copied.
+// The Syncer also remembers what was synced for each image, to bring the containers
+// that restarted, and lost the synced files, back up to date.
+type Syncer struct {
+	pods map[string]podLister
+
+	// lock guards what was synced and the containers seen. It's not
+	// held while files are synced, since that can take a while.
+	lock       gosync.Mutex
+	synced     map[string]*syncedFiles
+	containers map[string]int32
+	watchOnce  gosync.Once
+}
+
+// target is a container to sync files to.
+type target struct {
+	kubeContext string
+	pod         v1.Pod
+	container   v1.Container
+}
+
+func (t target) String() string {
+	return fmt.Sprintf("%s/%s", t.pod.Namespace, t.pod.Name)
+}
+
+// key identifies a container across the kubectl contexts.
+func (t target) key() string {
+	return fmt.Sprintf("%s/%s/%s/%s", t.kubeContext, t.pod.Namespace, t.pod.Name, t.container.Name)
+}
+
+// restartCount returns how many times the container has restarted.
+func (t target) restartCount() int32 {
+	for _, status := range t.pod.Status.ContainerStatuses {
+		if status.Name == t.container.Name {
+			return status.RestartCount
+		}
+	}
+	return 0
+}
+
+// started tells if the container is running. Pods without a status for
+// the container are considered running.
+func (t target) started() bool {
+	for _, status := range t.pod.Status.ContainerStatuses {
+		if status.Name == t.container.Name {
+			return status.State.Running != nil
+		}
+	}
+	return true
+}
+
+// NewSyncer returns a Syncer for the pods matching a label selector,
+// in the namespaces listed for each kubectl context.
+func NewSyncer(selector map[string]string, namespaces map[string][]string) *Syncer {
+	pods := map[string]podLister{}
+	for kubeContext, contextNamespaces := range namespaces {
+		pods[kubeContext] = podCache(kubeContext, contextNamespaces, selector)
+	}
+
+	return &Syncer{
+		pods:       pods,
+		synced:     map[string]*syncedFiles{},
+		containers: map[string]int32{},
+	}
+}
+
+// Stop stops watching the pods.
+func (s *Syncer) Stop() {
+	for _, lister := range s.pods {
+		lister.Stop()
+	}
+}
+
+func (s *Syncer) Sync(ctx context.Context, out io.Writer, item *sync.Item) error {
+	s.watchOnce.Do(func() {
+		go s.watchRestarts(ctx, out)
+	})
+
+	targets, err := s.record(item)
+	if err != nil {
+		return err
+	}
+
+	return s.syncTargets(ctx, out, targets, item)
+}
+
+// record remembers the files of an item and returns the containers to sync them to.
+func (s *Syncer) record(item *sync.Item) ([]target, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	targets, err := s.targets(item.Image)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("didn't sync any files")
+	}
+
+	s.syncedFiles(item.Image).add(item)
+	for _, t := range targets {
+		s.containers[t.key()] = t.restartCount()
+	}
+
+	return targets, nil
+}
+
+// syncTargets copies and deletes the files of an item in the given containers,
+// then runs the post-sync commands.
+func (s *Syncer) syncTargets(ctx context.Context, out io.Writer, targets []target, item *sync.Item) error {
+	if len(item.Copy) > 0 {
+		logrus.Infoln("Copying files:", item.Copy, "to", item.Image)
+
+		checksums := sync.LocalChecksums(item.Copy)
+
+		if err := perform(ctx, targets, func(ctx context.Context, t target) error {
+			files := sync.ChangedFiles(item.Copy, checksums, func(command []string) ([]byte, error) {
+				return execInContainerStdout(ctx, t, command)
+			})
+			if len(files) == 0 {
+				logrus.Debugln("Files are up to date in", t)
+				return nil
+			}
+
+			archive, err := mappedTar(files)
+			if err != nil {
+				return errors.Wrap(err, "creating tar archive")
+			}
+
+			return execInContainer(ctx, t, []string{"tar", "xmf", "-", "-C", "/", "--no-same-owner"}, archive)
+		}); err != nil {
+			return errors.Wrap(err, "copying files")
+		}
+	}
+
+	if len(item.Delete) > 0 {
+		logrus.Infoln("Deleting files:", item.Delete, "from", item.Image)
+
+		command := []string{"rm", "-rf", "--"}
+		for _, dsts := range item.Delete {
+			command = append(command, dsts...)
+		}
+
+		if err := perform(ctx, targets, func(ctx context.Context, t target) error {
+			return execInContainer(ctx, t, command, nil)
+		}); err != nil {
+			return errors.Wrap(err, "deleting files")
+		}
+	}
+
+	if len(item.After) > 0 {
+		logrus.Infoln("Running post-sync commands in", item.Image)
+
+		// Run the commands one container at a time, to keep their output together.
+		for _, t := range targets {
+			if err := sync.RunPostSyncCommands(out, t.container.Name, item.After, func(command []string) ([]byte, error) {
+				return execInContainerOut(ctx, t, command)
+			}); err != nil {
+				return errors.Wrap(err, t.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// targets returns the containers running a given image.
+func (s *Syncer) targets(image string) ([]target, error) {
+	var targets []target
+
+	for kubeContext, lister := range s.pods {
+		pods, err := lister.RunningPods()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pod := range pods {
+			for _, container := range pod.Spec.Containers {
+				if container.Image == image {
+					targets = append(targets, target{
+						kubeContext: kubeContext,
+						pod:         pod,
+						container:   container,
+					})
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// perform runs a function on all the targets concurrently and reports which ones failed.
+func perform(ctx context.Context, targets []target, fn func(context.Context, target) error) error {
+	results := make(chan error, len(targets))
+	for _, t := range targets {
+		go func(t target) {
+			err := fn(ctx, t)
+			if err != nil {
+				logrus.Warnf("Failed to sync files to %s: %s", t, err)
+				err = errors.Wrap(err, t.String())
+			} else {
+				logrus.Infof("Synced files to %s", t)
+			}
+			results <- err
+		}(t)
+	}
+
+	var failures []string
+	for range targets {
+		if err := <-results; err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed on %d of %d pods: %s", len(failures), len(targets), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func execInContainer(ctx context.Context, t target, command []string, stdin []byte) error {
+	var stderr bytes.Buffer
+
+	opts := kubernetes.ExecOptions{
+		KubeContext: t.kubeContext,
+		Namespace:   t.pod.Namespace,
+		Pod:         t.pod.Name,
+		Container:   t.container.Name,
+		Command:     command,
+		Stderr:      &stderr,
+	}
+	if stdin != nil {
+		opts.Stdin = bytes.NewReader(stdin)
+	}
+
+	if err := kubernetes.Exec(ctx, opts); err != nil {
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			return errors.Wrap(err, output)
+		}
+		return err
+	}
+	return nil
+}
+
+// execInContainerStdout runs a command in a container and returns its standard output.
+func execInContainerStdout(ctx context.Context, t target, command []string) ([]byte, error) {
+	var stdout bytes.Buffer
+
+	err := kubernetes.Exec(ctx, kubernetes.ExecOptions{
+		KubeContext: t.kubeContext,
+		Namespace:   t.pod.Namespace,
+		Pod:         t.pod.Name,
+		Container:   t.container.Name,
+		Command:     command,
+		Stdout:      &stdout,
+	})
+	return stdout.Bytes(), err
+}
+
+// execInContainerOut runs a command in a container and returns its combined output.
+func execInContainerOut(ctx context.Context, t target, command []string) ([]byte, error) {
+	var output bytes.Buffer
+
+	err := kubernetes.Exec(ctx, kubernetes.ExecOptions{
+		KubeContext: t.kubeContext,
+		Namespace:   t.pod.Namespace,
+		Pod:         t.pod.Name,
+		Container:   t.container.Name,
+		Command:     command,
+		Stdout:      &output,
+		Stderr:      &output,
+	})
+	return output.Bytes(), err
+}
+
+// mappedTar creates a tar archive of the files.
+func mappedTar(files map[string][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := util.CreateMappedTar(&buf, "/", files); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
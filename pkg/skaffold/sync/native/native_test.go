@@ -0,0 +1,312 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package native
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// emptyChecksum is the sha256 checksum of an empty file.
+const emptyChecksum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+var managedBySkaffold = map[string]string{"app.kubernetes.io/managed-by": "skaffold-test"}
+
+type fakePods []v1.Pod
+
+func (f fakePods) RunningPods() ([]v1.Pod, error) {
+	return f, nil
+}
+
+func (f fakePods) Stop() {}
+
+func podRunning(name, image string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "container", Image: image}},
+		},
+	}
+}
+
+func TestNativeSync(t *testing.T) {
+	tests := []struct {
+		description    string
+		item           *sync.Item
+		checksums      map[string]string
+		failingPod     string
+		expected       []string
+		expectedOutput string
+		shouldErr      bool
+	}{
+		{
+			description: "copy to all matching pods",
+			item: &sync.Item{
+				Image: "web:123",
+				Copy:  map[string][]string{"index.html": {"/app/index.html"}},
+			},
+			expected: []string{
+				"kubecontext ns/web1 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web1 container: tar xmf - -C / --no-same-owner",
+				"kubecontext ns/web2 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web2 container: tar xmf - -C / --no-same-owner",
+			},
+		},
+		{
+			description: "skip up to date pods",
+			item: &sync.Item{
+				Image: "web:123",
+				Copy:  map[string][]string{"index.html": {"/app/index.html"}},
+			},
+			checksums: map[string]string{
+				"web1": emptyChecksum + "  /app/index.html\n",
+				"web2": "0123456789abcdef  /app/index.html\n",
+			},
+			expected: []string{
+				"kubecontext ns/web1 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web2 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web2 container: tar xmf - -C / --no-same-owner",
+			},
+		},
+		{
+			description: "delete",
+			item: &sync.Item{
+				Image:  "web:123",
+				Delete: map[string][]string{"index.html": {"/app/index.html"}},
+			},
+			expected: []string{
+				"kubecontext ns/web1 container: rm -rf -- /app/index.html",
+				"kubecontext ns/web2 container: rm -rf -- /app/index.html",
+			},
+		},
+		{
+			description: "post-sync commands",
+			item: &sync.Item{
+				Image: "web:123",
+				Copy:  map[string][]string{"index.html": {"/app/index.html"}},
+				After: []latest.PostSyncCommand{{Command: []string{"kill", "-HUP", "1"}}},
+			},
+			expected: []string{
+				"kubecontext ns/web1 container: kill -HUP 1",
+				"kubecontext ns/web1 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web1 container: tar xmf - -C / --no-same-owner",
+				"kubecontext ns/web2 container: kill -HUP 1",
+				"kubecontext ns/web2 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web2 container: tar xmf - -C / --no-same-owner",
+			},
+			expectedOutput: "[container] reloaded web1\n[container] reloaded web2\n",
+		},
+		{
+			description: "report failing pod",
+			item: &sync.Item{
+				Image: "web:123",
+				Copy:  map[string][]string{"index.html": {"/app/index.html"}},
+			},
+			failingPod: "web2",
+			expected: []string{
+				"kubecontext ns/web1 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web1 container: tar xmf - -C / --no-same-owner",
+				"kubecontext ns/web2 container: sha256sum -- /app/index.html",
+				"kubecontext ns/web2 container: tar xmf - -C / --no-same-owner",
+			},
+			shouldErr: true,
+		},
+		{
+			description: "no matching pod",
+			item: &sync.Item{
+				Image: "other:123",
+				Copy:  map[string][]string{"index.html": {"/app/index.html"}},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.NewTempDir().
+				Touch("index.html").
+				Chdir()
+			t.Override(&podCache, func(kubeContext string, namespaces []string, selector map[string]string) podLister {
+				t.CheckDeepEqual(managedBySkaffold, selector)
+				return fakePods{podRunning("web1", "web:123"), podRunning("web2", "web:123"), podRunning("app", "app:123")}
+			})
+
+			var lock gosync.Mutex
+			var executed []string
+			t.Override(&kubernetes.Exec, func(_ context.Context, opts kubernetes.ExecOptions) error {
+				if opts.Stdin != nil {
+					_, err := tar.NewReader(opts.Stdin).Next()
+					t.CheckNoError(err)
+				}
+
+				lock.Lock()
+				executed = append(executed, fmt.Sprintf("%s %s/%s %s: %s", opts.KubeContext, opts.Namespace, opts.Pod, opts.Container, strings.Join(opts.Command, " ")))
+				lock.Unlock()
+
+				switch {
+				case opts.Command[0] == "sha256sum":
+					fmt.Fprint(opts.Stdout, test.checksums[opts.Pod])
+				case opts.Command[0] == "kill":
+					fmt.Fprintf(opts.Stdout, "reloaded %s\n", opts.Pod)
+				case opts.Pod == test.failingPod:
+					fmt.Fprint(opts.Stderr, "tar: not found")
+					return errors.New("command terminated with non-zero exit code")
+				}
+				return nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			syncer := NewSyncer(managedBySkaffold, map[string][]string{"kubecontext": {"ns"}})
+			var out bytes.Buffer
+			err := syncer.Sync(ctx, &out, test.item)
+
+			sort.Strings(executed)
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expected, executed)
+			t.CheckDeepEqual(test.expectedOutput, out.String())
+			if test.failingPod != "" {
+				t.CheckErrorContains("ns/web2: tar: not found", err)
+			}
+		})
+	}
+}
+
+func TestResyncRestarted(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.NewTempDir().
+			Touch("index.html").
+			Chdir()
+		pods := &fakePods{podRestarted("web1", 0), podRestarted("web2", 0)}
+		t.Override(&podCache, func(string, []string, map[string]string) podLister {
+			return pods
+		})
+
+		var syncer *Syncer
+		var lock gosync.Mutex
+		var executed []string
+		t.Override(&kubernetes.Exec, func(_ context.Context, opts kubernetes.ExecOptions) error {
+			// The records of the syncer must not be locked while commands run.
+			unlocked := make(chan bool)
+			go func() {
+				syncer.lock.Lock()
+				syncer.lock.Unlock()
+				close(unlocked)
+			}()
+			select {
+			case <-unlocked:
+			case <-time.After(time.Second):
+				t.Errorf("syncer locked while running %s", opts.Command)
+			}
+
+			if opts.Command[0] != "sha256sum" {
+				lock.Lock()
+				executed = append(executed, fmt.Sprintf("%s: %s", opts.Pod, strings.Join(opts.Command, " ")))
+				lock.Unlock()
+			}
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		syncer = NewSyncer(managedBySkaffold, map[string][]string{"kubecontext": {"ns"}})
+		t.CheckNoError(syncer.Sync(ctx, ioutil.Discard, &sync.Item{
+			Image: "web:123",
+			Copy:  map[string][]string{"index.html": {"/app/index.html"}},
+		}))
+		t.CheckNoError(syncer.Sync(ctx, ioutil.Discard, &sync.Item{
+			Image:  "web:123",
+			Delete: map[string][]string{"old.html": {"/app/old.html"}},
+		}))
+
+		// web1 restarted, web2 was replaced by web3 and web4 is not running yet.
+		executed = nil
+		web4 := podRestarted("web4", 0)
+		web4.Status.ContainerStatuses[0].State = v1.ContainerState{Waiting: &v1.ContainerStateWaiting{}}
+		*pods = fakePods{podRestarted("web1", 1), podRestarted("web3", 0), web4}
+
+		var out bytes.Buffer
+		syncer.resyncRestarted(ctx, &out)
+
+		sort.Strings(executed)
+		t.CheckDeepEqual([]string{
+			"web1: rm -rf -- /app/old.html",
+			"web1: tar xmf - -C / --no-same-owner",
+			"web3: rm -rf -- /app/old.html",
+			"web3: tar xmf - -C / --no-same-owner",
+		}, executed)
+		t.CheckDeepEqual("Syncing 2 files to 2 restarted containers of web:123\n", out.String())
+
+		// Nothing changed since the last check.
+		executed = nil
+		syncer.resyncRestarted(ctx, &out)
+		t.CheckDeepEqual([]string(nil), executed)
+	})
+}
+
+func podRestarted(name string, restartCount int32) v1.Pod {
+	pod := podRunning(name, "web:123")
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{{
+		Name:         "container",
+		RestartCount: restartCount,
+		State:        v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+	}}
+	return pod
+}
+
+type stoppablePods struct {
+	fakePods
+	stopped bool
+}
+
+func (s *stoppablePods) Stop() { s.stopped = true }
+
+func TestStop(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		pods := map[string]*stoppablePods{}
+		t.Override(&podCache, func(kubeContext string, namespaces []string, selector map[string]string) podLister {
+			pods[kubeContext] = &stoppablePods{}
+			return pods[kubeContext]
+		})
+
+		syncer := NewSyncer(managedBySkaffold, map[string][]string{"cluster1": {"ns"}, "cluster2": {"ns"}})
+		syncer.Stop()
+
+		t.CheckDeepEqual(true, pods["cluster1"].stopped)
+		t.CheckDeepEqual(true, pods["cluster2"].stopped)
+	})
+}
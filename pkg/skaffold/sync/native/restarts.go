@@ -0,0 +1,155 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package native
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/sirupsen/logrus"
+)
+
+// restartPollInterval is the time between two checks for restarted containers.
+var restartPollInterval = 2 * time.Second
+
+// syncedFiles are the files synced to the containers of an image.
+type syncedFiles struct {
+	copied  map[string][]string
+	deleted map[string][]string
+	after   []latest.PostSyncCommand
+}
+
+func (s *Syncer) syncedFiles(image string) *syncedFiles {
+	files, found := s.synced[image]
+	if !found {
+		files = &syncedFiles{
+			copied:  map[string][]string{},
+			deleted: map[string][]string{},
+		}
+		s.synced[image] = files
+	}
+	return files
+}
+
+// add records the files of a sync. A file deleted after being copied, or the other
+// way around, is only recorded once.
+func (f *syncedFiles) add(item *sync.Item) {
+	for src, dsts := range item.Copy {
+		f.copied[src] = dsts
+		delete(f.deleted, src)
+	}
+	for src, dsts := range item.Delete {
+		f.deleted[src] = dsts
+		delete(f.copied, src)
+	}
+	f.after = item.After
+}
+
+// item returns an item that syncs all the recorded files. The item
+// doesn't share its files with the records, that later syncs update.
+func (f *syncedFiles) item(image string) *sync.Item {
+	item := &sync.Item{
+		Image:  image,
+		Copy:   map[string][]string{},
+		Delete: map[string][]string{},
+		After:  f.after,
+	}
+	for src, dsts := range f.copied {
+		item.Copy[src] = dsts
+	}
+	for src, dsts := range f.deleted {
+		item.Delete[src] = dsts
+	}
+	return item
+}
+
+// resync is a sync of the recorded files to restarted containers.
+type resync struct {
+	targets []target
+	item    *sync.Item
+}
+
+// watchRestarts periodically brings the restarted containers up to date,
+// until the context is cancelled.
+func (s *Syncer) watchRestarts(ctx context.Context, out io.Writer) {
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.resyncRestarted(ctx, out)
+		}
+	}
+}
+
+// resyncRestarted syncs the files recorded for each image to the containers that
+// were restarted or created since they were last seen.
+func (s *Syncer) resyncRestarted(ctx context.Context, out io.Writer) {
+	for _, r := range s.restarted() {
+		color.Default.Fprintf(out, "Syncing %d files to %d restarted containers of %s\n", len(r.item.Copy)+len(r.item.Delete), len(r.targets), r.item.Image)
+
+		if err := s.syncTargets(ctx, out, r.targets, r.item); err != nil {
+			logrus.Warnln("Unable to sync files to restarted containers:", err)
+		}
+	}
+}
+
+// restarted lists, for each image, the containers that were restarted or created
+// since they were last seen, and records them as seen so that they are not synced
+// again until the next restart.
+func (s *Syncer) restarted() []resync {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var resyncs []resync
+	for image, files := range s.synced {
+		if len(files.copied) == 0 && len(files.deleted) == 0 {
+			continue
+		}
+
+		targets, err := s.targets(image)
+		if err != nil {
+			logrus.Debugln("Listing pods:", err)
+			return resyncs
+		}
+
+		var restarted []target
+		for _, t := range targets {
+			if !t.started() {
+				continue
+			}
+
+			restartCount, seen := s.containers[t.key()]
+			if !seen || restartCount < t.restartCount() {
+				restarted = append(restarted, t)
+				s.containers[t.key()] = t.restartCount()
+			}
+		}
+		if len(restarted) > 0 {
+			resyncs = append(resyncs, resync{targets: restarted, item: files.item(image)})
+		}
+	}
+
+	return resyncs
+}
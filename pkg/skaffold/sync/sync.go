@@ -17,8 +17,11 @@ limitations under the License.
 package sync
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -26,6 +29,7 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/jib"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -43,20 +47,50 @@ var (
 )
 
 type Syncer interface {
-	Sync(context.Context, *Item) error
+	Sync(context.Context, io.Writer, *Item) error
 }
 
 type syncMap map[string][]string
 
+// SyncMapFunc returns the destinations in the container of the files of an artifact's workspace,
+// keyed by their paths relative to the workspace.
+type SyncMapFunc func(context.Context, *latest.Artifact) (map[string][]string, error)
+
 type Item struct {
 	Image  string
 	Copy   map[string][]string
 	Delete map[string][]string
+	After  []latest.PostSyncCommand
+}
+
+// PostSyncError is returned when a post-sync command failed.
+type PostSyncError struct {
+	Command []string
+	Output  string
+
+	// Rebuild tells that the artifact should be rebuilt.
+	Rebuild bool
 }
 
-func NewItem(a *latest.Artifact, e watch.Events, builds []build.Artifact, insecureRegistries map[string]bool) (*Item, error) {
+func (e PostSyncError) Error() string {
+	return fmt.Sprintf("post-sync command %v failed: %s", e.Command, e.Output)
+}
+
+func NewItem(ctx context.Context, a *latest.Artifact, e watch.Events, builds []build.Artifact, insecureRegistries map[string]bool, syncMapFn SyncMapFunc) (*Item, error) {
 	// If there are no changes, short circuit and don't sync anything
-	if !e.HasChanged() || a.Sync == nil || len(a.Sync.Manual) == 0 {
+	if !e.HasChanged() || a.Sync == nil {
+		return nil, nil
+	}
+
+	if len(a.Sync.Infer) > 0 && (a.JibMavenArtifact != nil || a.JibGradleArtifact != nil) {
+		return jibItem(ctx, a, e, builds)
+	}
+
+	if len(a.Sync.Infer) > 0 {
+		return inferredItem(ctx, a, e, builds, syncMapFn)
+	}
+
+	if len(a.Sync.Manual) == 0 {
 		return nil, nil
 	}
 
@@ -89,9 +123,132 @@ func NewItem(a *latest.Artifact, e watch.Events, builds []build.Artifact, insecu
 		Image:  tag,
 		Copy:   toCopy,
 		Delete: toDelete,
+		After:  a.Sync.After,
 	}, nil
 }
 
+// inferredItem syncs the changed files to the destinations of the `COPY` and `ADD`
+// instructions of the Dockerfile.
+func inferredItem(ctx context.Context, a *latest.Artifact, e watch.Events, builds []build.Artifact, syncMapFn SyncMapFunc) (*Item, error) {
+	// The destinations of deleted files can't be inferred from the workspace anymore.
+	if len(e.Deleted) > 0 {
+		logrus.Infof("Files were deleted from %s. Skipping inferred sync", a.Workspace)
+		return nil, nil
+	}
+
+	tag := latestTag(a.ImageName, builds)
+	if tag == "" {
+		return nil, fmt.Errorf("could not find latest tag for image %s in builds: %v", a.ImageName, builds)
+	}
+
+	destinations, err := syncMapFn(ctx, a)
+	if err != nil {
+		if _, isNotSupported := err.(build.ErrSyncMapNotSupported); isNotSupported {
+			logrus.Infof("Inferred sync is not supported for %s. Skipping sync", a.ImageName)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "inferring sync map for %s", a.ImageName)
+	}
+
+	toCopy, err := intersectInferred(a.Workspace, a.Sync.Infer, destinations, append(e.Added, e.Modified...))
+	if err != nil {
+		return nil, errors.Wrap(err, "intersecting inferred sync map and added, modified files")
+	}
+
+	// Something went wrong, don't sync, rebuild.
+	if toCopy == nil {
+		return nil, nil
+	}
+
+	return &Item{
+		Image:  tag,
+		Copy:   toCopy,
+		Delete: syncMap{},
+		After:  a.Sync.After,
+	}, nil
+}
+
+// jibItem syncs the changed files, or the classes and resources compiled from them,
+// to the destinations given by Jib.
+func jibItem(ctx context.Context, a *latest.Artifact, e watch.Events, builds []build.Artifact) (*Item, error) {
+	for _, f := range append(append(e.Added, e.Modified...), e.Deleted...) {
+		relPath, err := filepath.Rel(a.Workspace, f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "changed file %s can't be found relative to context %s", f, a.Workspace)
+		}
+
+		matches, err := matchesAny(a.Sync.Infer, relPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matches {
+			logrus.Infof("Changed file %s does not match any sync pattern. Skipping sync", relPath)
+			return nil, nil
+		}
+	}
+
+	tag := latestTag(a.ImageName, builds)
+	if tag == "" {
+		return nil, fmt.Errorf("could not find latest tag for image %s in builds: %v", a.ImageName, builds)
+	}
+
+	toCopy, toDelete, err := jib.GetSyncDiff(ctx, a.Workspace, a, e)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting jib sync diff for %s", a.ImageName)
+	}
+
+	// The dependencies changed, don't sync, rebuild.
+	if toCopy == nil || toDelete == nil {
+		return nil, nil
+	}
+
+	return &Item{
+		Image:  tag,
+		Copy:   toCopy,
+		Delete: toDelete,
+		After:  a.Sync.After,
+	}, nil
+}
+
+func intersectInferred(contextWd string, patterns []string, destinations map[string][]string, files []string) (syncMap, error) {
+	ret := make(syncMap)
+	for _, f := range files {
+		relPath, err := filepath.Rel(contextWd, f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "changed file %s can't be found relative to context %s", f, contextWd)
+		}
+
+		matches, err := matchesAny(patterns, relPath)
+		if err != nil {
+			return nil, err
+		}
+
+		dsts := destinations[relPath]
+		if !matches || len(dsts) == 0 {
+			logrus.Infof("Changed file %s can't be synced to an inferred destination. Skipping sync", relPath)
+			return nil, nil
+		}
+
+		ret[f] = dsts
+	}
+	return ret, nil
+}
+
+func matchesAny(patterns []string, relPath string) (bool, error) {
+	for _, pattern := range patterns {
+		matches, err := doublestar.PathMatch(filepath.FromSlash(pattern), relPath)
+		if err != nil {
+			return false, errors.Wrapf(err, "pattern error for %s", relPath)
+		}
+
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func latestTag(image string, builds []build.Artifact) string {
 	for _, build := range builds {
 		if build.ImageName == image {
@@ -149,42 +306,85 @@ func matchSyncRules(syncRules []*latest.SyncRule, relPath, containerWd string) (
 	return dsts, nil
 }
 
-func Perform(ctx context.Context, image string, files syncMap, cmdFn func(context.Context, v1.Pod, v1.Container, map[string][]string) []*exec.Cmd, namespaces []string) error {
+// Perform syncs files to the containers running a given image, in the namespaces
+// listed for each kubectl context.
+func Perform(ctx context.Context, image string, files syncMap, cmdFn func(context.Context, string, v1.Pod, v1.Container, map[string][]string) []*exec.Cmd, namespaces map[string][]string) error {
 	if len(files) == 0 {
 		return nil
 	}
 
-	client, err := kubernetes.Client()
-	if err != nil {
-		return errors.Wrap(err, "getting k8s client")
+	numSynced := 0
+	if err := ForEachContainer(image, namespaces, func(kubeContext string, p v1.Pod, c v1.Container) error {
+		cmds := cmdFn(ctx, kubeContext, p, c, files)
+		for _, cmd := range cmds {
+			if _, err := util.RunCmdOut(cmd); err != nil {
+				return err
+			}
+		}
+		numSynced++
+		return nil
+	}); err != nil {
+		return err
 	}
 
-	numSynced := 0
-	for _, ns := range namespaces {
-		pods, err := client.CoreV1().Pods(ns).List(meta_v1.ListOptions{})
+	if numSynced == 0 {
+		return errors.New("didn't sync any files")
+	}
+
+	return nil
+}
+
+// ForEachContainer calls a function for each container running a given image, in the namespaces
+// listed for each kubectl context.
+func ForEachContainer(image string, namespaces map[string][]string, fn func(string, v1.Pod, v1.Container) error) error {
+	for kubeContext, contextNamespaces := range namespaces {
+		client, err := kubernetes.Client(kubeContext)
 		if err != nil {
-			return errors.Wrap(err, "getting pods for namespace "+ns)
+			return errors.Wrap(err, "getting k8s client")
 		}
 
-		for _, p := range pods.Items {
-			for _, c := range p.Spec.Containers {
-				if c.Image != image {
-					continue
-				}
+		for _, ns := range contextNamespaces {
+			pods, err := client.CoreV1().Pods(ns).List(meta_v1.ListOptions{})
+			if err != nil {
+				return errors.Wrap(err, "getting pods for namespace "+ns)
+			}
+
+			for _, p := range pods.Items {
+				for _, c := range p.Spec.Containers {
+					if c.Image != image {
+						continue
+					}
 
-				cmds := cmdFn(ctx, p, c, files)
-				for _, cmd := range cmds {
-					if _, err := util.RunCmdOut(cmd); err != nil {
+					if err := fn(kubeContext, p, c); err != nil {
 						return err
 					}
-					numSynced++
 				}
 			}
 		}
 	}
 
-	if numSynced == 0 {
-		return errors.New("didn't sync any files")
+	return nil
+}
+
+// RunPostSyncCommands runs the post-sync commands of an item in a container, with a function that
+// executes a command in that container and returns its output. The output of the commands is
+// written to out, prefixed with the name of the container.
+func RunPostSyncCommands(out io.Writer, container string, commands []latest.PostSyncCommand, run func([]string) ([]byte, error)) error {
+	for _, command := range commands {
+		output, err := run(command.Command)
+
+		scanner := bufio.NewScanner(bytes.NewReader(output))
+		for scanner.Scan() {
+			fmt.Fprintf(out, "[%s] %s\n", container, scanner.Text())
+		}
+
+		if err != nil {
+			return PostSyncError{
+				Command: command.Command,
+				Output:  err.Error(),
+				Rebuild: command.RebuildOnFailure,
+			}
+		}
 	}
 
 	return nil
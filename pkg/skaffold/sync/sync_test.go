@@ -16,6 +16,7 @@ limitations under the License.
 package sync
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os/exec"
@@ -29,6 +30,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -390,7 +392,85 @@ func TestNewSyncItem(t *testing.T) {
 				return test.workingDir, nil
 			})
 
-			actual, err := NewItem(test.artifact, test.evt, test.builds, map[string]bool{})
+			actual, err := NewItem(context.Background(), test.artifact, test.evt, test.builds, map[string]bool{}, nil)
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, actual)
+		})
+	}
+}
+
+func TestNewSyncItemInferred(t *testing.T) {
+	var tests = []struct {
+		description string
+		evt         watch.Events
+		syncMapErr  error
+		expected    *Item
+		shouldErr   bool
+	}{
+		{
+			description: "sync copied file",
+			evt: watch.Events{
+				Modified: []string{filepath.Join("src", "index.js")},
+			},
+			expected: &Item{
+				Image: "test:123",
+				Copy: map[string][]string{
+					filepath.Join("src", "index.js"): {"/app/index.js"},
+				},
+				Delete: map[string][]string{},
+			},
+		},
+		{
+			description: "file not matching patterns",
+			evt: watch.Events{
+				Modified: []string{filepath.Join("src", "index.js"), "package.json"},
+			},
+		},
+		{
+			description: "file not copied by the Dockerfile",
+			evt: watch.Events{
+				Modified: []string{filepath.Join("test", "index.js")},
+			},
+		},
+		{
+			description: "deleted file",
+			evt: watch.Events{
+				Deleted: []string{filepath.Join("src", "index.js")},
+			},
+		},
+		{
+			description: "sync map not supported",
+			evt: watch.Events{
+				Modified: []string{filepath.Join("src", "index.js")},
+			},
+			syncMapErr: build.ErrSyncMapNotSupported{},
+		},
+		{
+			description: "sync map error",
+			evt: watch.Events{
+				Modified: []string{filepath.Join("src", "index.js")},
+			},
+			syncMapErr: errors.New("BUG"),
+			shouldErr:  true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			artifact := &latest.Artifact{
+				ImageName: "test",
+				Workspace: ".",
+				Sync: &latest.Sync{
+					Infer: []string{"**/*.js"},
+				},
+			}
+			syncMap := func(context.Context, *latest.Artifact) (map[string][]string, error) {
+				return map[string][]string{
+					filepath.Join("src", "index.js"): {"/app/index.js"},
+					"package.json":                   {"/app/package.json"},
+				}, test.syncMapErr
+			}
+
+			actual, err := NewItem(context.Background(), artifact, test.evt, []build.Artifact{{ImageName: "test", Tag: "test:123"}}, map[string]bool{}, syncMap)
 
 			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, actual)
 		})
@@ -479,7 +559,7 @@ func (t *TestCmdRecorder) RunCmdOut(cmd *exec.Cmd) ([]byte, error) {
 	return nil, t.RunCmd(cmd)
 }
 
-func fakeCmd(ctx context.Context, p v1.Pod, c v1.Container, files map[string][]string) []*exec.Cmd {
+func fakeCmd(ctx context.Context, _ string, p v1.Pod, c v1.Container, files map[string][]string) []*exec.Cmd {
 	cmds := make([]*exec.Cmd, len(files))
 	i := 0
 	for src, dsts := range files {
@@ -516,7 +596,7 @@ func TestPerform(t *testing.T) {
 		description string
 		image       string
 		files       syncMap
-		cmdFn       func(context.Context, v1.Pod, v1.Container, map[string][]string) []*exec.Cmd
+		cmdFn       func(context.Context, string, v1.Pod, v1.Container, map[string][]string) []*exec.Cmd
 		cmdErr      error
 		clientErr   error
 		expected    []string
@@ -545,6 +625,14 @@ func TestPerform(t *testing.T) {
 			clientErr:   fmt.Errorf(""),
 			shouldErr:   true,
 		},
+		{
+			description: "files up to date",
+			image:       "gcr.io/k8s-skaffold:123",
+			files:       syncMap{"test.go": {"/test.go"}},
+			cmdFn: func(context.Context, string, v1.Pod, v1.Container, map[string][]string) []*exec.Cmd {
+				return nil
+			},
+		},
 		{
 			description: "no copy",
 			image:       "gcr.io/different-pod:123",
@@ -558,13 +646,65 @@ func TestPerform(t *testing.T) {
 			cmdRecord := &TestCmdRecorder{err: test.cmdErr}
 
 			t.Override(&util.DefaultExecCommand, cmdRecord)
-			t.Override(&pkgkubernetes.Client, func() (kubernetes.Interface, error) {
+			t.Override(&pkgkubernetes.Client, func(string) (kubernetes.Interface, error) {
 				return fake.NewSimpleClientset(pod), test.clientErr
 			})
 
-			err := Perform(context.Background(), test.image, test.files, test.cmdFn, []string{""})
+			err := Perform(context.Background(), test.image, test.files, test.cmdFn, map[string][]string{"": {""}})
 
 			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, cmdRecord.cmds)
 		})
 	}
 }
+
+func TestRunPostSyncCommands(t *testing.T) {
+	var tests = []struct {
+		description    string
+		commands       []latest.PostSyncCommand
+		failing        string
+		expected       []string
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			description: "run all commands",
+			commands: []latest.PostSyncCommand{
+				{Command: []string{"touch", "/tmp/reload"}},
+				{Command: []string{"kill", "-HUP", "1"}},
+			},
+			expected:       []string{"touch /tmp/reload", "kill -HUP 1"},
+			expectedOutput: "[container] output of touch\n[container] output of kill\n",
+		},
+		{
+			description: "stop at first failure",
+			commands: []latest.PostSyncCommand{
+				{Command: []string{"compile"}, RebuildOnFailure: true},
+				{Command: []string{"kill", "-HUP", "1"}},
+			},
+			failing:        "compile",
+			expected:       []string{"compile"},
+			expectedOutput: "[container] output of compile\n",
+			expectedErr:    PostSyncError{Command: []string{"compile"}, Output: "exit status 1", Rebuild: true},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var out bytes.Buffer
+			var executed []string
+
+			err := RunPostSyncCommands(&out, "container", test.commands, func(command []string) ([]byte, error) {
+				executed = append(executed, strings.Join(command, " "))
+
+				output := []byte("output of " + command[0] + "\n")
+				if command[0] == test.failing {
+					return output, errors.New("exit status 1")
+				}
+				return output, nil
+			})
+
+			t.CheckDeepEqual(test.expectedErr, err)
+			t.CheckDeepEqual(test.expected, executed)
+			t.CheckDeepEqual(test.expectedOutput, out.String())
+		})
+	}
+}
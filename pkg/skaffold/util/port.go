@@ -17,8 +17,8 @@ limitations under the License.
 package util
 
 import (
-	"fmt"
 	"net"
+	"strconv"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -28,33 +28,33 @@ import (
 // unless we really want to expose something to the network.
 const Loopback = "127.0.0.1"
 
-// First, check if the provided port is available. If so, use it.
+// First, check if the provided port is available on the given address. If so, use it.
 // If not, check if any of the next 10 subsequent ports are available.
 // If not, check if any of ports 4503-4533 are available.
 // If not, return a random port, which hopefully won't collide with any future containers
 
 // See https://www.iana.org/assignments/service-names-port-numbers/service-names-port-numbers.txt,
-func GetAvailablePort(port int, forwardedPorts *sync.Map) int {
-	if getPortIfAvailable(port, forwardedPorts) {
+func GetAvailablePort(address string, port int, forwardedPorts *sync.Map) int {
+	if getPortIfAvailable(address, port, forwardedPorts) {
 		return port
 	}
 
 	// try the next 10 ports after the provided one
 	for i := 0; i < 10; i++ {
 		port++
-		if getPortIfAvailable(port, forwardedPorts) {
+		if getPortIfAvailable(address, port, forwardedPorts) {
 			logrus.Debugf("found open port: %d", port)
 			return port
 		}
 	}
 
 	for port = 4503; port <= 4533; port++ {
-		if getPortIfAvailable(port, forwardedPorts) {
+		if getPortIfAvailable(address, port, forwardedPorts) {
 			return port
 		}
 	}
 
-	l, err := net.Listen("tcp", fmt.Sprintf("%s:0", Loopback))
+	l, err := net.Listen("tcp", net.JoinHostPort(address, "0"))
 	if err != nil {
 		return -1
 	}
@@ -66,13 +66,13 @@ func GetAvailablePort(port int, forwardedPorts *sync.Map) int {
 	return p
 }
 
-func getPortIfAvailable(p int, forwardedPorts *sync.Map) bool {
+func getPortIfAvailable(address string, p int, forwardedPorts *sync.Map) bool {
 	alreadyUsed, loaded := forwardedPorts.LoadOrStore(p, true)
 	if loaded && alreadyUsed.(bool) {
 		return false
 	}
 
-	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", Loopback, p))
+	l, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(p)))
 	if err != nil {
 		return false
 	}
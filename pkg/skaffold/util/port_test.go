@@ -36,7 +36,7 @@ func TestGetAvailablePort(t *testing.T) {
 
 	for i := 0; i < N; i++ {
 		go func() {
-			port := GetAvailablePort(4503, &ports)
+			port := GetAvailablePort(Loopback, 4503, &ports)
 
 			l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", Loopback, port))
 			if err != nil {
@@ -55,3 +55,16 @@ func TestGetAvailablePort(t *testing.T) {
 		t.Fatalf("A port that was available couldn't be used %d times", errors)
 	}
 }
+
+func TestGetAvailablePortOnAddress(t *testing.T) {
+	l, err := net.Listen("tcp", fmt.Sprintf("%s:0", Loopback))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	taken := l.Addr().(*net.TCPAddr).Port
+
+	if port := GetAvailablePort(Loopback, taken, &sync.Map{}); port == taken {
+		t.Fatalf("port %d is already taken on %s", taken, Loopback)
+	}
+}
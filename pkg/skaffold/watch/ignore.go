@@ -0,0 +1,289 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/pkg/errors"
+)
+
+// IgnoreFile is the name of the file, at the root of a workspace, that lists
+// the paths whose changes are ignored. It uses the .gitignore syntax.
+const IgnoreFile = ".skaffoldignore"
+
+// Ignorer tells which changes to ignore, according to the .skaffoldignore
+// and .gitignore files at the root of the project, or to the .dockerignore
+// file at the root of an artifact's workspace.
+// A nil Ignorer doesn't ignore anything.
+type Ignorer struct {
+	root  string
+	rules []ignoreRule
+	// kept are the paths, relative to the root, that are never ignored.
+	kept map[string]bool
+}
+
+// ignoreRule is a pattern of an ignore file.
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	// anchored patterns are matched from the root, the others against any path component.
+	anchored bool
+	// dirOnly patterns only match directories.
+	dirOnly bool
+}
+
+// NewIgnorer reads the .gitignore and .skaffoldignore files at the root of the project.
+func NewIgnorer(root string) (*Ignorer, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting absolute path")
+	}
+
+	i := &Ignorer{root: absRoot}
+
+	for _, name := range []string{".gitignore", IgnoreFile} {
+		patterns, err := readLines(filepath.Join(absRoot, name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", name)
+		}
+
+		for _, pattern := range patterns {
+			i.rules = append(i.rules, gitignoreRule(pattern))
+		}
+	}
+
+	return i, nil
+}
+
+// NewDockerIgnorer reads the .dockerignore file at the root of an artifact's workspace.
+// As with docker, the Dockerfile and the .dockerignore file are never ignored.
+func NewDockerIgnorer(workspace, dockerfile string) (*Ignorer, error) {
+	absRoot, err := filepath.Abs(workspace)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting absolute path")
+	}
+
+	patterns, err := readDockerignore(filepath.Join(absRoot, ".dockerignore"))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading .dockerignore")
+	}
+
+	if filepath.IsAbs(dockerfile) {
+		if rel, err := filepath.Rel(absRoot, dockerfile); err == nil {
+			dockerfile = rel
+		}
+	}
+
+	i := &Ignorer{
+		root: absRoot,
+		kept: map[string]bool{
+			filepath.ToSlash(filepath.Clean(dockerfile)): true,
+			".dockerignore": true,
+		},
+	}
+	for _, pattern := range patterns {
+		i.rules = append(i.rules, dockerignoreRule(pattern))
+	}
+
+	return i, nil
+}
+
+// Ignored tells if changes to a path, absolute or relative to the root of the workspace,
+// are ignored. Paths outside of the workspace are never ignored.
+func (i *Ignorer) Ignored(path string) bool {
+	return i.ignored(path, false)
+}
+
+// ignoredDir tells if changes to a directory, and to everything it holds, are ignored.
+func (i *Ignorer) ignoredDir(dir string) bool {
+	return i.ignored(dir, true)
+}
+
+func (i *Ignorer) ignored(path string, isDir bool) bool {
+	if i == nil || len(i.rules) == 0 {
+		return false
+	}
+
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(i.root, path)
+		if err != nil {
+			return false
+		}
+		path = rel
+	}
+
+	path = filepath.ToSlash(filepath.Clean(path))
+	if path == "." || strings.HasPrefix(path, "../") || i.kept[path] {
+		return false
+	}
+
+	// As with git, the last matching pattern wins.
+	ignored := false
+	components := strings.Split(path, "/")
+	for _, rule := range i.rules {
+		if rule.matches(components, isDir) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// Filter returns the paths whose changes are not ignored.
+func (i *Ignorer) Filter(paths []string) []string {
+	if i == nil || len(i.rules) == 0 {
+		return paths
+	}
+
+	var filtered []string
+	for _, path := range paths {
+		if !i.Ignored(path) {
+			filtered = append(filtered, path)
+		}
+	}
+	return filtered
+}
+
+// Filtered wraps a list of dependencies to leave out the ignored files.
+func (i *Ignorer) Filtered(deps func() ([]string, error)) func() ([]string, error) {
+	return func() ([]string, error) {
+		paths, err := deps()
+		if err != nil {
+			return nil, err
+		}
+		return i.Filter(paths), nil
+	}
+}
+
+func gitignoreRule(pattern string) ignoreRule {
+	var rule ignoreRule
+
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	// A pattern with a slash, other than a trailing one, is relative to the root.
+	if strings.Contains(pattern, "/") {
+		rule.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+
+	rule.segments = strings.Split(pattern, "/")
+	return rule
+}
+
+func dockerignoreRule(pattern string) ignoreRule {
+	var rule ignoreRule
+
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+
+	// .dockerignore patterns are always relative to the root.
+	rule.anchored = true
+	rule.segments = strings.Split(pattern, "/")
+	return rule
+}
+
+// matches tells if a pattern matches a path, or one of its parent directories.
+func (r ignoreRule) matches(components []string, isDir bool) bool {
+	if !r.anchored {
+		for i, component := range components {
+			if r.dirOnly && !isDir && i == len(components)-1 {
+				break
+			}
+			if matched, _ := filepath.Match(r.segments[0], component); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	return matchSegments(r.segments, components, r.dirOnly && !isDir)
+}
+
+// matchSegments tells if the segments of a pattern match the first components of a path.
+// `**` matches any number of components.
+func matchSegments(segments, components []string, dirOnly bool) bool {
+	if len(segments) == 0 {
+		// The pattern matches a parent directory.
+		return !dirOnly || len(components) > 0
+	}
+	if segments[0] == "**" {
+		for skip := 0; skip <= len(components); skip++ {
+			if matchSegments(segments[1:], components[skip:], dirOnly) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(components) == 0 {
+		return false
+	}
+
+	if matched, _ := filepath.Match(segments[0], components[0]); !matched {
+		return false
+	}
+	return matchSegments(segments[1:], components[1:], dirOnly)
+}
+
+// readLines reads the patterns of a .gitignore style file.
+// A missing file has no patterns.
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+func readDockerignore(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return dockerignore.ReadAll(f)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		description string
+		files       map[string]string
+		path        string
+		expected    bool
+	}{
+		{
+			description: "no ignore file",
+			path:        "node_modules/lib/index.js",
+		},
+		{
+			description: "directory at any depth",
+			files:       map[string]string{".gitignore": "node_modules/"},
+			path:        "app/node_modules/lib/index.js",
+			expected:    true,
+		},
+		{
+			description: "directory pattern doesn't match a file",
+			files:       map[string]string{".gitignore": "build/"},
+			path:        "src/build",
+		},
+		{
+			description: "file pattern at any depth",
+			files:       map[string]string{".gitignore": "# swap files\n*.swp"},
+			path:        "src/.main.go.swp",
+			expected:    true,
+		},
+		{
+			description: "anchored pattern",
+			files:       map[string]string{".gitignore": "/target"},
+			path:        "app/target/classes/Main.class",
+		},
+		{
+			description: "anchored pattern at the root",
+			files:       map[string]string{".gitignore": "/target"},
+			path:        "target/classes/Main.class",
+			expected:    true,
+		},
+		{
+			description: "double star",
+			files:       map[string]string{".skaffoldignore": "src/**/*.tmp"},
+			path:        "src/main/java/Main.tmp",
+			expected:    true,
+		},
+		{
+			description: "negated by a later pattern",
+			files:       map[string]string{".gitignore": "*.log", ".skaffoldignore": "!important.log"},
+			path:        "logs/important.log",
+		},
+		{
+			description: "dockerignore is not used for the project",
+			files:       map[string]string{".dockerignore": "*\n!src"},
+			path:        "k8s/deploy.yaml",
+		},
+		{
+			description: "absolute path",
+			files:       map[string]string{".gitignore": "*.swp"},
+			path:        "ABSOLUTE/file.swp",
+			expected:    true,
+		},
+		{
+			description: "outside of the workspace",
+			files:       map[string]string{".gitignore": "*.swp"},
+			path:        "../file.swp",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				WriteFiles(test.files)
+
+			path := test.path
+			if strings.HasPrefix(path, "ABSOLUTE/") {
+				path = tmpDir.Path(strings.TrimPrefix(path, "ABSOLUTE/"))
+			}
+
+			ignorer, err := NewIgnorer(tmpDir.Root())
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expected, ignorer.Ignored(path))
+		})
+	}
+}
+
+func TestDockerIgnored(t *testing.T) {
+	tests := []struct {
+		description  string
+		dockerignore string
+		dockerfile   string
+		path         string
+		expected     bool
+	}{
+		{
+			description: "no dockerignore",
+			path:        "tmp/file",
+		},
+		{
+			description:  "ignored",
+			dockerignore: "*.md\ntmp",
+			path:         "tmp/file",
+			expected:     true,
+		},
+		{
+			description:  "relative to the root",
+			dockerignore: "*.md",
+			path:         "docs/README.md",
+		},
+		{
+			description:  "whitelist",
+			dockerignore: "*\n!src",
+			path:         "test/main_test.go",
+			expected:     true,
+		},
+		{
+			description:  "whitelisted",
+			dockerignore: "*\n!src",
+			path:         "src/main.go",
+		},
+		{
+			description:  "Dockerfile is never ignored",
+			dockerignore: "*\n!src",
+			path:         "Dockerfile",
+		},
+		{
+			description:  "custom Dockerfile is never ignored",
+			dockerignore: "*\n!src",
+			dockerfile:   "build/Dockerfile.dev",
+			path:         "ABSOLUTE/build/Dockerfile.dev",
+		},
+		{
+			description:  "dockerignore is never ignored",
+			dockerignore: "*\n!src",
+			path:         ".dockerignore",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir()
+			if test.dockerignore != "" {
+				tmpDir.Write(".dockerignore", test.dockerignore)
+			}
+
+			path := test.path
+			if strings.HasPrefix(path, "ABSOLUTE/") {
+				path = tmpDir.Path(strings.TrimPrefix(path, "ABSOLUTE/"))
+			}
+			dockerfile := test.dockerfile
+			if dockerfile == "" {
+				dockerfile = "Dockerfile"
+			}
+
+			ignorer, err := NewDockerIgnorer(tmpDir.Root(), dockerfile)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expected, ignorer.Ignored(path))
+		})
+	}
+}
+
+func TestIgnoredDir(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write(".gitignore", "node_modules/\n/build/")
+
+		ignorer, err := NewIgnorer(tmpDir.Root())
+		t.CheckNoError(err)
+
+		t.CheckDeepEqual(true, ignorer.ignoredDir("node_modules"))
+		t.CheckDeepEqual(true, ignorer.ignoredDir("app/node_modules"))
+		t.CheckDeepEqual(true, ignorer.ignoredDir("build"))
+		t.CheckDeepEqual(false, ignorer.Ignored("build"))
+		t.CheckDeepEqual(false, ignorer.ignoredDir("src"))
+	})
+}
+
+func TestFilter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write(".skaffoldignore", "*.swp\nnode_modules")
+
+		ignorer, err := NewIgnorer(tmpDir.Root())
+		t.CheckNoError(err)
+
+		filtered := ignorer.Filter([]string{"index.js", ".index.js.swp", "node_modules/lib.js", "src/app.js"})
+		t.CheckDeepEqual([]string{"index.js", "src/app.js"}, filtered)
+
+		var nilIgnorer *Ignorer
+		t.CheckDeepEqual([]string{"index.js"}, nilIgnorer.Filter([]string{"index.js"}))
+	})
+}
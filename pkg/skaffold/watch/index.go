@@ -0,0 +1,214 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// buildDefinitions are the names of the files that define how an artifact is built,
+// or deployed. A change to one of them can change the list of dependencies.
+var buildDefinitions = []string{
+	"Dockerfile", "Dockerfile.*", "*.Dockerfile", "*.dockerfile", ".dockerignore",
+	"pom.xml", "build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts",
+	"BUILD", "BUILD.bazel", "WORKSPACE", "*.bzl",
+	"kustomization.yaml", "kustomization.yml", "Kustomization",
+}
+
+// isBuildDefinition tells if a file defines how an artifact is built.
+func isBuildDefinition(path string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range buildDefinitions {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh returns the new state of a component's dependencies.
+// Listing the dependencies can be expensive, so it's only done when one of the
+// directories that contain them, or one of their parents, has new or removed entries,
+// or when a build definition changed. Otherwise, only the known dependencies are checked.
+func (w *watchList) refresh(c *component) (FileMap, error) {
+	state, err := Stat(listOf(c.state))
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := Stat(listOf(c.dirs))
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameFileMap(c.dirs, dirs) || buildDefinitionChanged(c.state, state) {
+		state, err = Stat(w.ignorer.Filtered(c.deps))
+		if err != nil {
+			return nil, err
+		}
+
+		if c.dirs, err = statDirs(state, w.ignorer); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// concerns tells if any of the changed paths is a dependency of the component,
+// or is in a directory that contains one of them.
+func (c *component) concerns(changed []string) bool {
+	known := map[string]bool{}
+	for path := range c.state {
+		known[absPath(path)] = true
+	}
+	for dir := range c.dirs {
+		known[absPath(dir)] = true
+	}
+
+	for _, path := range changed {
+		if known[path] || known[filepath.Dir(path)] {
+			return true
+		}
+	}
+	return false
+}
+
+// statDirs returns the modification times of the directories that contain the files,
+// and of their parent directories up to the directory the files have in common.
+// A new package, like `src/b/` next to `src/a/`, changes one of these directories
+// even when no file sits directly in `src/`.
+// The subdirectories that hold no dependency yet are also returned, so that a package
+// created empty and filled later is noticed too.
+func statDirs(files FileMap, ignorer *Ignorer) (FileMap, error) {
+	var root string
+	for path := range files {
+		dir := filepath.Dir(path)
+		if root == "" {
+			root = dir
+		} else {
+			root = commonDir(root, dir)
+		}
+	}
+
+	set := map[string]bool{}
+	for path := range files {
+		for dir := filepath.Dir(path); !set[dir]; dir = filepath.Dir(dir) {
+			set[dir] = true
+			if dir == root || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+
+	var dirs []string
+	for dir := range set {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range dirs {
+		addSubdirs(dir, set, ignorer)
+	}
+
+	dirs = nil
+	for dir := range set {
+		dirs = append(dirs, dir)
+	}
+
+	return Stat(func() ([]string, error) { return dirs, nil })
+}
+
+// addSubdirs adds the subdirectories of a directory that are not yet known, and not ignored.
+func addSubdirs(dir string, set map[string]bool, ignorer *Ignorer) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, info := range infos {
+		path := filepath.Join(dir, info.Name())
+		if !info.IsDir() || set[path] || ignorer.ignoredDir(path) {
+			continue
+		}
+
+		set[path] = true
+		addSubdirs(path, set, ignorer)
+	}
+}
+
+// commonDir returns the deepest directory that contains both directories.
+func commonDir(a, b string) string {
+	for !isParentOrSame(a, b) {
+		parent := filepath.Dir(a)
+		if parent == a {
+			return a
+		}
+		a = parent
+	}
+	return a
+}
+
+// isParentOrSame tells if a directory is the same as, or a parent of, another one.
+func isParentOrSame(parent, dir string) bool {
+	if parent == dir || parent == "." && !filepath.IsAbs(dir) && !strings.HasPrefix(dir, "..") {
+		return true
+	}
+	return strings.HasPrefix(dir, strings.TrimSuffix(parent, string(filepath.Separator))+string(filepath.Separator))
+}
+
+func buildDefinitionChanged(prev, curr FileMap) bool {
+	for path, modTime := range prev {
+		if !isBuildDefinition(path) {
+			continue
+		}
+		if currModTime, found := curr[path]; !found || !currModTime.Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameFileMap(prev, curr FileMap) bool {
+	if len(prev) != len(curr) {
+		return false
+	}
+	for path, modTime := range prev {
+		if currModTime, found := curr[path]; !found || !currModTime.Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// listOf returns a function that lists the files of a FileMap.
+func listOf(files FileMap) func() ([]string, error) {
+	return func() ([]string, error) {
+		var paths []string
+		for path := range files {
+			paths = append(paths, path)
+		}
+		return paths, nil
+	}
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
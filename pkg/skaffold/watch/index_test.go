@@ -0,0 +1,213 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRefresh(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		description  string
+		update       func(*testutil.TempDir)
+		expectedDeps []string
+		expectedList bool
+	}{
+		{
+			description:  "no change",
+			update:       func(*testutil.TempDir) {},
+			expectedDeps: []string{"Dockerfile", "src/app.js"},
+		},
+		{
+			description:  "modified dependency",
+			update:       func(tmpDir *testutil.TempDir) { tmpDir.Chtimes("src/app.js", future) },
+			expectedDeps: []string{"Dockerfile", "src/app.js"},
+		},
+		{
+			description:  "new file",
+			update:       func(tmpDir *testutil.TempDir) { tmpDir.Touch("src/lib.js") },
+			expectedDeps: []string{"Dockerfile", "src/app.js", "src/lib.js"},
+			expectedList: true,
+		},
+		{
+			description:  "deleted dependency",
+			update:       func(tmpDir *testutil.TempDir) { tmpDir.Remove("src/app.js") },
+			expectedDeps: []string{"Dockerfile"},
+			expectedList: true,
+		},
+		{
+			description:  "modified build definition",
+			update:       func(tmpDir *testutil.TempDir) { tmpDir.Chtimes("Dockerfile", future) },
+			expectedDeps: []string{"Dockerfile", "src/app.js"},
+			expectedList: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				Touch("Dockerfile", "src/app.js").
+				Chtimes("src", past).
+				Chtimes(".", past).
+				Chdir()
+
+			listed := 0
+			deps := func() ([]string, error) {
+				listed++
+				return walk(".")
+			}
+
+			watcher := NewWatcher(&pollTrigger{}, nil).(*watchList)
+			err := watcher.Register(deps, func(Events) {})
+			t.CheckNoError(err)
+
+			test.update(tmpDir)
+			component := watcher.components[0]
+			state, err := watcher.refresh(component)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedDeps, sortedPaths(state))
+			t.CheckDeepEqual(test.expectedList, listed > 1)
+		})
+	}
+}
+
+func TestRefreshNewPackage(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		past := time.Now().Add(-time.Hour)
+		tmpDir := t.NewTempDir().
+			Touch("Dockerfile", "src/a/x.go").
+			Chtimes("src/a", past).
+			Chtimes("src", past).
+			Chtimes(".", past).
+			Chdir()
+
+		watcher := NewWatcher(&pollTrigger{}, nil).(*watchList)
+		err := watcher.Register(func() ([]string, error) { return walk(".") }, func(Events) {})
+		t.CheckNoError(err)
+
+		// src/ holds no file directly.
+		tmpDir.Touch("src/b/y.go")
+		state, err := watcher.refresh(watcher.components[0])
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"Dockerfile", "src/a/x.go", "src/b/y.go"}, sortedPaths(state))
+	})
+}
+
+func TestRefreshEmptyPackageFilledLater(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		past := time.Now().Add(-time.Hour)
+		tmpDir := t.NewTempDir().
+			Touch("Dockerfile", "src/a/x.go").
+			Chtimes("src/a", past).
+			Chtimes("src", past).
+			Chtimes(".", past).
+			Chdir()
+
+		watcher := NewWatcher(&pollTrigger{}, nil).(*watchList)
+		err := watcher.Register(func() ([]string, error) { return walk(".") }, func(Events) {})
+		t.CheckNoError(err)
+
+		tmpDir.Mkdir("src/b").Chtimes("src/b", past)
+		component := watcher.components[0]
+		component.state, err = watcher.refresh(component)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"Dockerfile", "src/a/x.go"}, sortedPaths(component.state))
+
+		tmpDir.Touch("src/b/y.go")
+		state, err := watcher.refresh(component)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"Dockerfile", "src/a/x.go", "src/b/y.go"}, sortedPaths(state))
+	})
+}
+
+func TestConcerns(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Touch("app/Dockerfile", "app/src/app.js", "other/file").
+			Chdir()
+
+		watcher := NewWatcher(&pollTrigger{}, nil).(*watchList)
+		err := watcher.Register(func() ([]string, error) {
+			return []string{"app/Dockerfile", "app/src/app.js"}, nil
+		}, func(Events) {})
+		t.CheckNoError(err)
+
+		component := watcher.components[0]
+		t.CheckDeepEqual(true, component.concerns([]string{tmpDir.Path("app/src/app.js")}))
+		t.CheckDeepEqual(true, component.concerns([]string{tmpDir.Path("app/src/new.js")}))
+		t.CheckDeepEqual(false, component.concerns([]string{tmpDir.Path("other/file")}))
+		t.CheckDeepEqual(false, component.concerns(nil))
+	})
+}
+
+func TestStatDirs(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.NewTempDir().
+			Touch("app/Dockerfile", "app/src/main/java/Main.java", "app/src/test/java/MainTest.java").
+			Mkdir("app/src/main/resources/static").
+			Chdir()
+
+		dirs, err := statDirs(FileMap{
+			"app/Dockerfile":                  time.Time{},
+			"app/src/main/java/Main.java":     time.Time{},
+			"app/src/test/java/MainTest.java": time.Time{},
+		}, nil)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"app", "app/src", "app/src/main", "app/src/main/java", "app/src/main/resources", "app/src/main/resources/static", "app/src/test", "app/src/test/java"}, sortedPaths(dirs))
+	})
+}
+
+func TestIsBuildDefinition(t *testing.T) {
+	testutil.CheckDeepEqual(t, true, isBuildDefinition("app/Dockerfile"))
+	testutil.CheckDeepEqual(t, true, isBuildDefinition("app/Dockerfile.dev"))
+	testutil.CheckDeepEqual(t, true, isBuildDefinition("pom.xml"))
+	testutil.CheckDeepEqual(t, true, isBuildDefinition("src/BUILD.bazel"))
+	testutil.CheckDeepEqual(t, false, isBuildDefinition("src/main.go"))
+}
+
+func walk(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, filepath.ToSlash(path))
+		}
+		return err
+	})
+	return files, err
+}
+
+func sortedPaths(files FileMap) []string {
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -40,6 +42,13 @@ type Trigger interface {
 	Debounce() bool
 }
 
+// pathTracker is implemented by the triggers that know which paths changed.
+type pathTracker interface {
+	// ChangedPaths returns the absolute paths that changed since the last call.
+	// It returns false if the changes are not known.
+	ChangedPaths() ([]string, bool)
+}
+
 // NewTrigger creates a new trigger.
 func NewTrigger(runctx *runcontext.RunContext) (Trigger, error) {
 	switch strings.ToLower(runctx.Opts.Trigger) {
@@ -140,6 +149,19 @@ func (t *manualTrigger) Start(ctx context.Context) (<-chan bool, error) {
 // notifyTrigger watches for changes with fsnotify
 type fsNotifyTrigger struct {
 	Interval time.Duration
+
+	// Ignorer is set by the watcher.
+	Ignorer *Ignorer
+
+	// Changes are the changes since the last trigger.
+	Changes *pathSet
+}
+
+// pathSet records the changed paths.
+type pathSet struct {
+	lock    sync.Mutex
+	paths   map[string]bool
+	unknown bool
 }
 
 // Debounce tells the watcher to not debounce rapid sequence of changes.
@@ -155,6 +177,7 @@ func (t *fsNotifyTrigger) WatchForChanges(out io.Writer) {
 // Start listening for file system changes
 func (t *fsNotifyTrigger) Start(ctx context.Context) (<-chan bool, error) {
 	c := make(chan notify.EventInfo, 100)
+	t.Changes = &pathSet{}
 
 	// Watch current directory recursively
 	if err := notify.Watch("./...", c, notify.All); err != nil {
@@ -173,7 +196,12 @@ func (t *fsNotifyTrigger) Start(ctx context.Context) (<-chan bool, error) {
 		for {
 			select {
 			case e := <-c:
+				if e != nil && t.Ignorer.Ignored(e.Path()) {
+					logrus.Debugln("Ignoring change", e)
+					continue
+				}
 				logrus.Debugln("Change detected", e)
+				t.Changes.add(e)
 
 				// Wait t.interval before triggering.
 				// This way, rapid stream of events will be grouped.
@@ -182,6 +210,7 @@ func (t *fsNotifyTrigger) Start(ctx context.Context) (<-chan bool, error) {
 				trigger <- true
 			case <-ctx.Done():
 				timer.Stop()
+				notify.Stop(c)
 				return
 			}
 		}
@@ -190,6 +219,51 @@ func (t *fsNotifyTrigger) Start(ctx context.Context) (<-chan bool, error) {
 	return trigger, nil
 }
 
+// ChangedPaths returns the paths changed since the last call.
+func (t *fsNotifyTrigger) ChangedPaths() ([]string, bool) {
+	if t.Changes == nil {
+		return nil, false
+	}
+	return t.Changes.reset()
+}
+
+// add records a changed path. A nil event means that the changes are unknown.
+func (s *pathSet) add(e notify.EventInfo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if e == nil {
+		s.unknown = true
+		return
+	}
+	if s.paths == nil {
+		s.paths = map[string]bool{}
+	}
+	s.paths[e.Path()] = true
+}
+
+// reset returns the changed paths, sorted, and forgets them.
+func (s *pathSet) reset() ([]string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	defer func() {
+		s.paths = nil
+		s.unknown = false
+	}()
+
+	if s.unknown {
+		return nil, false
+	}
+
+	var paths []string
+	for path := range s.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, true
+}
+
 type apiTrigger struct {
 	Trigger chan bool
 }
@@ -37,12 +37,15 @@ type Watcher interface {
 type watchList struct {
 	components []*component
 	trigger    Trigger
+	ignorer    *Ignorer
 }
 
-// NewWatcher creates a new Watcher.
-func NewWatcher(trigger Trigger) Watcher {
+// NewWatcher creates a new Watcher. Changes to the files ignored by the
+// ignorer don't trigger anything.
+func NewWatcher(trigger Trigger, ignorer *Ignorer) Watcher {
 	return &watchList{
 		trigger: trigger,
+		ignorer: ignorer,
 	}
 }
 
@@ -50,20 +53,28 @@ type component struct {
 	deps     func() ([]string, error)
 	onChange func(Events)
 	state    FileMap
-	events   Events
+	// dirs are the directories that contain the dependencies.
+	dirs   FileMap
+	events Events
 }
 
 // Register adds a new component to the watch list.
 func (w *watchList) Register(deps func() ([]string, error), onChange func(Events)) error {
-	state, err := Stat(deps)
+	state, err := Stat(w.ignorer.Filtered(deps))
 	if err != nil {
 		return errors.Wrap(err, "listing files")
 	}
 
+	dirs, err := statDirs(state, w.ignorer)
+	if err != nil {
+		return errors.Wrap(err, "listing directories")
+	}
+
 	w.components = append(w.components, &component{
 		deps:     deps,
 		onChange: onChange,
 		state:    state,
+		dirs:     dirs,
 	})
 	return nil
 }
@@ -73,6 +84,10 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 	ctxTrigger, cancelTrigger := context.WithCancel(ctx)
 	defer cancelTrigger()
 
+	if notifyTrigger, ok := w.trigger.(*fsNotifyTrigger); ok {
+		notifyTrigger.Ignorer = w.ignorer
+	}
+
 	t, err := w.trigger.Start(ctxTrigger)
 	if err != nil {
 		if notifyTrigger, ok := w.trigger.(*fsNotifyTrigger); ok {
@@ -96,9 +111,15 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 		case <-ctx.Done():
 			return nil
 		case <-t:
+			changedPaths, known := w.changedPaths()
+
 			changed := 0
 			for i, component := range w.components {
-				state, err := Stat(component.deps)
+				if known && !component.concerns(changedPaths) {
+					continue
+				}
+
+				state, err := w.refresh(component)
 				if err != nil {
 					return errors.Wrap(err, "listing files")
 				}
@@ -111,7 +132,6 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 					changed++
 				}
 			}
-
 			// Rapid file changes that are more frequent than the poll interval would trigger
 			// multiple rebuilds.
 			// To prevent that, we debounce changes that happen too quickly
@@ -135,3 +155,11 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 		}
 	}
 }
+
+// changedPaths returns the absolute paths that changed since the last call, if the trigger knows them.
+func (w *watchList) changedPaths() ([]string, bool) {
+	if tracker, ok := w.trigger.(pathTracker); ok {
+		return tracker.ChangedPaths()
+	}
+	return nil, false
+}
@@ -72,7 +72,7 @@ func testWatch(t *testing.T, trigger Trigger) {
 			somethingChanged := newCallback()
 
 			// Watch folder
-			watcher := NewWatcher(trigger)
+			watcher := NewWatcher(trigger, nil)
 			err := watcher.Register(tmpDir.List, folderChanged.call)
 			t.CheckNoError(err)
 
@@ -122,3 +122,29 @@ func (c *callback) callNoErr() error {
 func (c *callback) wait() {
 	c.wg.Wait()
 }
+
+func TestRegisterIgnored(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.NewTempDir().
+			Write(".gitignore", "node_modules/").
+			Touch("index.js", "src/app.js", "src/lib/util.js", "node_modules/lib/index.js").
+			Chdir()
+
+		ignorer, err := NewIgnorer(".")
+		t.CheckNoError(err)
+
+		watcher := NewWatcher(&pollTrigger{}, ignorer).(*watchList)
+		err = watcher.Register(func() ([]string, error) {
+			return []string{"index.js", "src/app.js", "node_modules/lib/index.js"}, nil
+		}, func(Events) {})
+		t.CheckNoError(err)
+		err = watcher.Register(func() ([]string, error) {
+			return []string{"src/lib/util.js"}, nil
+		}, func(Events) {})
+		t.CheckNoError(err)
+
+		t.CheckDeepEqual([]string{"index.js", "src/app.js"}, sortedPaths(watcher.components[0].state))
+		t.CheckDeepEqual([]string{".", "src", "src/lib"}, sortedPaths(watcher.components[0].dirs))
+		t.CheckDeepEqual([]string{"src/lib"}, sortedPaths(watcher.components[1].dirs))
+	})
+}